@@ -9,12 +9,24 @@ import (
 	"path/filepath"
 	"spotiflac/backend"
 	"strings"
+	"sync"
 	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
 // App struct
 type App struct {
-	ctx context.Context
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	shutdownOnce sync.Once
+
+	analysisMu     sync.Mutex
+	analysisCancel context.CancelFunc
+
+	duplicateMu     sync.Mutex
+	duplicateCancel context.CancelFunc
 }
 
 // NewApp creates a new App application struct
@@ -25,7 +37,16 @@ func NewApp() *App {
 // startup is called when the app starts. The context is saved
 // so we can call the runtime methods
 func (a *App) startup(ctx context.Context) {
-	a.ctx = ctx
+	a.ctx, a.cancel = context.WithCancel(ctx)
+	backend.SetProgressEventContext(a.ctx)
+
+	if settingsPath, err := backend.GetSettingsPath(); err == nil {
+		if err := backend.LoadSettings(settingsPath); err != nil {
+			fmt.Printf("Warning: Failed to load settings: %v\n", err)
+		}
+	}
+
+	a.RestoreDownloadQueue()
 }
 
 // SpotifyMetadataRequest represents the request structure for fetching Spotify metadata
@@ -62,7 +83,28 @@ type DownloadRequest struct {
 	ItemID               string `json:"item_id,omitempty"`                 // Optional queue item ID for multi-service fallback tracking
 	SpotifyTrackNumber   int    `json:"spotify_track_number,omitempty"`    // Track number from Spotify album
 	SpotifyDiscNumber    int    `json:"spotify_disc_number,omitempty"`     // Disc number from Spotify album
+	SpotifyTotalDiscs    int    `json:"spotify_total_discs,omitempty"`     // Total discs in album from Spotify
 	SpotifyTotalTracks   int    `json:"spotify_total_tracks,omitempty"`    // Total tracks in album from Spotify
+	EmbedProvenanceTags  bool   `json:"embed_provenance_tags,omitempty"`   // Write SPOTIFY_ID, SOURCE_SERVICE and DOWNLOAD_DATE tags
+	DeferCoverEmbed      bool   `json:"defer_cover_embed,omitempty"`       // Embed cover in the post-processing pool instead of during download
+	PreferLocalLyrics    bool   `json:"prefer_local_lyrics,omitempty"`     // Embed an existing .lrc sidecar instead of fetching lyrics online
+	ForceReembed         bool   `json:"force_reembed,omitempty"`           // Fetch and embed cover/lyrics even if the downloaded file already has them
+	SkipFinalMove        bool   `json:"skip_final_move,omitempty"`         // Leave the file in OutputDir even when an import folder is set
+	TempDownload         bool   `json:"temp_download,omitempty"`           // Download into OutputDir as-is, ignoring the per-item folder and skipping database and history records
+	SyncedLyricsOnly     bool   `json:"synced_only,omitempty"`             // Embed lyrics only when synced lyrics are available
+	PlainLyricsSidecar   bool   `json:"plain_lyrics_sidecar,omitempty"`    // In synced-only mode, save skipped plain lyrics as .txt
+	AlbumID              string `json:"album_id,omitempty"`                // Spotify album ID, keys the shared album cover
+	ShareAlbumCover      bool   `json:"share_album_cover,omitempty"`       // Fetch the album cover once and reuse it for every track and cover.jpg
+	Source               string `json:"source,omitempty"`                  // "playlist" or "album"; overrides UseAlbumTrackNumber for this item
+	EmbedSourceURL       bool   `json:"embed_source_url,omitempty"`        // Write the service or song.link URL as SOURCE_URL (FLAC/M4A) or WOAF (MP3)
+	WriteLRCSidecar      bool   `json:"write_lrc_sidecar,omitempty"`       // Also save the lyrics as a .lrc named like the audio file
+	OverwriteLRCSidecar  bool   `json:"overwrite_lrc_sidecar,omitempty"`   // Replace an existing .lrc sidecar
+	Genre                string `json:"genre,omitempty"`                   // Genres to tag, separated by ";" or ","; looked up when empty and EmbedGenre is set
+	EmbedGenre           bool   `json:"embed_genre,omitempty"`             // Look up genres from Spotify or MusicBrainz and write GENRE tags
+	ArtistID             string `json:"artist_id,omitempty"`               // Spotify artist ID, for the genre lookup
+
+	// Services to try in order until one succeeds, e.g. ["qobuz", "tidal", "amazon"]; overrides Service
+	ServicePriority []string `json:"service_priority,omitempty"`
 }
 
 // DownloadResponse represents the response structure for download operations
@@ -73,6 +115,46 @@ type DownloadResponse struct {
 	Error         string `json:"error,omitempty"`
 	AlreadyExists bool   `json:"already_exists,omitempty"`
 	ItemID        string `json:"item_id,omitempty"` // Queue item ID for tracking
+	Service       string `json:"service,omitempty"` // Service that produced the file
+
+	TrackNumberMismatch bool   `json:"track_number_mismatch,omitempty"`
+	ServiceTrackNumber  int    `json:"service_track_number,omitempty"`
+	ServicePaused       bool   `json:"service_paused,omitempty"` // Service skipped because its circuit breaker is open
+	ISRCCorrected       bool   `json:"isrc_corrected,omitempty"` // Service ISRC was replaced with the Spotify ISRC
+	CoverWidth          int    `json:"cover_width,omitempty"`    // Dimensions of the embedded cover, when embedded during download
+	CoverHeight         int    `json:"cover_height,omitempty"`
+	CoverSource         string `json:"cover_source,omitempty"` // Where the cover URL came from: "database", "spotify", ...
+
+	// Post-download steps skipped because the file already had valid art or lyrics
+	EnrichSkipped []string `json:"enrich_skipped,omitempty"`
+
+	// Set when the filename format was changed because another track on the album has the same title
+	FilenameAdjustment string `json:"filename_adjustment,omitempty"`
+
+	// Where the file is moved once post-processing finishes, when an import folder is set
+	FinalPath string `json:"final_path,omitempty"`
+
+	// Tidal API that served the file and the quality it reported
+	TidalAPI *backend.TidalAPIChoice `json:"tidal_api,omitempty"`
+
+	// Problems that didn't fail the audio download. Background cover/lyrics failures come later,
+	// on the queue item's warnings.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// GetTrackQualityOptions reports the best quality each service offers for a track, cached per ISRC
+func (a *App) GetTrackQualityOptions(spotifyID, isrc string) (map[string]backend.QualityInfo, error) {
+	return backend.GetTrackQualityOptions(spotifyID, isrc)
+}
+
+// GetLyricsDecisions returns whether each enriched track got synced, plain or no embedded lyrics
+func (a *App) GetLyricsDecisions() []backend.LyricsDecision {
+	return backend.GetLyricsDecisions()
+}
+
+// ClearLyricsDecisions resets the recorded lyrics decisions
+func (a *App) ClearLyricsDecisions() {
+	backend.ClearLyricsDecisions()
 }
 
 // GetStreamingURLs fetches all streaming URLs from song.link API
@@ -96,6 +178,16 @@ func (a *App) GetStreamingURLs(spotifyTrackID string) (string, error) {
 	return string(jsonData), nil
 }
 
+// GetPlaylistFetchProgress returns how many tracks of a large playlist have been fetched so far
+func (a *App) GetPlaylistFetchProgress() backend.PlaylistFetchProgress {
+	return backend.GetPlaylistFetchProgress()
+}
+
+// ClassifySpotifyURL reports a Spotify link's type and whether it can be downloaded
+func (a *App) ClassifySpotifyURL(url string) backend.SpotifyURLInfo {
+	return backend.ClassifySpotifyURL(url)
+}
+
 // GetSpotifyMetadata fetches metadata from Spotify
 func (a *App) GetSpotifyMetadata(req SpotifyMetadataRequest) (string, error) {
 	if req.URL == "" {
@@ -114,6 +206,10 @@ func (a *App) GetSpotifyMetadata(req SpotifyMetadataRequest) (string, error) {
 
 	data, err := backend.GetFilteredSpotifyData(ctx, req.URL, req.Batch, time.Duration(req.Delay*float64(time.Second)))
 	if err != nil {
+		// Podcast/audiobook links get their own message instead of a generic fetch failure
+		if backend.IsUnsupportedSpotifyType(err) {
+			return "", err
+		}
 		return "", fmt.Errorf("failed to fetch metadata: %v", err)
 	}
 
@@ -125,11 +221,43 @@ func (a *App) GetSpotifyMetadata(req SpotifyMetadataRequest) (string, error) {
 	return string(jsonData), nil
 }
 
+// GetEpisodeMetadata fetches a podcast episode's show, title, description, duration and publish date
+func (a *App) GetEpisodeMetadata(spotifyEpisodeID string) (backend.EpisodeMeta, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	meta, err := backend.GetEpisodeMetadata(ctx, spotifyEpisodeID)
+	if err != nil {
+		return backend.EpisodeMeta{}, err
+	}
+	return *meta, nil
+}
+
+// SaveEpisodeDescription archives a podcast episode's metadata and description as a .txt file in outputDir
+func (a *App) SaveEpisodeDescription(spotifyEpisodeID, outputDir string) (string, error) {
+	if outputDir == "" {
+		outputDir = backend.GetSettings().DownloadPath
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	meta, err := backend.GetEpisodeMetadata(ctx, spotifyEpisodeID)
+	if err != nil {
+		return "", err
+	}
+	return backend.SaveEpisodeDescription(meta, outputDir)
+}
+
 // GetISRCRequest represents a request to get ISRC for a track
 type GetISRCRequest struct {
 	SpotifyID    string `json:"spotify_id"`
 	DatabasePath string `json:"database_path"` // Optional: path to local database
 	SpotifyURL   string `json:"spotify_url"`   // Fallback: Spotify URL if database lookup fails
+
+	// Optional: pick the right database row by album, then release year, when the ID has several ISRCs
+	AlbumName   string `json:"album_name,omitempty"`
+	ReleaseDate string `json:"release_date,omitempty"`
 }
 
 // GetISRCResponse represents the response with ISRC data
@@ -150,7 +278,18 @@ func (a *App) GetISRCWithFallback(req GetISRCRequest) (GetISRCResponse, error) {
 	// Step 1: Try database first if path is provided
 	if req.DatabasePath != "" {
 		fmt.Printf("[GetISRCWithFallback] Checking database for Spotify ID: %s\n", req.SpotifyID)
-		isrc, err := backend.GetISRCFromDatabase(req.DatabasePath, req.SpotifyID)
+		var isrc string
+		var err error
+		if req.AlbumName != "" || req.ReleaseDate != "" {
+			// Disambiguate re-releases recorded under one ID instead of taking an arbitrary row
+			var matches []backend.ISRCMatch
+			matches, err = backend.GetAllISRCsForSpotifyID(req.DatabasePath, req.SpotifyID)
+			if match, ok := backend.PickISRCMatch(matches, req.AlbumName, req.ReleaseDate); ok {
+				isrc = match.ISRC
+			}
+		} else {
+			isrc, err = backend.GetISRCFromDatabase(req.DatabasePath, req.SpotifyID)
+		}
 
 		if err != nil {
 			// Database error (file not found, connection error, etc.) - log but continue to API
@@ -212,6 +351,86 @@ func (a *App) GetISRCWithFallback(req GetISRCRequest) (GetISRCResponse, error) {
 	}, nil
 }
 
+// GetAllISRCsForSpotifyID lists every ISRC the local database has for a Spotify ID
+func (a *App) GetAllISRCsForSpotifyID(databasePath, spotifyID string) ([]backend.ISRCMatch, error) {
+	return backend.GetAllISRCsForSpotifyID(databasePath, spotifyID)
+}
+
+// GetSpotifyIDFromISRC returns the first Spotify ID the local database has for an ISRC
+func (a *App) GetSpotifyIDFromISRC(databasePath, isrc string) (string, error) {
+	return backend.GetSpotifyIDFromISRC(databasePath, isrc)
+}
+
+// GetSpotifyIDsForISRC lists every Spotify ID the local database has for an ISRC
+func (a *App) GetSpotifyIDsForISRC(databasePath, isrc string) ([]backend.ISRCMatch, error) {
+	return backend.GetSpotifyIDsForISRC(databasePath, isrc)
+}
+
+// BatchISRCRequest asks for the ISRCs of several tracks in one call
+type BatchISRCRequest struct {
+	Tracks          []GetISRCRequest `json:"tracks"`
+	DatabasePath    string           `json:"database_path"`               // Used for tracks that don't set their own
+	ContinueOnError *bool            `json:"continue_on_error,omitempty"` // Keep going after a failed track (default true)
+}
+
+// BatchTrackError is one track that couldn't be resolved in a batch
+type BatchTrackError struct {
+	Index     int    `json:"index"`
+	SpotifyID string `json:"spotify_id"`
+	Error     string `json:"error"`
+}
+
+// BatchISRCResponse holds one result per requested track plus every failure in one list
+type BatchISRCResponse struct {
+	Results  []GetISRCResponse `json:"results"`
+	Errors   []BatchTrackError `json:"errors"`
+	Resolved int               `json:"resolved"`
+	Stopped  bool              `json:"stopped,omitempty"` // A failure ended the batch early because continue_on_error was false
+	Success  bool              `json:"success"`
+	Error    string            `json:"error,omitempty"`
+}
+
+// GetISRCsWithFallback resolves ISRCs for a batch of tracks, recording failed tracks instead of aborting the batch
+func (a *App) GetISRCsWithFallback(req BatchISRCRequest) BatchISRCResponse {
+	continueOnError := req.ContinueOnError == nil || *req.ContinueOnError
+
+	resp := BatchISRCResponse{
+		Results: make([]GetISRCResponse, 0, len(req.Tracks)),
+		Errors:  make([]BatchTrackError, 0),
+	}
+	for i, track := range req.Tracks {
+		if track.DatabasePath == "" {
+			track.DatabasePath = req.DatabasePath
+		}
+
+		result, err := a.GetISRCWithFallback(track)
+		resp.Results = append(resp.Results, result)
+		if err == nil {
+			resp.Resolved++
+			continue
+		}
+
+		message := result.Error
+		if message == "" {
+			message = err.Error()
+		}
+		fmt.Printf("[GetISRCsWithFallback] Track %d (%s) failed: %s\n", i+1, track.SpotifyID, message)
+		resp.Errors = append(resp.Errors, BatchTrackError{Index: i, SpotifyID: track.SpotifyID, Error: message})
+
+		if !continueOnError {
+			resp.Stopped = true
+			break
+		}
+	}
+
+	resp.Success = len(resp.Errors) == 0
+	if !resp.Success {
+		resp.Error = fmt.Sprintf("%d of %d tracks failed", len(resp.Errors), len(req.Tracks))
+	}
+	fmt.Printf("[GetISRCsWithFallback] Resolved %d/%d tracks\n", resp.Resolved, len(req.Tracks))
+	return resp
+}
+
 // TestDatabaseConnection tests if a database file is accessible and properly formatted
 func (a *App) TestDatabaseConnection(databasePath string) (string, error) {
 	if databasePath == "" {
@@ -226,6 +445,46 @@ func (a *App) TestDatabaseConnection(databasePath string) (string, error) {
 	return "Database connection successful!", nil
 }
 
+// RecordToDatabase inserts or updates a track in the local database, creating the schema if the file is new
+func (a *App) RecordToDatabase(databasePath string, meta backend.TrackMeta) error {
+	return backend.RecordToDatabase(databasePath, meta)
+}
+
+// BackupDatabase creates a verified copy of the SQLite database using the online backup API
+func (a *App) BackupDatabase(src, dest string) error {
+	return backend.BackupDatabase(src, dest)
+}
+
+// RestoreDatabase restores a SQLite database from a backup file after verifying it
+func (a *App) RestoreDatabase(backupPath, dest string) error {
+	return backend.RestoreDatabase(backupPath, dest)
+}
+
+// ResolveTrackVersion picks the album or single release of a track ("album", "single", or empty to keep it)
+func (a *App) ResolveTrackVersion(spotifyID string, prefer string) (*backend.TrackVersionResult, error) {
+	if spotifyID == "" {
+		return nil, fmt.Errorf("spotify ID is required")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	return backend.ResolveTrackVersion(ctx, spotifyID, prefer)
+}
+
+// ResolveTrackEdition picks an album edition of a track ("original", "remastered", "deluxe" or a keyword),
+// optionally preferring the edition released closest to preferYear
+func (a *App) ResolveTrackEdition(spotifyID string, prefer string, preferYear int) (*backend.TrackEditionResult, error) {
+	if spotifyID == "" {
+		return nil, fmt.Errorf("spotify ID is required")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	return backend.ResolveTrackEdition(ctx, spotifyID, prefer, preferYear)
+}
+
 // SpotifySearchRequest represents the request structure for searching Spotify
 type SpotifySearchRequest struct {
 	Query string `json:"query"`
@@ -276,8 +535,57 @@ func (a *App) SearchSpotifyByType(req SpotifySearchByTypeRequest) ([]backend.Sea
 	return backend.SearchSpotifyByType(ctx, req.Query, req.SearchType, req.Limit, req.Offset)
 }
 
+// applyPrefetchedTrack fills request fields the frontend left empty from what the queue prefetch resolved
+func applyPrefetchedTrack(req *DownloadRequest, track backend.TrackMetadata) {
+	fill := func(dst *string, value string) {
+		if *dst == "" {
+			*dst = value
+		}
+	}
+	fill(&req.ISRC, track.ISRC)
+	fill(&req.TrackName, track.Name)
+	fill(&req.ArtistName, track.Artists)
+	fill(&req.AlbumName, track.AlbumName)
+	fill(&req.AlbumArtist, track.AlbumArtist)
+	fill(&req.ReleaseDate, track.ReleaseDate)
+	fill(&req.CoverURL, track.Images)
+	if req.Duration == 0 {
+		req.Duration = track.DurationMS / 1000
+	}
+	if req.SpotifyTrackNumber == 0 {
+		req.SpotifyTrackNumber = track.TrackNumber
+	}
+	if req.SpotifyDiscNumber == 0 {
+		req.SpotifyDiscNumber = track.DiscNumber
+	}
+	if req.SpotifyTotalTracks == 0 {
+		req.SpotifyTotalTracks = track.TotalTracks
+	}
+}
+
 // DownloadTrack downloads a track by ISRC
 func (a *App) DownloadTrack(req DownloadRequest) (DownloadResponse, error) {
+	// The prefetch stage may already have resolved this item while the previous one downloaded
+	if req.ItemID != "" {
+		if prefetched, ok := backend.TakePrefetchedTrack(req.ItemID); ok {
+			applyPrefetchedTrack(&req, prefetched.Track)
+		}
+	}
+
+	// Text and M3U playlists only carry Spotify links, so look the ISRC up when nothing prefetched it
+	if req.ISRC == "" && req.SpotifyID != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		data, err := backend.GetFilteredSpotifyData(ctx, "https://open.spotify.com/track/"+req.SpotifyID, false, 0)
+		cancel()
+		if track, ok := data.(backend.TrackResponse); err == nil && ok {
+			applyPrefetchedTrack(&req, track.Track)
+		}
+	}
+
+	if len(req.ServicePriority) > 0 {
+		return a.downloadWithServicePriority(req)
+	}
+
 	if req.ISRC == "" {
 		return DownloadResponse{
 			Success: false,
@@ -301,7 +609,7 @@ func (a *App) DownloadTrack(req DownloadRequest) (DownloadResponse, error) {
 	}
 
 	var err error
-	var filename string
+	var result backend.DownloadResult
 
 	// Set default filename format if not provided
 	if req.FilenameFormat == "" {
@@ -317,10 +625,55 @@ func (a *App) DownloadTrack(req DownloadRequest) (DownloadResponse, error) {
 		backend.AddToQueue(itemID, req.TrackName, req.ArtistName, req.AlbumName, req.ISRC)
 	}
 
+	if req.SpotifyID != "" {
+		backend.SetItemSpotifyID(itemID, req.SpotifyID)
+	}
+
+	// A per-item destination set when queueing overrides the batch folder
+	if itemOutputDir := backend.GetItemOutputDir(itemID); itemOutputDir != "" && !req.TempDownload {
+		req.OutputDir = itemOutputDir
+	}
+
+	// Resolve numbering per item so playlist and album tracks in one batch each get the right number
+	source := req.Source
+	if source == "" {
+		source = backend.GetItemSource(itemID)
+	}
+	req.UseAlbumTrackNumber = backend.ResolveUseAlbumTrackNumber(source, req.UseAlbumTrackNumber)
+	filenamePosition := backend.FilenameTrackPosition(req.Position, req.SpotifyTrackNumber, req.UseAlbumTrackNumber)
+
+	// Another track of this album already has this title: number this one so it doesn't overwrite or skip
+	var filenameAdjustment string
+	if backend.ClaimAlbumTrackTitle(req.AlbumID, req.AlbumName, req.AlbumArtist, req.SpotifyID, req.TrackName) {
+		if filenamePosition == 0 && req.SpotifyTrackNumber > 0 {
+			req.UseAlbumTrackNumber = true
+			filenamePosition = req.SpotifyTrackNumber
+		}
+		if filenamePosition > 0 {
+			adjusted := backend.CollisionSafeFilenameFormat(req.FilenameFormat, req.SpotifyDiscNumber)
+			if adjusted != req.FilenameFormat || !req.TrackNumber {
+				filenameAdjustment = fmt.Sprintf("another track on this album is also titled %q, filename format changed to %q", req.TrackName, adjusted)
+				fmt.Printf("[Download] %s\n", filenameAdjustment)
+				req.FilenameFormat = adjusted
+				req.TrackNumber = true
+			}
+		}
+	}
+
 	// Mark item as downloading immediately
 	backend.SetDownloading(true)
 	backend.StartDownloadItem(itemID)
 	defer backend.SetDownloading(false)
+	backend.TriggerQueuePrefetch()
+
+	// With concurrent workers, a second item with this ISRC waits here and then finds the first one's file
+	releaseISRC := backend.LockISRCDownload(req.OutputDir, req.ISRC)
+	defer releaseISRC()
+	if req.TrackName != "" && req.ArtistName != "" {
+		expectedFilename := backend.BuildExpectedFilename(req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, req.FilenameFormat, req.TrackNumber, filenamePosition, req.SpotifyDiscNumber, req.UseAlbumTrackNumber)
+		backend.SetItemDownloadPath(itemID, filepath.Join(req.OutputDir, expectedFilename))
+		defer backend.SetItemDownloadPath(itemID, "")
+	}
 
 	// Early check: Check if file with same ISRC already exists
 	if existingFile, exists := backend.CheckISRCExists(req.OutputDir, req.ISRC); exists {
@@ -336,8 +689,9 @@ func (a *App) DownloadTrack(req DownloadRequest) (DownloadResponse, error) {
 	}
 
 	// Fallback: if we have track metadata, check if file already exists by filename
+	var corruptPaths []string
 	if req.TrackName != "" && req.ArtistName != "" {
-		expectedFilename := backend.BuildExpectedFilename(req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, req.FilenameFormat, req.TrackNumber, req.Position, req.SpotifyDiscNumber, req.UseAlbumTrackNumber)
+		expectedFilename := backend.BuildExpectedFilename(req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, req.FilenameFormat, req.TrackNumber, filenamePosition, req.SpotifyDiscNumber, req.UseAlbumTrackNumber)
 		expectedPath := filepath.Join(req.OutputDir, expectedFilename)
 
 		if fileInfo, err := os.Stat(expectedPath); err == nil && fileInfo.Size() > 100*1024 {
@@ -353,21 +707,75 @@ func (a *App) DownloadTrack(req DownloadRequest) (DownloadResponse, error) {
 					ItemID:        itemID,
 				}, nil
 			} else {
-				// File exists but has no valid ISRC metadata - it's corrupted, delete it
-				fmt.Printf("Removing corrupted file (no valid ISRC metadata): %s\n", expectedPath)
-				if removeErr := os.Remove(expectedPath); removeErr != nil {
-					fmt.Printf("Warning: Failed to remove corrupted file %s: %v\n", expectedPath, removeErr)
-				}
+				// File exists but has no valid ISRC metadata - it's corrupted, replace it
+				fmt.Printf("Replacing corrupted file (no valid ISRC metadata): %s\n", expectedPath)
+				corruptPaths = append(corruptPaths, expectedPath)
 			}
 		}
 	}
 
+	// Old copies are only removed once their replacement has downloaded
+	downloaded := false
+	finishRedownload := backend.SetAsideForRedownload(req.OutputDir, req.ISRC, corruptPaths...)
+	defer func() { finishRedownload(downloaded) }()
+
+	// Don't hammer a service whose breaker has tripped; the frontend can fall through to the next one
+	if err := backend.CheckServiceAvailable(req.Service); err != nil {
+		fmt.Printf("Skipping %s: %v\n", req.Service, err)
+		return DownloadResponse{
+			Success:       false,
+			Error:         err.Error(),
+			ItemID:        itemID,
+			ServicePaused: true,
+		}, err
+	}
+
+	// Reuse a song.link lookup from GetStreamingURLs to skip a redundant search for the chosen service
+	if req.ServiceURL == "" && req.SpotifyID != "" {
+		if urls, ok := backend.GetCachedSongLinkURLs(req.SpotifyID); ok {
+			if serviceURL := backend.PickServiceURL(urls.Platforms, req.Service); serviceURL != "" {
+				fmt.Printf("Using cached song.link URL for %s: %s\n", req.Service, serviceURL)
+				req.ServiceURL = serviceURL
+			}
+		}
+	}
+
+	// Pick the cover: database first when preferred, then the Spotify URL, then the same priority chain the library verifier uses
+	resolvedCover, coverSource := backend.ResolveDownloadCover(req.CoverURL, backend.CoverLookup{
+		Title:        req.TrackName,
+		Artist:       req.ArtistName,
+		Album:        req.AlbumName,
+		DatabasePath: backend.GetSettings().DatabasePath,
+		MaxQuality:   req.EmbedMaxQualityCover,
+	})
+	if resolvedCover != "" && resolvedCover != req.CoverURL {
+		fmt.Printf("Using cover from %s: %s\n", coverSource, resolvedCover)
+		req.CoverURL = resolvedCover
+	}
+
+	var warnings []string
+
+	// Fetch the album's cover once; later tracks of the same album reuse the cached bytes
+	if req.ShareAlbumCover && req.AlbumID != "" && req.CoverURL != "" {
+		if _, err := backend.PrepareSharedAlbumCover(req.AlbumID, req.CoverURL, req.EmbedMaxQualityCover); err != nil {
+			fmt.Printf("Warning: Failed to fetch shared album cover: %v\n", err)
+			warnings = append(warnings, fmt.Sprintf("shared album cover fetch failed: %v", err))
+		}
+	}
+
+	// Cover download can be moved out of the download path so the next track starts sooner
+	coverURL := req.CoverURL
+	if req.DeferCoverEmbed {
+		coverURL = ""
+		defer backend.DeferCover(req.ISRC)()
+	}
+
 	switch req.Service {
 	case "amazon":
 		downloader := backend.NewAmazonDownloader()
 		if req.ServiceURL != "" {
 			// Use provided URL directly
-			filename, err = downloader.DownloadByURL(req.ServiceURL, req.OutputDir, req.FilenameFormat, req.TrackNumber, req.Position, req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, req.CoverURL, req.ISRC, req.SpotifyTrackNumber, req.SpotifyDiscNumber, req.SpotifyTotalTracks, req.EmbedMaxQualityCover)
+			result, err = downloader.DownloadByURL(req.ServiceURL, req.OutputDir, req.FilenameFormat, req.TrackNumber, filenamePosition, req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, coverURL, req.ISRC, req.SpotifyTrackNumber, req.SpotifyDiscNumber, req.SpotifyTotalTracks, req.EmbedMaxQualityCover)
 		} else {
 			if req.SpotifyID == "" {
 				return DownloadResponse{
@@ -375,7 +783,7 @@ func (a *App) DownloadTrack(req DownloadRequest) (DownloadResponse, error) {
 					Error:   "Spotify ID is required for Amazon Music",
 				}, fmt.Errorf("spotify ID is required for Amazon Music")
 			}
-			filename, err = downloader.DownloadBySpotifyID(req.SpotifyID, req.OutputDir, req.FilenameFormat, req.TrackNumber, req.Position, req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, req.CoverURL, req.ISRC, req.SpotifyTrackNumber, req.SpotifyDiscNumber, req.SpotifyTotalTracks, req.EmbedMaxQualityCover)
+			result, err = downloader.DownloadBySpotifyID(req.SpotifyID, req.OutputDir, req.FilenameFormat, req.TrackNumber, filenamePosition, req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, coverURL, req.ISRC, req.SpotifyTrackNumber, req.SpotifyDiscNumber, req.SpotifyTotalTracks, req.EmbedMaxQualityCover)
 		}
 
 	case "tidal":
@@ -383,7 +791,7 @@ func (a *App) DownloadTrack(req DownloadRequest) (DownloadResponse, error) {
 			downloader := backend.NewTidalDownloader("")
 			if req.ServiceURL != "" {
 				// Use provided URL directly with fallback to multiple APIs
-				filename, err = downloader.DownloadByURLWithFallback(req.ServiceURL, req.OutputDir, req.AudioFormat, req.FilenameFormat, req.TrackNumber, req.Position, req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, req.UseAlbumTrackNumber, req.CoverURL, req.EmbedMaxQualityCover, req.SpotifyTrackNumber, req.SpotifyDiscNumber, req.SpotifyTotalTracks, req.ISRC)
+				result, err = downloader.DownloadByURLWithFallback(req.ServiceURL, req.OutputDir, req.AudioFormat, req.FilenameFormat, req.TrackNumber, filenamePosition, req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, req.UseAlbumTrackNumber, coverURL, req.EmbedMaxQualityCover, req.SpotifyTrackNumber, req.SpotifyDiscNumber, req.SpotifyTotalTracks, req.ISRC)
 			} else {
 				if req.SpotifyID == "" {
 					return DownloadResponse{
@@ -392,13 +800,13 @@ func (a *App) DownloadTrack(req DownloadRequest) (DownloadResponse, error) {
 					}, fmt.Errorf("spotify ID is required for Tidal")
 				}
 				// Use ISRC matching for search fallback
-				filename, err = downloader.DownloadWithFallbackAndISRC(req.SpotifyID, req.ISRC, req.OutputDir, req.AudioFormat, req.FilenameFormat, req.TrackNumber, req.Position, req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, req.UseAlbumTrackNumber, req.Duration, req.CoverURL, req.EmbedMaxQualityCover, req.SpotifyTrackNumber, req.SpotifyDiscNumber, req.SpotifyTotalTracks)
+				result, err = downloader.DownloadWithFallbackAndISRC(req.SpotifyID, req.ISRC, req.OutputDir, req.AudioFormat, req.FilenameFormat, req.TrackNumber, filenamePosition, req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, req.UseAlbumTrackNumber, req.Duration, coverURL, req.EmbedMaxQualityCover, req.SpotifyTrackNumber, req.SpotifyDiscNumber, req.SpotifyTotalTracks)
 			}
 		} else {
 			downloader := backend.NewTidalDownloader(req.ApiURL)
 			if req.ServiceURL != "" {
 				// Use provided URL directly with specific API
-				filename, err = downloader.DownloadByURL(req.ServiceURL, req.OutputDir, req.AudioFormat, req.FilenameFormat, req.TrackNumber, req.Position, req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, req.UseAlbumTrackNumber, req.CoverURL, req.EmbedMaxQualityCover, req.SpotifyTrackNumber, req.SpotifyDiscNumber, req.SpotifyTotalTracks, req.ISRC)
+				result, err = downloader.DownloadByURL(req.ServiceURL, req.OutputDir, req.AudioFormat, req.FilenameFormat, req.TrackNumber, filenamePosition, req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, req.UseAlbumTrackNumber, coverURL, req.EmbedMaxQualityCover, req.SpotifyTrackNumber, req.SpotifyDiscNumber, req.SpotifyTotalTracks, req.ISRC)
 			} else {
 				if req.SpotifyID == "" {
 					return DownloadResponse{
@@ -407,7 +815,7 @@ func (a *App) DownloadTrack(req DownloadRequest) (DownloadResponse, error) {
 					}, fmt.Errorf("spotify ID is required for Tidal")
 				}
 				// Use ISRC matching for search fallback
-				filename, err = downloader.DownloadWithISRC(req.SpotifyID, req.ISRC, req.OutputDir, req.AudioFormat, req.FilenameFormat, req.TrackNumber, req.Position, req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, req.UseAlbumTrackNumber, req.Duration, req.CoverURL, req.EmbedMaxQualityCover, req.SpotifyTrackNumber, req.SpotifyDiscNumber, req.SpotifyTotalTracks)
+				result, err = downloader.DownloadWithISRC(req.SpotifyID, req.ISRC, req.OutputDir, req.AudioFormat, req.FilenameFormat, req.TrackNumber, filenamePosition, req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, req.UseAlbumTrackNumber, req.Duration, coverURL, req.EmbedMaxQualityCover, req.SpotifyTrackNumber, req.SpotifyDiscNumber, req.SpotifyTotalTracks)
 			}
 		}
 
@@ -418,7 +826,7 @@ func (a *App) DownloadTrack(req DownloadRequest) (DownloadResponse, error) {
 		if quality == "" {
 			quality = "6"
 		}
-		filename, err = downloader.DownloadByISRC(req.ISRC, req.OutputDir, quality, req.FilenameFormat, req.TrackNumber, req.Position, req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, req.UseAlbumTrackNumber, req.CoverURL, req.EmbedMaxQualityCover, req.SpotifyTrackNumber, req.SpotifyDiscNumber, req.SpotifyTotalTracks)
+		result, err = downloader.DownloadByISRC(req.ISRC, req.OutputDir, quality, req.FilenameFormat, req.TrackNumber, filenamePosition, req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, req.UseAlbumTrackNumber, coverURL, req.EmbedMaxQualityCover, req.SpotifyTrackNumber, req.SpotifyDiscNumber, req.SpotifyTotalTracks)
 
 	default:
 		return DownloadResponse{
@@ -427,14 +835,18 @@ func (a *App) DownloadTrack(req DownloadRequest) (DownloadResponse, error) {
 		}, fmt.Errorf("unknown service: %s", req.Service)
 	}
 
+	if backend.RecordServiceResult(req.Service, err) && a.ctx != nil {
+		wailsRuntime.EventsEmit(a.ctx, "breaker:tripped", backend.GetBreakerStatus())
+	}
+
 	if err != nil {
 		// Clean up any partial/corrupted file that was created during failed download
-		if filename != "" && !strings.HasPrefix(filename, "EXISTS:") {
+		if result.Path != "" && !result.AlreadyExisted {
 			// Check if file exists and delete it
-			if _, statErr := os.Stat(filename); statErr == nil {
-				fmt.Printf("Removing corrupted/partial file after failed download: %s\n", filename)
-				if removeErr := os.Remove(filename); removeErr != nil {
-					fmt.Printf("Warning: Failed to remove corrupted file %s: %v\n", filename, removeErr)
+			if _, statErr := os.Stat(result.Path); statErr == nil {
+				fmt.Printf("Removing corrupted/partial file after failed download: %s\n", result.Path)
+				if removeErr := os.Remove(result.Path); removeErr != nil {
+					fmt.Printf("Warning: Failed to remove corrupted file %s: %v\n", result.Path, removeErr)
 				}
 			}
 		}
@@ -448,63 +860,61 @@ func (a *App) DownloadTrack(req DownloadRequest) (DownloadResponse, error) {
 		}, err
 	}
 
-	// Check if file already existed
-	alreadyExists := false
-	if strings.HasPrefix(filename, "EXISTS:") {
-		alreadyExists = true
-		filename = strings.TrimPrefix(filename, "EXISTS:")
-	}
-
-	// Embed lyrics after successful download (only for new downloads with Spotify ID and if embedLyrics is enabled)
-	if !alreadyExists && req.SpotifyID != "" && req.EmbedLyrics && strings.HasSuffix(filename, ".flac") {
-		go func(filePath, spotifyID, trackName, artistName string) {
-			fmt.Printf("\n========== LYRICS FETCH START ==========\n")
-			fmt.Printf("Spotify ID: %s\n", spotifyID)
-			fmt.Printf("Track: %s\n", trackName)
-			fmt.Printf("Artist: %s\n", artistName)
-			fmt.Println("Searching all sources...")
-
-			lyricsClient := backend.NewLyricsClient()
+	downloaded = true
+	filename := result.Path
+	alreadyExists := result.AlreadyExisted
 
-			// Try all sources with fallbacks
-			lyricsResp, source, err := lyricsClient.FetchLyricsAllSources(spotifyID, trackName, artistName)
-			if err != nil {
-				fmt.Printf("All sources failed: %v\n", err)
-				fmt.Printf("========== LYRICS FETCH END (FAILED) ==========\n\n")
-				return
-			}
+	// The service's own ISRC can differ from Spotify's (or be missing on URL downloads); dedup keys on Spotify's
+	isrcCorrected := false
+	if !alreadyExists {
+		corrected, err := backend.EnsureISRCTag(filename, req.ISRC)
+		if err != nil {
+			fmt.Printf("Warning: Failed to enforce ISRC tag: %v\n", err)
+		}
+		isrcCorrected = corrected
 
-			if lyricsResp == nil || len(lyricsResp.Lines) == 0 {
-				fmt.Println("No lyrics content found")
-				fmt.Printf("========== LYRICS FETCH END (FAILED) ==========\n\n")
-				return
-			}
+		if err := backend.EmbedDiscTotal(filename, req.SpotifyDiscNumber, req.SpotifyTotalDiscs); err != nil {
+			fmt.Printf("Warning: Failed to write disc total: %v\n", err)
+		}
+	}
 
-			fmt.Printf("Lyrics found from: %s\n", source)
-			fmt.Printf("Sync type: %s\n", lyricsResp.SyncType)
-			fmt.Printf("Total lines: %d\n", len(lyricsResp.Lines))
+	if req.ShareAlbumCover && req.AlbumID != "" {
+		if err := backend.WriteAlbumFolderCover(req.AlbumID, filepath.Dir(filename)); err != nil {
+			fmt.Printf("Warning: Failed to write album folder cover: %v\n", err)
+			warnings = append(warnings, fmt.Sprintf("album folder cover failed: %v", err))
+		}
+	}
 
-			lyrics := lyricsClient.ConvertToLRC(lyricsResp, trackName, artistName)
-			if lyrics == "" {
-				fmt.Println("No lyrics content to embed")
-				fmt.Printf("========== LYRICS FETCH END (FAILED) ==========\n\n")
-				return
+	// Record provenance tags before lyrics embedding starts rewriting the file in the background
+	if !alreadyExists && req.EmbedProvenanceTags && strings.HasSuffix(filename, ".flac") {
+		if err := backend.EmbedProvenanceTags(filename, req.SpotifyID, req.Service); err != nil {
+			fmt.Printf("Warning: Failed to embed provenance tags: %v\n", err)
+		}
+	}
+	if !alreadyExists && req.EmbedSourceURL {
+		sourceURL := req.ServiceURL
+		if sourceURL == "" {
+			sourceURL = backend.SongLinkURLForSpotifyID(req.SpotifyID)
+		}
+		if err := backend.EmbedSourceURL(filename, sourceURL); err != nil {
+			fmt.Printf("Warning: Failed to embed source URL: %v\n", err)
+		}
+	}
+	if !alreadyExists && (req.Genre != "" || req.EmbedGenre) {
+		genres := backend.SplitGenres(req.Genre)
+		if len(genres) == 0 {
+			var err error
+			if genres, err = backend.ResolveGenres(req.ArtistID, req.ArtistName); err != nil {
+				fmt.Printf("Warning: Failed to look up genres: %v\n", err)
 			}
+		}
+		if err := backend.EmbedGenres(filename, genres); err != nil {
+			fmt.Printf("Warning: Failed to embed genres: %v\n", err)
+		}
+	}
 
-			// Show full lyrics in console for debugging
-			fmt.Printf("\n--- Full LRC Content ---\n")
-			fmt.Println(lyrics)
-			fmt.Printf("--- End LRC Content ---\n\n")
-
-			fmt.Printf("Embedding into: %s\n", filePath)
-			if err := backend.EmbedLyricsOnly(filePath, lyrics); err != nil {
-				fmt.Printf("Failed to embed lyrics: %v\n", err)
-				fmt.Printf("========== LYRICS FETCH END (FAILED) ==========\n\n")
-			} else {
-				fmt.Printf("Lyrics embedded successfully!\n")
-				fmt.Printf("========== LYRICS FETCH END (SUCCESS) ==========\n\n")
-			}
-		}(filename, req.SpotifyID, req.TrackName, req.ArtistName)
+	if !alreadyExists && !req.TempDownload {
+		recordDownloadedTrack(req, filename)
 	}
 
 	message := "Download completed successfully"
@@ -522,15 +932,274 @@ func (a *App) DownloadTrack(req DownloadRequest) (DownloadResponse, error) {
 		}
 	}
 
-	return DownloadResponse{
+	// Hand cover and lyrics enrichment to the post-processing pool so the next download isn't blocked.
+	// Queued after the item is marked complete so the path set by a final move isn't overwritten.
+	var enrichSkipped []string
+	finalMoveDir := ""
+	if !req.SkipFinalMove {
+		finalMoveDir = backend.GetFinalMoveDir()
+	}
+	if !alreadyExists {
+		// Art or lyrics the source already embedded are kept unless a re-embed is forced
+		embedCover, embedLyrics := req.DeferCoverEmbed, req.EmbedLyrics
+		if !req.ForceReembed {
+			embedCover, embedLyrics, enrichSkipped = backend.SkipExistingEnrichment(filename, embedCover, embedLyrics)
+		}
+
+		backend.EnqueuePostProcess(backend.PostProcessJob{
+			FilePath:             filename,
+			SpotifyID:            req.SpotifyID,
+			TrackName:            req.TrackName,
+			ArtistName:           req.ArtistName,
+			AlbumName:            req.AlbumName,
+			CoverURL:             req.CoverURL,
+			EmbedCover:           embedCover,
+			EmbedMaxQualityCover: req.EmbedMaxQualityCover,
+			EmbedLyrics:          embedLyrics,
+			PreferLocalLyrics:    req.PreferLocalLyrics,
+			SyncedLyricsOnly:     req.SyncedLyricsOnly,
+			PlainLyricsSidecar:   req.PlainLyricsSidecar,
+			WriteLRCSidecar:      req.WriteLRCSidecar,
+			OverwriteLRCSidecar:  req.OverwriteLRCSidecar,
+			FinalMoveDir:         finalMoveDir,
+			BaseDir:              req.OutputDir,
+			ItemID:               itemID,
+		})
+	}
+
+	resp := DownloadResponse{
 		Success:       true,
 		Message:       message,
 		File:          filename,
 		AlreadyExists: alreadyExists,
 		ItemID:        itemID,
+		Service:       req.Service,
+		ISRCCorrected: isrcCorrected,
+		CoverSource:   coverSource,
+		EnrichSkipped: enrichSkipped,
+		Warnings:      warnings,
+	}
+	if mismatch, ok := backend.TakeTrackNumberMismatch(filename); ok {
+		resp.TrackNumberMismatch = true
+		resp.ServiceTrackNumber = mismatch.ServiceNumber
+	}
+	if size, ok := backend.TakeEmbeddedCoverSize(filename); ok {
+		resp.CoverWidth = size.Width
+		resp.CoverHeight = size.Height
+	}
+	if choice, ok := backend.TakeTidalAPIChoice(filename); ok {
+		resp.TidalAPI = &choice
+	}
+	if filenameAdjustment != "" {
+		resp.FilenameAdjustment = filenameAdjustment
+		resp.Warnings = append(resp.Warnings, filenameAdjustment)
+	}
+	if finalMoveDir != "" && !alreadyExists {
+		resp.FinalPath = backend.FinalMoveTarget(filename, req.OutputDir, finalMoveDir)
+	}
+	return resp, nil
+}
+
+// recordDownloadedTrack adds a finished download to the local database and the download history
+func recordDownloadedTrack(req DownloadRequest, filePath string) {
+	// Grow the local database from downloads so future ISRC and cover lookups work offline
+	if settings := backend.GetSettings(); settings.RecordToDatabase && settings.DatabasePath != "" && req.SpotifyID != "" {
+		if err := backend.RecordToDatabase(settings.DatabasePath, backend.TrackMeta{
+			SpotifyID:   req.SpotifyID,
+			Name:        req.TrackName,
+			Artists:     req.ArtistName,
+			ISRC:        req.ISRC,
+			DurationMs:  req.Duration * 1000,
+			TrackNumber: req.SpotifyTrackNumber,
+			DiscNumber:  req.SpotifyDiscNumber,
+			AlbumID:     req.AlbumID,
+			AlbumName:   req.AlbumName,
+			AlbumArtist: req.AlbumArtist,
+			ReleaseDate: req.ReleaseDate,
+			CoverURL:    req.CoverURL,
+		}); err != nil {
+			fmt.Printf("Warning: Failed to record track in database: %v\n", err)
+		}
+	}
+
+	// Global history across output folders, so a later download of the same ISRC can be flagged
+	if err := backend.RecordDownloadHistory(backend.HistoryEntry{
+		ISRC:       req.ISRC,
+		SpotifyID:  req.SpotifyID,
+		TrackName:  req.TrackName,
+		ArtistName: req.ArtistName,
+		Service:    req.Service,
+		FilePath:   filePath,
+	}); err != nil {
+		fmt.Printf("Warning: Failed to record download history: %v\n", err)
+	}
+}
+
+// downloadWithServicePriority runs DownloadTrack for each service in req.ServicePriority until one
+// succeeds. Every attempt repeats the ISRC check, so a file an earlier attempt left behind is reused.
+func (a *App) downloadWithServicePriority(req DownloadRequest) (DownloadResponse, error) {
+	services := req.ServicePriority
+	req.ServicePriority = nil
+
+	// All attempts report to the same queue item
+	if req.ItemID == "" {
+		req.ItemID = fmt.Sprintf("%s-%d", req.ISRC, time.Now().UnixNano())
+		backend.AddToQueue(req.ItemID, req.TrackName, req.ArtistName, req.AlbumName, req.ISRC)
+	}
+
+	serviceURL, serviceURLFor := req.ServiceURL, req.Service
+	var failures []string
+	var lastErr error
+	for _, service := range services {
+		attempt := req
+		attempt.Service = strings.ToLower(strings.TrimSpace(service))
+		// A direct service URL only applies to the service it was resolved for
+		if attempt.Service != serviceURLFor {
+			attempt.ServiceURL = ""
+		} else {
+			attempt.ServiceURL = serviceURL
+		}
+
+		fmt.Printf("[Fallback] Trying %s for %s\n", attempt.Service, req.TrackName)
+		resp, err := a.DownloadTrack(attempt)
+		if err == nil && resp.Success {
+			if len(failures) > 0 {
+				resp.Warnings = append(resp.Warnings, fmt.Sprintf("succeeded on %s after: %s", attempt.Service, strings.Join(failures, "; ")))
+			}
+			return resp, nil
+		}
+
+		message := resp.Error
+		if message == "" && err != nil {
+			message = err.Error()
+		}
+		failures = append(failures, fmt.Sprintf("%s: %s", attempt.Service, message))
+		lastErr = err
+	}
+
+	errorMsg := "all services failed: " + strings.Join(failures, "; ")
+	backend.FailDownloadItem(req.ItemID, errorMsg)
+	if lastErr == nil {
+		lastErr = fmt.Errorf("%s", errorMsg)
+	}
+	return DownloadResponse{
+		Success: false,
+		Error:   errorMsg,
+		ItemID:  req.ItemID,
+	}, lastErr
+}
+
+// ReplaceTrack downloads a new copy of a track and swaps it in for an existing file,
+// keeping its name and location and moving the old file to the trash folder
+func (a *App) ReplaceTrack(existingPath string, req DownloadRequest) (DownloadResponse, error) {
+	fmt.Printf("\n========== REPLACE TRACK START ==========\n")
+	fmt.Printf("Existing file: %s\n", existingPath)
+
+	existingPath = backend.NormalizePath(existingPath)
+	if info, err := os.Stat(existingPath); err != nil || info.IsDir() {
+		fmt.Printf("========== REPLACE TRACK END (FAILED) ==========\n\n")
+		return DownloadResponse{
+			Success: false,
+			Error:   "Existing file not found",
+		}, fmt.Errorf("existing file not found: %s", existingPath)
+	}
+
+	// Download next to the existing file so the final rename stays on the same volume
+	tempDir, err := os.MkdirTemp(filepath.Dir(existingPath), ".spotiflac-replace-")
+	if err != nil {
+		fmt.Printf("========== REPLACE TRACK END (FAILED) ==========\n\n")
+		return DownloadResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to create temp directory: %v", err),
+		}, err
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Lyrics enrichment and the records run after the swap, once the file has its final path
+	embedLyrics, writeLRCSidecar, plainLyricsSidecar := req.EmbedLyrics, req.WriteLRCSidecar, req.PlainLyricsSidecar
+	req.OutputDir = tempDir
+	req.EmbedLyrics = false
+	req.WriteLRCSidecar = false
+	req.PlainLyricsSidecar = false
+	req.DeferCoverEmbed = false
+	req.SkipFinalMove = true
+	req.TempDownload = true
+
+	resp, err := a.DownloadTrack(req)
+	if err != nil || !resp.Success {
+		fmt.Printf("========== REPLACE TRACK END (FAILED) ==========\n\n")
+		return resp, err
+	}
+
+	result, err := backend.ReplaceFile(existingPath, resp.File)
+	if err != nil {
+		fmt.Printf("========== REPLACE TRACK END (FAILED) ==========\n\n")
+		return DownloadResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Replace failed: %v", err),
+			ItemID:  resp.ItemID,
+		}, err
+	}
+
+	carriedLyrics := false
+	for _, tag := range result.CarriedTags {
+		if tag == "LYRICS" {
+			carriedLyrics = true
+		}
+	}
+	backend.SetItemFilePath(resp.ItemID, result.FilePath)
+	recordDownloadedTrack(req, result.FilePath)
+
+	backend.EnqueuePostProcess(backend.PostProcessJob{
+		FilePath:            result.FilePath,
+		SpotifyID:           req.SpotifyID,
+		TrackName:           req.TrackName,
+		ArtistName:          req.ArtistName,
+		AlbumName:           req.AlbumName,
+		EmbedLyrics:         embedLyrics && !carriedLyrics,
+		PreferLocalLyrics:   req.PreferLocalLyrics,
+		SyncedLyricsOnly:    req.SyncedLyricsOnly,
+		PlainLyricsSidecar:  plainLyricsSidecar,
+		WriteLRCSidecar:     writeLRCSidecar,
+		OverwriteLRCSidecar: req.OverwriteLRCSidecar,
+		ItemID:              resp.ItemID,
+	})
+
+	fmt.Printf("Old file moved to: %s\n", result.TrashedPath)
+	fmt.Printf("========== REPLACE TRACK END (SUCCESS) ==========\n\n")
+	return DownloadResponse{
+		Success: true,
+		Message: "Track replaced",
+		File:    result.FilePath,
+		ItemID:  resp.ItemID,
 	}, nil
 }
 
+// GetServiceBreakerStatus returns the circuit-breaker state of each download service
+func (a *App) GetServiceBreakerStatus() []backend.ServiceBreakerStatus {
+	return backend.GetBreakerStatus()
+}
+
+// ResumeService clears a tripped circuit breaker; an empty service resumes all of them
+func (a *App) ResumeService(service string) {
+	backend.ResumeService(service)
+}
+
+// GetFinalLocations returns where tracks were moved in the import folder, keyed by download path
+func (a *App) GetFinalLocations() map[string]string {
+	return backend.GetFinalLocations()
+}
+
+// GetCoverSourceThrottles returns the cover search sources that have been rate limiting requests
+func (a *App) GetCoverSourceThrottles() []backend.CoverSourceThrottle {
+	return backend.GetCoverSourceThrottles()
+}
+
+// WaitForPostProcessing blocks until queued cover and lyrics enrichment has finished
+func (a *App) WaitForPostProcessing() {
+	backend.WaitForPostProcessing()
+}
+
 // OpenFolder opens a folder in the file explorer
 func (a *App) OpenFolder(path string) error {
 	if path == "" {
@@ -567,6 +1236,16 @@ func (a *App) GetDefaults() map[string]string {
 	}
 }
 
+// GetSettings returns the persisted application settings
+func (a *App) GetSettings() backend.Settings {
+	return backend.GetSettings()
+}
+
+// UpdateSettings applies and persists new application settings
+func (a *App) UpdateSettings(settings backend.Settings) error {
+	return backend.UpdateSettings(settings)
+}
+
 // GetDownloadProgress returns current download progress
 func (a *App) GetDownloadProgress() backend.ProgressInfo {
 	return backend.GetDownloadProgress()
@@ -577,6 +1256,11 @@ func (a *App) GetDownloadQueue() backend.DownloadQueueInfo {
 	return backend.GetDownloadQueue()
 }
 
+// GetStateVersion returns a counter that changes whenever download progress or the queue changes
+func (a *App) GetStateVersion() int64 {
+	return backend.GetStateVersion()
+}
+
 // ClearCompletedDownloads clears completed, failed, and skipped items from the queue
 func (a *App) ClearCompletedDownloads() {
 	backend.ClearDownloadQueue()
@@ -594,20 +1278,175 @@ func (a *App) AddToDownloadQueue(isrc, trackName, artistName, albumName string)
 	return itemID
 }
 
+// AddToDownloadQueueWithOutputDir adds a track to the queue with its own destination folder
+func (a *App) AddToDownloadQueueWithOutputDir(isrc, trackName, artistName, albumName, outputDir string) string {
+	itemID := a.AddToDownloadQueue(isrc, trackName, artistName, albumName)
+	if outputDir != "" {
+		backend.SetItemOutputDir(itemID, backend.NormalizePath(outputDir))
+	}
+	return itemID
+}
+
+// QueueDownload adds a track to the queue for the backend download workers and returns its item ID.
+// Each finished item is reported with a "download:item-done" event.
+func (a *App) QueueDownload(req DownloadRequest) string {
+	if req.ItemID == "" {
+		req.ItemID = fmt.Sprintf("%s-%d", req.ISRC, time.Now().UnixNano())
+		backend.AddToQueue(req.ItemID, req.TrackName, req.ArtistName, req.AlbumName, req.ISRC)
+	}
+	if req.SpotifyID != "" {
+		backend.SetItemSpotifyID(req.ItemID, req.SpotifyID)
+	}
+	if req.Service == "" && len(req.ServicePriority) > 0 {
+		req.Service = req.ServicePriority[0]
+	}
+	if req.Service == "" {
+		req.Service = "tidal"
+	}
+	if raw, err := json.Marshal(req); err == nil {
+		backend.SetItemRequest(req.ItemID, raw)
+	}
+
+	backend.EnqueueDownloadJob(req.ItemID, req.Service, func() {
+		resp, _ := a.DownloadTrack(req)
+		if a.ctx != nil {
+			wailsRuntime.EventsEmit(a.ctx, "download:item-done", resp)
+		}
+	})
+	backend.TriggerQueuePrefetch()
+	return req.ItemID
+}
+
+// RestoreDownloadQueue reloads the items that were pending when the app last exited and returns how
+// many were restored. Items queued with QueueDownload go back to the download workers; the rest
+// are listed as queued for the frontend to download.
+func (a *App) RestoreDownloadQueue() int {
+	restored, err := backend.RestoreDownloadQueue()
+	if err != nil {
+		fmt.Printf("Warning: Failed to restore download queue: %v\n", err)
+		return 0
+	}
+
+	for _, item := range restored {
+		if len(item.Request) == 0 {
+			continue
+		}
+		var req DownloadRequest
+		if err := json.Unmarshal(item.Request, &req); err != nil {
+			fmt.Printf("Warning: Failed to restore request for %s: %v\n", item.ID, err)
+			continue
+		}
+		req.ItemID = item.ID
+		a.QueueDownload(req)
+	}
+	return len(restored)
+}
+
+// StartDownloadWorkers starts n backend download workers (0 = configured maximum) and returns how many run
+func (a *App) StartDownloadWorkers(n int) int {
+	return backend.StartDownloadWorkers(n)
+}
+
+// StopDownloadWorkers stops the download workers once their current items finish
+func (a *App) StopDownloadWorkers() {
+	backend.StopDownloadWorkers()
+}
+
+// SetMaxConcurrentDownloads sets how many items the download workers process at once (1-5)
+func (a *App) SetMaxConcurrentDownloads(n int) {
+	backend.SetMaxConcurrentDownloads(n)
+}
+
+// AddToDownloadQueueWithSpotifyID adds a track to the queue with its Spotify ID, which lets the
+// prefetch stage resolve it before its turn
+func (a *App) AddToDownloadQueueWithSpotifyID(isrc, trackName, artistName, albumName, spotifyID string) string {
+	itemID := a.AddToDownloadQueue(isrc, trackName, artistName, albumName)
+	if spotifyID != "" {
+		backend.SetItemSpotifyID(itemID, spotifyID)
+		backend.TriggerQueuePrefetch()
+	}
+	return itemID
+}
+
+// AddToDownloadQueueWithSource adds a track to the queue tagged as coming from a playlist or an album
+func (a *App) AddToDownloadQueueWithSource(isrc, trackName, artistName, albumName, source string) string {
+	itemID := a.AddToDownloadQueue(isrc, trackName, artistName, albumName)
+	if source != "" {
+		backend.SetItemSource(itemID, source)
+	}
+	return itemID
+}
+
+// GetPlaylistOutputDir returns the subfolder of baseDir used for a playlist in multi-destination batches
+func (a *App) GetPlaylistOutputDir(baseDir, playlistName string) string {
+	return backend.PlaylistOutputDir(backend.NormalizePath(baseDir), playlistName)
+}
+
 // MarkDownloadItemFailed marks a download item as failed
 func (a *App) MarkDownloadItemFailed(itemID, errorMsg string) {
 	backend.FailDownloadItem(itemID, errorMsg)
 }
 
+// ReleaseSharedAlbumCover ends an album's download session and deletes its cached cover
+func (a *App) ReleaseSharedAlbumCover(albumID string) {
+	backend.ReleaseSharedAlbumCover(albumID)
+}
+
+// MarkDownloadItemUnavailable marks a download item as unavailable on every supported service
+func (a *App) MarkDownloadItemUnavailable(itemID, spotifyID string) {
+	backend.MarkDownloadItemUnavailable(itemID, spotifyID)
+}
+
+// QueryDownloadHistory returns earlier downloads of an ISRC, newest first, so the UI can warn before a redownload
+func (a *App) QueryDownloadHistory(isrc string) ([]backend.HistoryEntry, error) {
+	return backend.QueryDownloadHistory(isrc)
+}
+
+// ExportDownloadHistory writes the global download history to outPath as CSV
+func (a *App) ExportDownloadHistory(outPath string) error {
+	if outPath == "" {
+		return fmt.Errorf("output path is required")
+	}
+	_, err := backend.ExportDownloadHistoryCSV(outPath)
+	return err
+}
+
+// ExportFailedAsCSV writes failed and unavailable queue items to outPath as a Spotify-style CSV for re-import
+func (a *App) ExportFailedAsCSV(outPath string) error {
+	if outPath == "" {
+		return fmt.Errorf("output path is required")
+	}
+	_, err := backend.ExportFailedAsCSV(outPath)
+	return err
+}
+
+// GetBatchReport summarizes the queue, listing failed downloads and tracks no service carries
+func (a *App) GetBatchReport() backend.BatchReport {
+	return backend.GetBatchReport()
+}
+
 // CancelAllQueuedItems marks all queued items as cancelled/skipped
 func (a *App) CancelAllQueuedItems() {
 	backend.CancelAllQueuedItems()
 }
 
-// Quit closes the application
+// shutdown cancels background scans and lets running downloads finish before the app exits.
+// It runs once, from Quit or from Wails when the window is closed.
+func (a *App) shutdown(ctx context.Context) {
+	a.shutdownOnce.Do(func() {
+		a.CancelLibraryAnalysis()
+		a.CancelDuplicateScan()
+		backend.Shutdown()
+		if a.cancel != nil {
+			a.cancel()
+		}
+	})
+}
+
+// Quit cleans up and closes the application
 func (a *App) Quit() {
-	// You can add cleanup logic here if needed
-	panic("quit") // This will trigger Wails to close the app
+	a.shutdown(a.ctx)
+	wailsRuntime.Quit(a.ctx)
 }
 
 // AnalyzeTrack analyzes audio quality of a FLAC file
@@ -654,6 +1493,106 @@ func (a *App) AnalyzeMultipleTracks(filePaths []string) (string, error) {
 	return string(jsonData), nil
 }
 
+// StartLibraryAnalysis analyzes all FLAC files in a directory in the background, emitting an
+// "analysis:result" event per file and "analysis:done" when the scan finishes or is cancelled
+func (a *App) StartLibraryAnalysis(dirPath string) error {
+	if dirPath == "" {
+		return fmt.Errorf("directory path is required")
+	}
+
+	a.analysisMu.Lock()
+	if a.analysisCancel != nil {
+		a.analysisMu.Unlock()
+		return fmt.Errorf("a library analysis is already running")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	a.analysisCancel = cancel
+	a.analysisMu.Unlock()
+
+	go func() {
+		defer func() {
+			a.analysisMu.Lock()
+			a.analysisCancel = nil
+			a.analysisMu.Unlock()
+			cancel()
+		}()
+
+		count, err := backend.AnalyzeLibrary(ctx, dirPath, func(progress backend.AnalysisProgress) {
+			wailsRuntime.EventsEmit(a.ctx, "analysis:result", progress)
+		})
+
+		done := map[string]interface{}{
+			"analyzed":  count,
+			"cancelled": ctx.Err() != nil,
+		}
+		if err != nil && ctx.Err() == nil {
+			done["error"] = err.Error()
+		}
+		wailsRuntime.EventsEmit(a.ctx, "analysis:done", done)
+	}()
+
+	return nil
+}
+
+// CancelLibraryAnalysis stops a running library analysis
+func (a *App) CancelLibraryAnalysis() {
+	a.analysisMu.Lock()
+	defer a.analysisMu.Unlock()
+	if a.analysisCancel != nil {
+		a.analysisCancel()
+	}
+}
+
+// StartDuplicateScan looks for audio-identical files under dirPath in the background, emitting a
+// "duplicates:progress" event per hashed file and "duplicates:done" with the groups found
+func (a *App) StartDuplicateScan(dirPath string) error {
+	if dirPath == "" {
+		return fmt.Errorf("directory path is required")
+	}
+
+	a.duplicateMu.Lock()
+	if a.duplicateCancel != nil {
+		a.duplicateMu.Unlock()
+		return fmt.Errorf("a duplicate scan is already running")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	a.duplicateCancel = cancel
+	a.duplicateMu.Unlock()
+
+	go func() {
+		defer func() {
+			a.duplicateMu.Lock()
+			a.duplicateCancel = nil
+			a.duplicateMu.Unlock()
+			cancel()
+		}()
+
+		groups, err := backend.FindDuplicates(ctx, dirPath, func(progress backend.DuplicateScanProgress) {
+			wailsRuntime.EventsEmit(a.ctx, "duplicates:progress", progress)
+		})
+
+		done := map[string]interface{}{
+			"groups":    groups,
+			"cancelled": ctx.Err() != nil,
+		}
+		if err != nil && ctx.Err() == nil {
+			done["error"] = err.Error()
+		}
+		wailsRuntime.EventsEmit(a.ctx, "duplicates:done", done)
+	}()
+
+	return nil
+}
+
+// CancelDuplicateScan stops a running duplicate scan
+func (a *App) CancelDuplicateScan() {
+	a.duplicateMu.Lock()
+	defer a.duplicateMu.Unlock()
+	if a.duplicateCancel != nil {
+		a.duplicateCancel()
+	}
+}
+
 // LyricsDownloadRequest represents the request structure for downloading lyrics
 type LyricsDownloadRequest struct {
 	SpotifyID           string `json:"spotify_id"`
@@ -668,6 +1607,7 @@ type LyricsDownloadRequest struct {
 	Position            int    `json:"position"`
 	UseAlbumTrackNumber bool   `json:"use_album_track_number"`
 	DiscNumber          int    `json:"disc_number"`
+	LyricsFormat        string `json:"lyrics_format,omitempty"` // "lrc", "srt" or "both"
 }
 
 // DownloadLyrics downloads lyrics for a single track
@@ -693,6 +1633,7 @@ func (a *App) DownloadLyrics(req LyricsDownloadRequest) (backend.LyricsDownloadR
 		Position:            req.Position,
 		UseAlbumTrackNumber: req.UseAlbumTrackNumber,
 		DiscNumber:          req.DiscNumber,
+		LyricsFormat:        req.LyricsFormat,
 	}
 
 	resp, err := client.DownloadLyrics(backendReq)
@@ -756,6 +1697,13 @@ func (a *App) DownloadCover(req CoverDownloadRequest) (backend.CoverDownloadResp
 	return *resp, nil
 }
 
+// DownloadArtistImages saves each artist's Spotify image into outDir, named by artist
+func (a *App) DownloadArtistImages(spotifyArtistIDs []string, outDir string) []backend.CoverDownloadResponse {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	return backend.DownloadArtistImages(ctx, spotifyArtistIDs, outDir)
+}
+
 // CheckTrackAvailability checks the availability of a track on different streaming platforms
 func (a *App) CheckTrackAvailability(spotifyTrackID string, isrc string) (string, error) {
 	if spotifyTrackID == "" {
@@ -791,6 +1739,17 @@ func (a *App) GetFFmpegPath() (string, error) {
 	return backend.GetFFmpegPath()
 }
 
+// SetFFmpegPath uses an existing ffmpeg binary instead of the bundled download and saves the choice
+func (a *App) SetFFmpegPath(path string) error {
+	if err := backend.SetFFmpegPath(path); err != nil {
+		return err
+	}
+
+	settings := backend.GetSettings()
+	settings.FFmpegPath = path
+	return backend.UpdateSettings(settings)
+}
+
 // DownloadFFmpegRequest represents a request to download ffmpeg
 type DownloadFFmpegRequest struct{}
 
@@ -876,6 +1835,33 @@ func (a *App) ReadFileMetadata(filePath string) (*backend.AudioMetadata, error)
 	return backend.ReadAudioMetadata(filePath)
 }
 
+// MergeArtistFolders folds near-duplicate artist folders under rootPath into one, using the configured aliases
+func (a *App) MergeArtistFolders(rootPath string, dryRun bool) (*backend.ArtistFolderMergeResult, error) {
+	if rootPath == "" {
+		return &backend.ArtistFolderMergeResult{Success: false, Error: "Root path is required"}, fmt.Errorf("root path is required")
+	}
+	return backend.MergeArtistFolders(rootPath, backend.GetSettings().ArtistFolderAliases, dryRun)
+}
+
+// FixMojibakeTags repairs double-encoded title/artist/album tags below dirPath; dryRun only lists the fixes
+func (a *App) FixMojibakeTags(dirPath string, dryRun bool) (*backend.MojibakeRepairResult, error) {
+	if dirPath == "" {
+		return &backend.MojibakeRepairResult{Success: false, Error: "Directory path is required"}, fmt.Errorf("directory path is required")
+	}
+	return backend.FixMojibakeInFolder(dirPath, dryRun)
+}
+
+// RetagLibrary rewrites core tags below req.ScanPath from matched Spotify metadata; DryRun only reports the diffs
+func (a *App) RetagLibrary(req backend.RetagRequest) (*backend.RetagResponse, error) {
+	if req.ScanPath == "" {
+		return &backend.RetagResponse{Success: false, Error: "Directory path is required"}, fmt.Errorf("directory path is required")
+	}
+	if req.DatabasePath == "" {
+		req.DatabasePath = backend.GetSettings().DatabasePath
+	}
+	return backend.RetagLibrary(req)
+}
+
 // PreviewRenameFiles generates a preview of rename operations
 func (a *App) PreviewRenameFiles(files []string, format string) []backend.RenamePreview {
 	return backend.PreviewRename(files, format)
@@ -961,6 +1947,15 @@ func (a *App) CheckFilesExistence(outputDir string, tracks []CheckFileExistenceR
 	return backend.CheckFilesExistParallel(outputDir, backendTracks)
 }
 
+// FilterMissingTracks returns only the album/playlist tracks not yet in the output directory,
+// using local checks so present tracks skip availability and metadata lookups entirely
+func (a *App) FilterMissingTracks(req backend.MissingOnlyRequest) (*backend.MissingOnlyResult, error) {
+	if req.OutputDir == "" {
+		return nil, fmt.Errorf("output directory is required")
+	}
+	return backend.FilterMissingTracks(req)
+}
+
 // SkipDownloadItem marks a download item as skipped (file already exists)
 func (a *App) SkipDownloadItem(itemID, filePath string) {
 	backend.SkipDownloadItem(itemID, filePath)
@@ -976,7 +1971,7 @@ func (a *App) SelectMultipleCSVFiles() ([]string, error) {
 	return backend.SelectMultipleCSVFiles(a.ctx)
 }
 
-// ParseCSVPlaylist parses a CSV playlist file and returns tracks
+// ParseCSVPlaylist parses a CSV, text or M3U playlist file and returns tracks
 func (a *App) ParseCSVPlaylist(filePath string) (backend.CSVParseResult, error) {
 	if filePath == "" {
 		return backend.CSVParseResult{
@@ -988,7 +1983,7 @@ func (a *App) ParseCSVPlaylist(filePath string) (backend.CSVParseResult, error)
 	fmt.Printf("\n========== CSV PARSE START ==========\n")
 	fmt.Printf("File path: %s\n", filePath)
 
-	tracks, err := backend.ParseCSVPlaylist(filePath)
+	tracks, localTracks, err := backend.ParsePlaylistFileWithLocal(filePath, backend.CSVSortOriginal)
 	if err != nil {
 		fmt.Printf("Parse error: %v\n", err)
 		fmt.Printf("========== CSV PARSE END (FAILED) ==========\n\n")
@@ -999,16 +1994,50 @@ func (a *App) ParseCSVPlaylist(filePath string) (backend.CSVParseResult, error)
 		}, err
 	}
 
-	fmt.Printf("Parse success: %d tracks\n", len(tracks))
+	fmt.Printf("Parse success: %d tracks, %d local/unavailable\n", len(tracks), len(localTracks))
+
+	var validation *backend.CSVValidationReport
+	if backend.GetSettings().ValidateCSVTracks {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		report := backend.ValidateCSVTracks(ctx, tracks)
+		cancel()
+		validation = &report
+	}
 	fmt.Printf("========== CSV PARSE END (SUCCESS) ==========\n\n")
 
 	return backend.CSVParseResult{
-		Success:    true,
-		TrackCount: len(tracks),
-		Tracks:     tracks,
+		Success:     true,
+		TrackCount:  len(tracks),
+		Tracks:      tracks,
+		LocalTracks: localTracks,
+		Validation:  validation,
 	}, nil
 }
 
+// ValidateCSVTracks checks that parsed CSV tracks still resolve on Spotify, flagging dead IDs before queueing
+func (a *App) ValidateCSVTracks(tracks []backend.CSVTrack) backend.CSVValidationReport {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	return backend.ValidateCSVTracks(ctx, tracks)
+}
+
+// MatchLocalCSVTracks searches Spotify by title and artist for CSV rows that had no track URI
+func (a *App) MatchLocalCSVTracks(tracks []backend.CSVTrack) []backend.LocalTrackMatch {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	return backend.MatchLocalCSVTracks(ctx, tracks)
+}
+
+// SortCSVTracks reorders parsed CSV tracks (original, artist, album, release_date) and reassigns positions
+func (a *App) SortCSVTracks(tracks []backend.CSVTrack, sortBy string) []backend.CSVTrack {
+	return backend.SortCSVTracks(tracks, sortBy)
+}
+
+// FilterCSVTracksByDuration removes tracks shorter than minDurationSeconds before queueing (0 = no filter)
+func (a *App) FilterCSVTracksByDuration(tracks []backend.CSVTrack, minDurationSeconds int) backend.CSVDurationFilterResult {
+	return backend.FilterCSVTracksByDuration(tracks, minDurationSeconds)
+}
+
 // ParseMultipleCSVFiles parses multiple CSV playlist files and returns batch results
 func (a *App) ParseMultipleCSVFiles(filePaths []string) (backend.BatchCSVParseResult, error) {
 	if len(filePaths) == 0 {
@@ -1022,6 +2051,11 @@ func (a *App) ParseMultipleCSVFiles(filePaths []string) (backend.BatchCSVParseRe
 	fmt.Printf("Number of files: %d\n", len(filePaths))
 
 	result := backend.ParseMultipleCSVFiles(filePaths)
+	if result.Success && backend.GetSettings().ValidateCSVTracks {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		backend.ValidateBatchCSVTracks(ctx, &result)
+		cancel()
+	}
 
 	fmt.Printf("========== BATCH CSV PARSE END ==========\n\n")
 
@@ -1116,18 +2150,425 @@ func (a *App) VerifyLibraryCompleteness(req backend.LibraryVerificationRequest)
 	return response, nil
 }
 
+// SetDefaultCover sets the placeholder image used when no album art can be found (empty disables it)
+func (a *App) SetDefaultCover(coverPath string) error {
+	return backend.SetDefaultCover(coverPath)
+}
+
+// GetDefaultCover returns the configured placeholder cover path
+func (a *App) GetDefaultCover() string {
+	return backend.GetDefaultCover()
+}
+
+// GetMissingCoverTracks returns downloaded tracks that received the placeholder cover
+func (a *App) GetMissingCoverTracks() []string {
+	return backend.GetMissingCoverTracks()
+}
+
+// ClearMissingCoverTracks clears the list of tracks flagged with the placeholder cover
+func (a *App) ClearMissingCoverTracks() {
+	backend.ClearMissingCoverTracks()
+}
+
+// RunSelfTest checks ffmpeg/ffprobe, directory write access, service connectivity and the database
+func (a *App) RunSelfTest() (backend.SelfTestReport, error) {
+	fmt.Println("\n========== SELF TEST START ==========")
+	report := backend.RunSelfTest(backend.GetSettings())
+	fmt.Printf("========== SELF TEST END ==========\n\n")
+	return *report, nil
+}
+
+// GetEnrichmentWarnings returns and clears cover/lyrics failures from background post-processing, keyed by file
+func (a *App) GetEnrichmentWarnings() map[string][]string {
+	return backend.TakeAllEnrichWarnings()
+}
+
+// GetReadOnlySkippedFiles returns files whose cover/lyrics embedding was skipped because they are read-only
+func (a *App) GetReadOnlySkippedFiles() []string {
+	return backend.GetReadOnlySkippedFiles()
+}
+
+// ClearReadOnlySkippedFiles clears the list of skipped read-only files
+func (a *App) ClearReadOnlySkippedFiles() {
+	backend.ClearReadOnlySkippedFiles()
+}
+
+// GetCroppedCoverTracks returns downloaded tracks whose album art was cropped to square
+func (a *App) GetCroppedCoverTracks() []string {
+	return backend.GetCroppedCoverTracks()
+}
+
+// ClearCroppedCoverTracks clears the list of tracks with cropped album art
+func (a *App) ClearCroppedCoverTracks() {
+	backend.ClearCroppedCoverTracks()
+}
+
+// ExportCatalog writes a JSON inventory of every audio file under dirPath (tags, ISRC, quality,
+// cover/lyrics presence) to outPath
+func (a *App) ExportCatalog(dirPath, outPath string) error {
+	if dirPath == "" || outPath == "" {
+		return fmt.Errorf("directory path and output path are required")
+	}
+	_, err := backend.ExportCatalog(dirPath, outPath)
+	return err
+}
+
+// DeduplicateEmbeddedArt finds albums whose tracks embed identical art and, when enabled in
+// settings, moves it to a shared cover sidecar; returns the bytes saved
+func (a *App) DeduplicateEmbeddedArt(dirPath string) (int64, error) {
+	if dirPath == "" {
+		return 0, fmt.Errorf("directory path is required")
+	}
+	result, err := backend.DeduplicateEmbeddedArt(dirPath)
+	if err != nil {
+		return 0, err
+	}
+	return result.SavedBytes, nil
+}
+
+// GetCopyFinalizedFiles returns files that were copied instead of renamed into place because the
+// output folder is on another filesystem (network share, cloud drive)
+func (a *App) GetCopyFinalizedFiles() []string {
+	return backend.GetCopyFinalizedFiles()
+}
+
+// ClearCopyFinalizedFiles clears the list of copy-finalized files
+func (a *App) ClearCopyFinalizedFiles() {
+	backend.ClearCopyFinalizedFiles()
+}
+
+// FindBrokenAudioFiles lists audio files in a directory that are empty, truncated or fail to decode
+func (a *App) FindBrokenAudioFiles(dirPath string) ([]backend.BrokenFile, error) {
+	if dirPath == "" {
+		return nil, fmt.Errorf("directory path is required")
+	}
+	return backend.FindBrokenAudioFiles(dirPath)
+}
+
+// GetLibraryStats summarizes a music folder: formats, size, duration, quality and missing cover/lyrics/ISRC
+func (a *App) GetLibraryStats(dirPath string) (backend.LibraryStats, error) {
+	if dirPath == "" {
+		return backend.LibraryStats{}, fmt.Errorf("directory path is required")
+	}
+	stats, err := backend.GetLibraryStats(dirPath)
+	if err != nil {
+		return backend.LibraryStats{}, err
+	}
+	return *stats, nil
+}
+
+// DeleteBrokenAudioFiles deletes broken audio files so they can be re-downloaded
+func (a *App) DeleteBrokenAudioFiles(filePaths []string) (int, error) {
+	return backend.DeleteBrokenAudioFiles(filePaths)
+}
+
+// CompareCSVToFolder reports which tracks of a CSV playlist already exist in a local folder
+func (a *App) CompareCSVToFolder(req backend.CSVCompareRequest) (*backend.CSVCompareResult, error) {
+	if req.CSVFilePath == "" || req.FolderPath == "" {
+		return &backend.CSVCompareResult{
+			Success: false,
+			Error:   "CSV file path and folder path are required",
+		}, fmt.Errorf("csv file path and folder path are required")
+	}
+
+	fmt.Println("\n========== CSV COMPARE START ==========")
+	result, err := backend.CompareCSVToFolder(req)
+	if err != nil {
+		fmt.Printf("========== CSV COMPARE END (FAILED) ==========\n\n")
+		return result, err
+	}
+	fmt.Printf("========== CSV COMPARE END (SUCCESS) ==========\n\n")
+	return result, nil
+}
+
+// AuditBatchAgainstSpotify compares downloaded files against Spotify durations to flag wrong versions and missing tracks
+func (a *App) AuditBatchAgainstSpotify(dirPath string, tracks []backend.CSVTrack) (backend.AuditReport, error) {
+	if dirPath == "" {
+		return backend.AuditReport{Success: false, Error: "directory path is required"}, fmt.Errorf("directory path is required")
+	}
+
+	report, err := backend.AuditBatchAgainstSpotify(dirPath, tracks)
+	return *report, err
+}
+
+// RepairISRCTags writes missing ISRC tags into FLAC files so ISRC-based dedup recognizes them
+func (a *App) RepairISRCTags(dirPath, databasePath string) []backend.TrackVerificationResult {
+	fmt.Println("\n========== ISRC REPAIR START ==========")
+
+	results, err := backend.RepairISRCTags(dirPath, databasePath)
+	if err != nil {
+		fmt.Printf("ISRC repair failed: %v\n", err)
+		fmt.Printf("========== ISRC REPAIR END (FAILED) ==========\n\n")
+		return []backend.TrackVerificationResult{{FilePath: dirPath, Error: err.Error()}}
+	}
+
+	repaired := 0
+	for _, r := range results {
+		if r.ISRCRepaired {
+			repaired++
+		}
+	}
+	fmt.Printf("Repaired %d/%d files\n", repaired, len(results))
+	fmt.Printf("========== ISRC REPAIR END (SUCCESS) ==========\n\n")
+	return results
+}
+
+// CreateArchive zips downloaded files together with their cover and lyrics sidecars
+func (a *App) CreateArchive(files []string, zipPath string) (*backend.ArchiveResult, error) {
+	if zipPath == "" {
+		return &backend.ArchiveResult{Success: false, Error: "Archive path is required"}, fmt.Errorf("archive path is required")
+	}
+
+	fmt.Println("\n========== ARCHIVE START ==========")
+	result, err := backend.CreateArchive(files, zipPath, true)
+	if err != nil {
+		fmt.Printf("========== ARCHIVE END (FAILED) ==========\n\n")
+		return result, err
+	}
+	fmt.Printf("========== ARCHIVE END (SUCCESS) ==========\n\n")
+	return result, nil
+}
+
+// archiveBatch zips a queued batch once all its items have finished and reports the archive's path
+// and size with an "archive:done" event
+func (a *App) archiveBatch(itemIDs []string, zipPath string) {
+	result, err := backend.ArchiveBatch(itemIDs, zipPath)
+	if err != nil {
+		fmt.Printf("[Archive] Batch archive failed: %v\n", err)
+	}
+	if a.ctx != nil {
+		wailsRuntime.EventsEmit(a.ctx, "archive:done", result)
+	}
+}
+
+// FilterTracksByVersion drops live/acoustic/remix-style versions before queueing.
+// A nil keyword list uses the configured keywords, or the defaults if none are set.
+func (a *App) FilterTracksByVersion(tracks []backend.AlbumTrackMetadata, keywords []string) backend.VersionFilterResult {
+	if keywords == nil {
+		settings := backend.GetSettings()
+		if !settings.SkipAlternateVersions {
+			return backend.FilterTracksByVersionKeywords(tracks, nil)
+		}
+		keywords = settings.ExcludedVersionKeywords
+		if len(keywords) == 0 {
+			keywords = backend.DefaultExcludedVersionKeywords
+		}
+	}
+	return backend.FilterTracksByVersionKeywords(tracks, keywords)
+}
+
+// GetDefaultVersionKeywords returns the built-in list of excluded version keywords
+func (a *App) GetDefaultVersionKeywords() []string {
+	return backend.DefaultExcludedVersionKeywords
+}
+
 // CSVBatchDownloadRequest represents a request to download tracks from a CSV file
 type CSVBatchDownloadRequest struct {
-	CSVFilePath string `json:"csv_file_path"`
-	OutputDir   string `json:"output_dir"`
+	CSVFilePath        string `json:"csv_file_path"`
+	OutputDir          string `json:"output_dir"`
+	MinDurationSeconds int    `json:"min_duration_seconds,omitempty"` // 0 = the min_duration_seconds setting
 }
 
 // CSVBatchDownloadResponse represents the response from CSV batch download
 type CSVBatchDownloadResponse struct {
-	Success       bool   `json:"success"`
-	Message       string `json:"message"`
-	TotalTracks   int    `json:"total_tracks"`
-	QueuedTracks  int    `json:"queued_tracks"`
-	SkippedTracks int    `json:"skipped_tracks"`
-	Error         string `json:"error,omitempty"`
+	Success       bool     `json:"success"`
+	Message       string   `json:"message"`
+	TotalTracks   int      `json:"total_tracks"`
+	QueuedTracks  int      `json:"queued_tracks"`
+	SkippedTracks int      `json:"skipped_tracks"`
+	ShortTracks   int      `json:"short_tracks"` // Dropped by the minimum duration filter
+	ItemIDs       []string `json:"item_ids,omitempty"`
+	ArchivePath   string   `json:"archive_path,omitempty"` // Written once the batch finishes, when archive_after_download is on
+	Error         string   `json:"error,omitempty"`
+}
+
+// DownloadCSVBatch parses a CSV, text or M3U playlist and queues one download per track, dropping
+// tracks shorter than the minimum duration and skipping ones whose ISRC is already in the output folder
+func (a *App) DownloadCSVBatch(req CSVBatchDownloadRequest) (CSVBatchDownloadResponse, error) {
+	if req.CSVFilePath == "" {
+		return CSVBatchDownloadResponse{Success: false, Error: "File path is required"}, fmt.Errorf("file path is required")
+	}
+
+	tracks, _, err := backend.ParsePlaylistFileWithLocal(req.CSVFilePath, backend.CSVSortOriginal)
+	if err != nil {
+		return CSVBatchDownloadResponse{Success: false, Error: err.Error()}, err
+	}
+
+	outputDir := req.OutputDir
+	if outputDir == "" {
+		outputDir = backend.GetDefaultMusicPath()
+	} else {
+		outputDir = backend.NormalizePath(outputDir)
+	}
+
+	minDuration := req.MinDurationSeconds
+	if minDuration == 0 {
+		minDuration = backend.GetSettings().MinDurationSeconds
+	}
+	filtered := backend.FilterCSVTracksByDuration(tracks, minDuration)
+	resp := CSVBatchDownloadResponse{
+		TotalTracks: len(tracks),
+		ShortTracks: filtered.SkippedCount,
+	}
+	for _, track := range filtered.Tracks {
+		if track.ISRC != "" {
+			if existingFile, exists := backend.CheckISRCExists(outputDir, track.ISRC); exists {
+				fmt.Printf("[CSV Batch] Skipping %s - already exists: %s\n", track.TrackName, existingFile)
+				resp.SkippedTracks++
+				continue
+			}
+		}
+
+		itemID := a.QueueDownload(DownloadRequest{
+			ISRC:        track.ISRC,
+			TrackName:   track.TrackName,
+			ArtistName:  track.ArtistName,
+			AlbumName:   track.AlbumName,
+			ReleaseDate: track.ReleaseDate,
+			OutputDir:   outputDir,
+			Position:    track.Position,
+			SpotifyID:   track.SpotifyID,
+			Duration:    track.DurationMs / 1000,
+			Genre:       track.Genre,
+			Source:      "playlist",
+		})
+		resp.ItemIDs = append(resp.ItemIDs, itemID)
+		resp.QueuedTracks++
+	}
+
+	if resp.QueuedTracks > 0 {
+		backend.StartDownloadWorkers(0)
+		if backend.GetSettings().ArchiveAfterDownload {
+			name := strings.TrimSuffix(filepath.Base(req.CSVFilePath), filepath.Ext(req.CSVFilePath))
+			resp.ArchivePath = backend.BatchArchivePath(outputDir, name)
+			go a.archiveBatch(resp.ItemIDs, resp.ArchivePath)
+		}
+	}
+	resp.Success = true
+	resp.Message = fmt.Sprintf("Queued %d of %d tracks (%d already downloaded, %d too short)", resp.QueuedTracks, resp.TotalTracks, resp.SkippedTracks, resp.ShortTracks)
+	fmt.Printf("[CSV Batch] %s\n", resp.Message)
+	return resp, nil
+}
+
+// AlbumDownloadRequest represents a request to queue every track of a Spotify album
+type AlbumDownloadRequest struct {
+	AlbumURL             string `json:"album_url"` // Spotify album URL, URI or bare ID
+	Service              string `json:"service"`
+	OutputDir            string `json:"output_dir"`
+	AudioFormat          string `json:"audio_format,omitempty"`
+	FilenameFormat       string `json:"filename_format,omitempty"`
+	TrackNumber          bool   `json:"track_number,omitempty"`
+	EmbedLyrics          bool   `json:"embed_lyrics,omitempty"`
+	EmbedMaxQualityCover bool   `json:"embed_max_quality_cover,omitempty"`
+	ShareAlbumCover      bool   `json:"share_album_cover,omitempty"`
+}
+
+// AlbumDownloadResponse represents the result of queueing an album
+type AlbumDownloadResponse struct {
+	Success       bool     `json:"success"`
+	Message       string   `json:"message"`
+	AlbumName     string   `json:"album_name,omitempty"`
+	TotalTracks   int      `json:"total_tracks"`
+	QueuedTracks  int      `json:"queued_tracks"`
+	SkippedTracks int      `json:"skipped_tracks"`
+	ItemIDs       []string `json:"item_ids,omitempty"`
+	ArchivePath   string   `json:"archive_path,omitempty"` // Written once the album finishes, when archive_after_download is on
+	Error         string   `json:"error,omitempty"`
+}
+
+// DownloadAlbum fetches an album's full tracklist and queues one download per track, skipping
+// tracks whose ISRC is already in the output folder
+func (a *App) DownloadAlbum(req AlbumDownloadRequest) (AlbumDownloadResponse, error) {
+	albumURL := strings.TrimSpace(req.AlbumURL)
+	if albumURL == "" {
+		return AlbumDownloadResponse{Success: false, Error: "album URL is required"}, fmt.Errorf("album URL is required")
+	}
+	// A bare album ID is accepted as well as a URL or URI
+	if !strings.Contains(albumURL, "/") && !strings.Contains(albumURL, ":") {
+		albumURL = "https://open.spotify.com/album/" + albumURL
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Second)
+	defer cancel()
+
+	data, err := backend.GetFilteredSpotifyData(ctx, albumURL, false, 0)
+	if err != nil {
+		return AlbumDownloadResponse{Success: false, Error: err.Error()}, fmt.Errorf("failed to fetch album: %v", err)
+	}
+	album, ok := data.(*backend.AlbumResponsePayload)
+	if !ok {
+		return AlbumDownloadResponse{Success: false, Error: "URL is not a Spotify album"}, fmt.Errorf("URL is not a Spotify album")
+	}
+
+	outputDir := req.OutputDir
+	if outputDir == "" {
+		outputDir = backend.GetDefaultMusicPath()
+	} else {
+		outputDir = backend.NormalizePath(outputDir)
+	}
+	service := req.Service
+	if service == "" {
+		service = "tidal"
+	}
+
+	resp := AlbumDownloadResponse{
+		AlbumName:   album.AlbumInfo.Name,
+		TotalTracks: len(album.TrackList),
+	}
+	for _, track := range album.TrackList {
+		if track.ISRC != "" {
+			if existingFile, exists := backend.CheckISRCExists(outputDir, track.ISRC); exists {
+				fmt.Printf("[Album] Skipping %s - already exists: %s\n", track.Name, existingFile)
+				resp.SkippedTracks++
+				continue
+			}
+		}
+
+		totalTracks := track.TotalTracks
+		if totalTracks == 0 {
+			totalTracks = album.AlbumInfo.TotalTracks
+		}
+		itemID := a.QueueDownload(DownloadRequest{
+			ISRC:                 track.ISRC,
+			Service:              service,
+			TrackName:            track.Name,
+			ArtistName:           track.Artists,
+			AlbumName:            track.AlbumName,
+			AlbumArtist:          track.AlbumArtist,
+			ReleaseDate:          track.ReleaseDate,
+			CoverURL:             track.Images,
+			OutputDir:            outputDir,
+			AudioFormat:          req.AudioFormat,
+			FilenameFormat:       req.FilenameFormat,
+			TrackNumber:          req.TrackNumber,
+			Position:             track.TrackNumber,
+			UseAlbumTrackNumber:  true,
+			SpotifyID:            track.SpotifyID,
+			EmbedLyrics:          req.EmbedLyrics,
+			EmbedMaxQualityCover: req.EmbedMaxQualityCover,
+			Duration:             track.DurationMS / 1000,
+			SpotifyTrackNumber:   track.TrackNumber,
+			SpotifyDiscNumber:    track.DiscNumber,
+			SpotifyTotalDiscs:    track.TotalDiscs,
+			SpotifyTotalTracks:   totalTracks,
+			AlbumID:              track.AlbumID,
+			ArtistID:             track.ArtistID,
+			ShareAlbumCover:      req.ShareAlbumCover,
+			Source:               "album",
+		})
+		resp.ItemIDs = append(resp.ItemIDs, itemID)
+		resp.QueuedTracks++
+	}
+
+	if resp.QueuedTracks > 0 {
+		backend.StartDownloadWorkers(0)
+		if backend.GetSettings().ArchiveAfterDownload {
+			resp.ArchivePath = backend.BatchArchivePath(outputDir, album.AlbumInfo.Name)
+			go a.archiveBatch(resp.ItemIDs, resp.ArchivePath)
+		}
+	}
+	resp.Success = true
+	resp.Message = fmt.Sprintf("Queued %d of %d tracks from %s (%d already downloaded)", resp.QueuedTracks, resp.TotalTracks, resp.AlbumName, resp.SkippedTracks)
+	fmt.Printf("[Album] %s\n", resp.Message)
+	return resp, nil
 }
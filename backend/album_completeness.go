@@ -0,0 +1,104 @@
+package backend
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AlbumStatus describes an album whose files on disk don't add up to its track count
+type AlbumStatus struct {
+	Album          string   `json:"album"`
+	AlbumArtist    string   `json:"album_artist,omitempty"`
+	Directory      string   `json:"directory"`
+	PresentTracks  int      `json:"present_tracks"`
+	TotalTracks    int      `json:"total_tracks"`
+	MissingNumbers []int    `json:"missing_numbers,omitempty"` // Only for single-disc albums, where numbers are unambiguous
+	Files          []string `json:"files"`
+}
+
+// parseTagNumber reads "3" or "3/12" and returns both parts, zero when absent
+func parseTagNumber(value string) (int, int) {
+	parts := strings.SplitN(strings.TrimSpace(value), "/", 2)
+	number, _ := strconv.Atoi(strings.TrimSpace(parts[0]))
+	total := 0
+	if len(parts) == 2 {
+		total, _ = strconv.Atoi(strings.TrimSpace(parts[1]))
+	}
+	return number, total
+}
+
+// FindIncompleteAlbums groups audio files by album tag and reports albums with fewer files
+// than their tagged total. Files without an album or a total track count are skipped.
+func FindIncompleteAlbums(audioFiles []string) []AlbumStatus {
+	type albumGroup struct {
+		status    AlbumStatus
+		numbers   map[int]bool
+		multiDisc bool
+	}
+
+	groups := make(map[string]*albumGroup)
+	var order []string
+	for _, path := range audioFiles {
+		meta, err := ExtractMetadataFromFile(path)
+		if err != nil || meta.Album == "" || meta.TotalTracks <= 0 {
+			continue
+		}
+
+		albumArtist := meta.AlbumArtist
+		if albumArtist == "" {
+			albumArtist = meta.Artist
+		}
+		key := normalizeMatchKey(meta.Album) + "\x00" + normalizeMatchKey(albumArtist)
+
+		group, ok := groups[key]
+		if !ok {
+			group = &albumGroup{
+				status: AlbumStatus{
+					Album:       meta.Album,
+					AlbumArtist: albumArtist,
+					Directory:   filepath.Dir(path),
+				},
+				numbers: make(map[int]bool),
+			}
+			groups[key] = group
+			order = append(order, key)
+		}
+
+		group.status.PresentTracks++
+		group.status.Files = append(group.status.Files, path)
+		if meta.TotalTracks > group.status.TotalTracks {
+			group.status.TotalTracks = meta.TotalTracks
+		}
+		if meta.DiscNumber > 1 || meta.TotalDiscs > 1 {
+			group.multiDisc = true
+		}
+		if meta.TrackNumber > 0 {
+			group.numbers[meta.TrackNumber] = true
+		}
+	}
+
+	incomplete := make([]AlbumStatus, 0)
+	for _, key := range order {
+		group := groups[key]
+		if group.status.PresentTracks >= group.status.TotalTracks {
+			continue
+		}
+
+		if !group.multiDisc && len(group.numbers) > 0 {
+			for n := 1; n <= group.status.TotalTracks; n++ {
+				if !group.numbers[n] {
+					group.status.MissingNumbers = append(group.status.MissingNumbers, n)
+				}
+			}
+		}
+		sort.Strings(group.status.Files)
+
+		fmt.Printf("[Library Verifier] Incomplete album: %s - %s (%d/%d)\n",
+			group.status.AlbumArtist, group.status.Album, group.status.PresentTracks, group.status.TotalTracks)
+		incomplete = append(incomplete, group.status)
+	}
+	return incomplete
+}
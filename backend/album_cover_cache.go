@@ -0,0 +1,110 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// sharedAlbumCover is an album's art fetched once and reused by every track of the album
+type sharedAlbumCover struct {
+	once sync.Once
+	url  string
+	path string
+	err  error
+}
+
+var (
+	sharedAlbumCovers     = make(map[string]*sharedAlbumCover)
+	sharedAlbumCoversLock sync.Mutex
+)
+
+// PrepareSharedAlbumCover downloads an album's cover the first time it is asked for and returns the
+// cached file on every later call, so all tracks of the album embed identical art
+func PrepareSharedAlbumCover(albumID, coverURL string, embedMaxQualityCover bool) (string, error) {
+	if albumID == "" || coverURL == "" {
+		return "", fmt.Errorf("album ID and cover URL are required")
+	}
+
+	sharedAlbumCoversLock.Lock()
+	cover, ok := sharedAlbumCovers[albumID]
+	if !ok {
+		cover = &sharedAlbumCover{url: coverURL}
+		sharedAlbumCovers[albumID] = cover
+	}
+	sharedAlbumCoversLock.Unlock()
+
+	cover.once.Do(func() {
+		tmp, err := os.CreateTemp("", "spotiflac-album-cover-*.jpg")
+		if err != nil {
+			cover.err = fmt.Errorf("failed to create temp file: %v", err)
+			return
+		}
+		tmp.Close()
+
+		if err := NewCoverClient().DownloadCoverToPath(coverURL, tmp.Name(), embedMaxQualityCover); err != nil {
+			os.Remove(tmp.Name())
+			cover.err = err
+			return
+		}
+
+		fmt.Printf("[Cover] Fetched shared cover for album %s\n", albumID)
+		sharedAlbumCoversLock.Lock()
+		cover.path = tmp.Name()
+		sharedAlbumCoversLock.Unlock()
+	})
+
+	return cover.path, cover.err
+}
+
+// sharedCoverPathForURL returns the cached album cover downloaded from coverURL, if any
+func sharedCoverPathForURL(coverURL string) string {
+	sharedAlbumCoversLock.Lock()
+	defer sharedAlbumCoversLock.Unlock()
+
+	for _, cover := range sharedAlbumCovers {
+		if cover.url == coverURL && cover.path != "" {
+			return cover.path
+		}
+	}
+	return ""
+}
+
+// WriteAlbumFolderCover copies an album's shared cover to cover.jpg in dir unless one exists
+func WriteAlbumFolderCover(albumID, dir string) error {
+	sharedAlbumCoversLock.Lock()
+	cover, ok := sharedAlbumCovers[albumID]
+	path := ""
+	if ok {
+		path = cover.path
+	}
+	sharedAlbumCoversLock.Unlock()
+
+	if path == "" {
+		return fmt.Errorf("no shared cover for album %s", albumID)
+	}
+
+	folderCover := filepath.Join(dir, "cover.jpg")
+	if info, err := os.Stat(folderCover); err == nil && info.Size() > 0 {
+		return nil
+	}
+	return copyFile(path, folderCover)
+}
+
+// ReleaseSharedAlbumCover ends an album's download session and deletes its cached cover.
+// An empty album ID releases every cached cover.
+func ReleaseSharedAlbumCover(albumID string) {
+	sharedAlbumCoversLock.Lock()
+	defer sharedAlbumCoversLock.Unlock()
+
+	for id, cover := range sharedAlbumCovers {
+		if albumID != "" && id != albumID {
+			continue
+		}
+		if cover.path != "" {
+			os.Remove(cover.path)
+		}
+		delete(sharedAlbumCovers, id)
+	}
+}
@@ -0,0 +1,146 @@
+package backend
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const (
+	defaultAlbumMatchThreshold = 0.85
+	maxAlbumMatchCandidates    = 500
+)
+
+var (
+	albumMatchThreshold     = defaultAlbumMatchThreshold
+	albumMatchThresholdLock sync.RWMutex
+
+	// Bracketed edition markers: "(Deluxe Edition)", "[2011 Remaster]", "(Super Deluxe Version)"
+	albumEditionPattern = regexp.MustCompile(`(?i)\s*[\(\[][^\)\]]*(remaster|deluxe|edition|expanded|anniversary|bonus|version|mono|stereo)[^\)\]]*[\)\]]`)
+)
+
+// SetAlbumMatchThreshold sets the minimum similarity (0-1) for a fuzzy album-name match in the
+// database. A threshold of 0 or less disables fuzzy matching.
+func SetAlbumMatchThreshold(threshold float64) {
+	if threshold > 1 {
+		threshold = 1
+	}
+	albumMatchThresholdLock.Lock()
+	albumMatchThreshold = threshold
+	albumMatchThresholdLock.Unlock()
+}
+
+func getAlbumMatchThreshold() float64 {
+	albumMatchThresholdLock.RLock()
+	defer albumMatchThresholdLock.RUnlock()
+	return albumMatchThreshold
+}
+
+// normalizeAlbumName strips edition suffixes, punctuation and case so
+// "Abbey Road (Remastered 2009)" and "abbey road" compare equal
+func normalizeAlbumName(name string) string {
+	name = albumEditionPattern.ReplaceAllString(name, "")
+	name = stripEditionSuffix(name)
+	return normalizeMatchKey(name)
+}
+
+// albumSearchWord picks the longest word of an album name to pre-filter candidates in SQL
+func albumSearchWord(name string) string {
+	name = albumEditionPattern.ReplaceAllString(name, "")
+	longest := ""
+	for _, word := range strings.Fields(name) {
+		word = strings.Trim(word, ".,:;!?'\"()[]-")
+		if len([]rune(word)) > len([]rune(longest)) {
+			longest = word
+		}
+	}
+	return longest
+}
+
+// levenshteinDistance returns the edit distance between two strings, by rune
+func levenshteinDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(minInt(prev[j]+1, curr[j-1]+1), prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// albumNameSimilarity scores two album names from 0 (unrelated) to 1 (equal after normalization)
+func albumNameSimilarity(a, b string) float64 {
+	na, nb := normalizeAlbumName(a), normalizeAlbumName(b)
+	if na == "" || nb == "" {
+		return 0
+	}
+	if na == nb {
+		return 1
+	}
+
+	longest := len([]rune(na))
+	if n := len([]rune(nb)); n > longest {
+		longest = n
+	}
+	return 1 - float64(levenshteinDistance(na, nb))/float64(longest)
+}
+
+// findAlbumRowIDFuzzy ranks albums sharing a word with albumName by normalized similarity
+// and returns the best one above the configured threshold
+func findAlbumRowIDFuzzy(db *sql.DB, albumName string) (int, string, float64, error) {
+	threshold := getAlbumMatchThreshold()
+	if threshold <= 0 {
+		return 0, "", 0, sql.ErrNoRows
+	}
+
+	word := albumSearchWord(albumName)
+	if word == "" {
+		return 0, "", 0, sql.ErrNoRows
+	}
+
+	rows, err := db.Query("SELECT rowid, name FROM albums WHERE LOWER(name) LIKE LOWER(?) LIMIT ?", "%"+word+"%", maxAlbumMatchCandidates)
+	if err != nil {
+		return 0, "", 0, fmt.Errorf("failed to query albums: %v", err)
+	}
+	defer rows.Close()
+
+	bestRowID := 0
+	bestName := ""
+	bestScore := 0.0
+	for rows.Next() {
+		var rowID int
+		var name string
+		if err := rows.Scan(&rowID, &name); err != nil {
+			continue
+		}
+		if score := albumNameSimilarity(albumName, name); score > bestScore {
+			bestRowID, bestName, bestScore = rowID, name, score
+		}
+	}
+
+	if bestScore < threshold {
+		return 0, "", bestScore, sql.ErrNoRows
+	}
+	return bestRowID, bestName, bestScore, nil
+}
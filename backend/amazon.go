@@ -289,27 +289,6 @@ func (a *AmazonDownloader) DownloadFromService(amazonURL, outputDir string) (str
 
 				fmt.Printf("Downloading: %s - %s\n", artist, trackName)
 
-				// Download file
-				downloadReq, err := http.NewRequest("GET", fileURL, nil)
-				if err != nil {
-					lastError = fmt.Errorf("failed to create download request: %w", err)
-					break
-				}
-
-				downloadReq.Header.Set("User-Agent", a.getRandomUserAgent())
-
-				fileResp, err := a.client.Do(downloadReq)
-				if err != nil {
-					lastError = fmt.Errorf("failed to download file: %w", err)
-					break
-				}
-				defer fileResp.Body.Close()
-
-				if fileResp.StatusCode != 200 {
-					lastError = fmt.Errorf("download failed with status %d", fileResp.StatusCode)
-					break
-				}
-
 				// Generate filename
 				fileName := fmt.Sprintf("%s - %s.flac", artist, trackName)
 				for _, char := range `<>:"/\|?*` {
@@ -319,25 +298,19 @@ func (a *AmazonDownloader) DownloadFromService(amazonURL, outputDir string) (str
 
 				filePath := filepath.Join(outputDir, fileName)
 
-				// Save file
-				out, err := os.Create(filePath)
-				if err != nil {
-					lastError = fmt.Errorf("failed to create file: %w", err)
+				// Save file through a resumable .part
+				fmt.Println("Downloading...")
+				userAgent := a.getRandomUserAgent()
+				err = downloadResumable(a.client, fileURL, filePath, func(req *http.Request) {
+					req.Header.Set("User-Agent", userAgent)
+				})
+				if isExpiredURLError(err) {
+					lastError = err
 					break
 				}
-				defer out.Close()
-
-				fmt.Println("Downloading...")
-				// Use progress writer to track download
-				pw := NewProgressWriter(out)
-				_, err = io.Copy(pw, fileResp.Body)
 				if err != nil {
-					out.Close()
-					return "", fmt.Errorf("failed to write file: %w", err)
+					return "", err
 				}
-
-				// Print final size
-				fmt.Printf("\rDownloaded: %.2f MB (Complete)\n", float64(pw.GetTotal())/(1024*1024))
 				fmt.Println("Download complete!")
 				return filePath, nil
 
@@ -372,11 +345,11 @@ func (a *AmazonDownloader) DownloadFromService(amazonURL, outputDir string) (str
 	return "", fmt.Errorf("all regions failed. Last error: %v", lastError)
 }
 
-func (a *AmazonDownloader) DownloadByURL(amazonURL, outputDir, filenameFormat string, includeTrackNumber bool, position int, spotifyTrackName, spotifyArtistName, spotifyAlbumName, spotifyAlbumArtist, spotifyReleaseDate, spotifyCoverURL, spotifyISRC string, spotifyTrackNumber, spotifyDiscNumber, spotifyTotalTracks int, embedMaxQualityCover bool) (string, error) {
+func (a *AmazonDownloader) DownloadByURL(amazonURL, outputDir, filenameFormat string, includeTrackNumber bool, position int, spotifyTrackName, spotifyArtistName, spotifyAlbumName, spotifyAlbumArtist, spotifyReleaseDate, spotifyCoverURL, spotifyISRC string, spotifyTrackNumber, spotifyDiscNumber, spotifyTotalTracks int, embedMaxQualityCover bool) (DownloadResult, error) {
 	// Create output directory if needed
 	if outputDir != "." {
 		if err := os.MkdirAll(outputDir, 0755); err != nil {
-			return "", fmt.Errorf("failed to create output directory: %w", err)
+			return DownloadResult{}, fmt.Errorf("failed to create output directory: %w", err)
 		}
 	}
 
@@ -387,7 +360,7 @@ func (a *AmazonDownloader) DownloadByURL(amazonURL, outputDir, filenameFormat st
 
 		if fileInfo, err := os.Stat(expectedPath); err == nil && fileInfo.Size() > 0 {
 			fmt.Printf("File already exists: %s (%.2f MB)\n", expectedPath, float64(fileInfo.Size())/(1024*1024))
-			return "EXISTS:" + expectedPath, nil
+			return DownloadResult{Path: expectedPath, AlreadyExisted: true}, nil
 		}
 	}
 
@@ -396,7 +369,7 @@ func (a *AmazonDownloader) DownloadByURL(amazonURL, outputDir, filenameFormat st
 	// Download from service
 	filePath, err := a.DownloadFromService(amazonURL, outputDir)
 	if err != nil {
-		return "", err
+		return DownloadResult{}, err
 	}
 
 	// Rename file based on Spotify metadata
@@ -518,14 +491,14 @@ func (a *AmazonDownloader) DownloadByURL(amazonURL, outputDir, filenameFormat st
 
 	fmt.Println("Done")
 	fmt.Println("✓ Downloaded successfully from Amazon Music")
-	return filePath, nil
+	return DownloadResult{Path: filePath}, nil
 }
 
-func (a *AmazonDownloader) DownloadBySpotifyID(spotifyTrackID, outputDir, filenameFormat string, includeTrackNumber bool, position int, spotifyTrackName, spotifyArtistName, spotifyAlbumName, spotifyAlbumArtist, spotifyReleaseDate, spotifyCoverURL, spotifyISRC string, spotifyTrackNumber, spotifyDiscNumber, spotifyTotalTracks int, embedMaxQualityCover bool) (string, error) {
+func (a *AmazonDownloader) DownloadBySpotifyID(spotifyTrackID, outputDir, filenameFormat string, includeTrackNumber bool, position int, spotifyTrackName, spotifyArtistName, spotifyAlbumName, spotifyAlbumArtist, spotifyReleaseDate, spotifyCoverURL, spotifyISRC string, spotifyTrackNumber, spotifyDiscNumber, spotifyTotalTracks int, embedMaxQualityCover bool) (DownloadResult, error) {
 	// Get Amazon URL from Spotify track ID
 	amazonURL, err := a.GetAmazonURLFromSpotify(spotifyTrackID)
 	if err != nil {
-		return "", err
+		return DownloadResult{}, err
 	}
 
 	return a.DownloadByURL(amazonURL, outputDir, filenameFormat, includeTrackNumber, position, spotifyTrackName, spotifyArtistName, spotifyAlbumName, spotifyAlbumArtist, spotifyReleaseDate, spotifyCoverURL, spotifyISRC, spotifyTrackNumber, spotifyDiscNumber, spotifyTotalTracks, embedMaxQualityCover)
@@ -1,9 +1,12 @@
 package backend
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
 	"os"
+	"os/exec"
+	"strconv"
 
 	"github.com/go-flac/go-flac"
 	mewflac "github.com/mewkiz/flac"
@@ -23,6 +26,10 @@ type AnalysisResult struct {
 	PeakAmplitude float64       `json:"peak_amplitude"`
 	RMSLevel      float64       `json:"rms_level"`
 	Spectrum      *SpectrumData `json:"spectrum,omitempty"`
+
+	// Sample rate detected by ffprobe from the audio stream, compared against STREAMINFO
+	DetectedSampleRate uint32 `json:"detected_sample_rate,omitempty"`
+	SampleRateMismatch bool   `json:"sample_rate_mismatch"`
 }
 
 // AnalyzeTrack performs audio analysis on a FLAC file
@@ -98,9 +105,68 @@ func AnalyzeTrack(filepath string) (*AnalysisResult, error) {
 	// Set bit depth
 	result.BitDepth = fmt.Sprintf("%d-bit", result.BitsPerSample)
 
+	// Compare declared sample rate against what ffprobe detects in the stream
+	if installed, _ := IsFFprobeInstalled(); installed {
+		if detected, err := probeStreamSampleRate(filepath); err == nil {
+			result.DetectedSampleRate = detected
+			if result.SampleRate > 0 && detected != result.SampleRate {
+				result.SampleRateMismatch = true
+				fmt.Printf("Warning: sample rate mismatch in %s: STREAMINFO %d Hz, stream %d Hz\n", filepath, result.SampleRate, detected)
+			}
+		} else {
+			fmt.Printf("Warning: failed to probe sample rate: %v\n", err)
+		}
+	}
+
 	return result, nil
 }
 
+// probeStreamSampleRate returns the sample rate of the first audio stream as reported by ffprobe
+func probeStreamSampleRate(filePath string) (uint32, error) {
+	ffprobePath, err := GetFFprobePath()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := ValidateExecutable(ffprobePath); err != nil {
+		return 0, fmt.Errorf("invalid ffprobe executable: %w", err)
+	}
+
+	cmd := exec.Command(ffprobePath,
+		"-v", "error",
+		"-select_streams", "a:0",
+		"-show_entries", "stream=sample_rate",
+		"-print_format", "json",
+		filePath,
+	)
+
+	// Hide console window on Windows
+	setHideWindow(cmd)
+
+	output, err := ffmpegOutput(cmd)
+	if err != nil {
+		return 0, fmt.Errorf("ffprobe failed: %w", err)
+	}
+
+	var probe struct {
+		Streams []struct {
+			SampleRate string `json:"sample_rate"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(output, &probe); err != nil {
+		return 0, fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+	if len(probe.Streams) == 0 {
+		return 0, fmt.Errorf("no audio stream found")
+	}
+
+	rate, err := strconv.ParseUint(probe.Streams[0].SampleRate, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid sample rate: %s", probe.Streams[0].SampleRate)
+	}
+	return uint32(rate), nil
+}
+
 // calculateRealAudioMetrics calculates actual dynamic range, peak, and RMS from decoded audio
 func calculateRealAudioMetrics(result *AnalysisResult, filepath string) {
 	// Decode FLAC to get actual samples
@@ -0,0 +1,108 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// AnalysisProgress is emitted for every analyzed file during a library scan
+type AnalysisProgress struct {
+	Result  *AnalysisResult `json:"result,omitempty"`
+	Error   string          `json:"error,omitempty"`
+	File    string          `json:"file"`
+	Current int             `json:"current"`
+	Total   int             `json:"total"`
+}
+
+// AnalyzeLibrary analyzes every FLAC file under dirPath and calls onResult as each file
+// completes, so callers can display results before the scan finishes. The scan stops
+// early when ctx is cancelled. It returns the number of files analyzed.
+func AnalyzeLibrary(ctx context.Context, dirPath string, onResult func(AnalysisProgress)) (int, error) {
+	dirPath = NormalizePath(dirPath)
+	fmt.Printf("\n[Analysis] Scanning library: %s\n", dirPath)
+
+	files := make([]string, 0)
+	err := filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !info.IsDir() && strings.ToLower(filepath.Ext(path)) == ".flac" {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to scan directory: %w", err)
+	}
+
+	total := len(files)
+	fmt.Printf("[Analysis] Found %d FLAC files\n", total)
+
+	// Analysis is CPU bound, so limit workers to the number of cores
+	maxWorkers := runtime.NumCPU()
+	if maxWorkers > 8 {
+		maxWorkers = 8
+	}
+
+	var wg sync.WaitGroup
+	var callbackMu sync.Mutex
+	processed := int32(0)
+
+	fileChan := make(chan string)
+	go func() {
+		defer close(fileChan)
+		for _, f := range files {
+			select {
+			case <-ctx.Done():
+				return
+			case fileChan <- f:
+			}
+		}
+	}()
+
+	for w := 0; w < maxWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range fileChan {
+				if ctx.Err() != nil {
+					return
+				}
+
+				progress := AnalysisProgress{File: path, Total: total}
+				result, err := AnalyzeTrack(path)
+				if err != nil {
+					progress.Error = err.Error()
+				} else {
+					progress.Result = result
+				}
+				progress.Current = int(atomic.AddInt32(&processed, 1))
+
+				if onResult != nil {
+					callbackMu.Lock()
+					onResult(progress)
+					callbackMu.Unlock()
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		fmt.Printf("[Analysis] Scan cancelled after %d/%d files\n", processed, total)
+		return int(processed), ctx.Err()
+	}
+
+	fmt.Printf("[Analysis] Scan complete: %d files analyzed\n", processed)
+	return int(processed), nil
+}
@@ -0,0 +1,188 @@
+package backend
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ArchiveResult represents the result of creating a zip archive
+type ArchiveResult struct {
+	Success   bool   `json:"success"`
+	ZipPath   string `json:"zip_path"`
+	Size      int64  `json:"size"`
+	FileCount int    `json:"file_count"`
+	Skipped   int    `json:"skipped"`
+	Error     string `json:"error,omitempty"`
+}
+
+// sidecarExtensions are files next to a track that belong in the archive with it
+var sidecarExtensions = []string{".jpg", ".png", ".lrc", ".srt", ".txt"}
+
+// commonDir returns the deepest directory shared by all paths
+func commonDir(paths []string) string {
+	if len(paths) == 0 {
+		return ""
+	}
+	common := filepath.Dir(paths[0])
+	for _, p := range paths[1:] {
+		dir := filepath.Dir(p)
+		for common != "" && !strings.HasPrefix(dir+string(os.PathSeparator), common+string(os.PathSeparator)) {
+			parent := filepath.Dir(common)
+			if parent == common {
+				return ""
+			}
+			common = parent
+		}
+	}
+	return common
+}
+
+// CreateArchive writes the given files, plus their cover and lyrics sidecars when
+// includeSidecars is set, into a zip archive. Files are streamed into the archive one at
+// a time so large collections don't have to fit in memory.
+func CreateArchive(files []string, zipPath string, includeSidecars bool) (*ArchiveResult, error) {
+	if len(files) == 0 {
+		return &ArchiveResult{Success: false, Error: "no files to archive"}, fmt.Errorf("no files to archive")
+	}
+
+	zipPath = NormalizePath(zipPath)
+	if !strings.HasSuffix(strings.ToLower(zipPath), ".zip") {
+		zipPath += ".zip"
+	}
+
+	// Collect files, adding sidecars and removing duplicates
+	seen := make(map[string]bool)
+	entries := make([]string, 0, len(files))
+	for _, f := range files {
+		f = NormalizePath(f)
+		if !seen[f] {
+			seen[f] = true
+			entries = append(entries, f)
+		}
+		if !includeSidecars {
+			continue
+		}
+		base := strings.TrimSuffix(f, filepath.Ext(f))
+		for _, ext := range sidecarExtensions {
+			sidecar := base + ext
+			if !seen[sidecar] && fileExists(sidecar) {
+				seen[sidecar] = true
+				entries = append(entries, sidecar)
+			}
+		}
+	}
+
+	baseDir := commonDir(entries)
+
+	if err := os.MkdirAll(filepath.Dir(zipPath), 0755); err != nil {
+		return &ArchiveResult{Success: false, Error: err.Error()}, fmt.Errorf("failed to create archive directory: %v", err)
+	}
+
+	out, err := os.Create(zipPath)
+	if err != nil {
+		return &ArchiveResult{Success: false, Error: err.Error()}, fmt.Errorf("failed to create archive: %v", err)
+	}
+
+	result := &ArchiveResult{ZipPath: zipPath}
+	zw := zip.NewWriter(out)
+
+	fmt.Printf("[Archive] Creating %s with %d files\n", zipPath, len(entries))
+	for _, path := range entries {
+		if err := addFileToZip(zw, path, baseDir); err != nil {
+			fmt.Printf("[Archive] Skipping %s: %v\n", path, err)
+			result.Skipped++
+			continue
+		}
+		result.FileCount++
+	}
+
+	if err := zw.Close(); err != nil {
+		out.Close()
+		os.Remove(zipPath)
+		return &ArchiveResult{Success: false, Error: err.Error()}, fmt.Errorf("failed to finalize archive: %v", err)
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(zipPath)
+		return &ArchiveResult{Success: false, Error: err.Error()}, fmt.Errorf("failed to write archive: %v", err)
+	}
+
+	if info, err := os.Stat(zipPath); err == nil {
+		result.Size = info.Size()
+	}
+	result.Success = true
+
+	fmt.Printf("[Archive] ✓ Archived %d files (%.2f MB)\n", result.FileCount, float64(result.Size)/(1024*1024))
+	return result, nil
+}
+
+// BatchArchivePath returns where the archive of a finished download batch is written
+func BatchArchivePath(outputDir, name string) string {
+	name = sanitizeFilename(name)
+	if name == "" {
+		name = "download"
+	}
+	return filepath.Join(outputDir, name+".zip")
+}
+
+// ArchiveBatch waits until every item of a download batch has finished and been enriched, then
+// zips the downloaded or already present files with their sidecars into zipPath
+func ArchiveBatch(itemIDs []string, zipPath string) (*ArchiveResult, error) {
+	for {
+		files, done := batchItemFiles(itemIDs)
+		if done {
+			// Enrichment rewrites the files and may move them, so archive once it settles
+			WaitForPostProcessing()
+			files, _ = batchItemFiles(itemIDs)
+			return CreateArchive(files, zipPath, true)
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// addFileToZip streams a single file into the archive, storing audio uncompressed since
+// it is already compressed
+func addFileToZip(zw *zip.Writer, path, baseDir string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+
+	name := filepath.Base(path)
+	if baseDir != "" {
+		if rel, err := filepath.Rel(baseDir, path); err == nil {
+			name = rel
+		}
+	}
+	header.Name = filepath.ToSlash(name)
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".flac", ".mp3", ".m4a", ".jpg", ".png":
+		header.Method = zip.Store
+	default:
+		header.Method = zip.Deflate
+	}
+
+	w, err := zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, f)
+	return err
+}
@@ -0,0 +1,254 @@
+package backend
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	id3v2 "github.com/bogem/id3v2/v2"
+	"github.com/go-flac/flacpicture"
+	"github.com/go-flac/go-flac"
+)
+
+var (
+	stripDuplicateArt     bool
+	stripDuplicateArtLock sync.RWMutex
+)
+
+// SetStripDuplicateArt toggles whether art deduplication rewrites files: when on, an album whose
+// tracks all embed the same picture gets a cover.jpg sidecar and the embedded copies are removed
+func SetStripDuplicateArt(enabled bool) {
+	stripDuplicateArtLock.Lock()
+	stripDuplicateArt = enabled
+	stripDuplicateArtLock.Unlock()
+}
+
+func isStripDuplicateArtEnabled() bool {
+	stripDuplicateArtLock.RLock()
+	defer stripDuplicateArtLock.RUnlock()
+	return stripDuplicateArt
+}
+
+// ArtDedupAlbum is the outcome for one album directory
+type ArtDedupAlbum struct {
+	Dir           string `json:"dir"`
+	Tracks        int    `json:"tracks"`
+	Identical     bool   `json:"identical"`                // Every track embeds the same picture
+	Redundant     int64  `json:"redundant_bytes"`          // Bytes taken by all but one embedded copy
+	SavedBytes    int64  `json:"saved_bytes"`              // Bytes actually freed
+	SidecarPath   string `json:"sidecar_path,omitempty"`   // cover sidecar the art was moved to
+	StrippedFiles int    `json:"stripped_files,omitempty"` // Tracks the embedded art was removed from
+	Error         string `json:"error,omitempty"`
+}
+
+// ArtDedupResult summarizes a library deduplication run
+type ArtDedupResult struct {
+	Albums         []ArtDedupAlbum `json:"albums"`
+	RedundantBytes int64           `json:"redundant_bytes"`
+	SavedBytes     int64           `json:"saved_bytes"`
+	Stripped       bool            `json:"stripped"` // Whether files were rewritten or this was a report only
+}
+
+// embeddedArt is the front cover stored in one audio file
+type embeddedArt struct {
+	data []byte
+	mime string
+}
+
+// readEmbeddedArt returns the first picture embedded in a FLAC or MP3 file, nil when there is none
+func readEmbeddedArt(filePath string) (*embeddedArt, error) {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".flac":
+		f, err := flac.ParseFile(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse FLAC file: %w", err)
+		}
+		for _, block := range f.Meta {
+			if block.Type != flac.Picture {
+				continue
+			}
+			pic, err := flacpicture.ParseFromMetaDataBlock(*block)
+			if err != nil {
+				continue
+			}
+			return &embeddedArt{data: pic.ImageData, mime: pic.MIME}, nil
+		}
+		return nil, nil
+	case ".mp3":
+		tag, err := id3v2.Open(filePath, id3v2.Options{Parse: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to open MP3 file: %w", err)
+		}
+		defer tag.Close()
+		for _, frame := range tag.GetFrames(tag.CommonID("Attached picture")) {
+			if pic, ok := frame.(id3v2.PictureFrame); ok && len(pic.Picture) > 0 {
+				return &embeddedArt{data: pic.Picture, mime: pic.MimeType}, nil
+			}
+		}
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unsupported file format: %s", filepath.Ext(filePath))
+	}
+}
+
+// stripEmbeddedArt removes every embedded picture from a FLAC or MP3 file
+func stripEmbeddedArt(filePath string) error {
+	restore, err := prepareWritable(filePath)
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".flac":
+		f, err := flac.ParseFile(filePath)
+		if err != nil {
+			return fmt.Errorf("failed to parse FLAC file: %w", err)
+		}
+		for i := len(f.Meta) - 1; i >= 0; i-- {
+			if f.Meta[i].Type == flac.Picture {
+				f.Meta = append(f.Meta[:i], f.Meta[i+1:]...)
+			}
+		}
+		if err := saveFLACFile(f, filePath); err != nil {
+			return fmt.Errorf("failed to save FLAC file: %w", err)
+		}
+		return nil
+	case ".mp3":
+		tag, err := id3v2.Open(filePath, id3v2.Options{Parse: true})
+		if err != nil {
+			return fmt.Errorf("failed to open MP3 file: %w", err)
+		}
+		defer tag.Close()
+		tag.DeleteFrames(tag.CommonID("Attached picture"))
+		if err := tag.Save(); err != nil {
+			return fmt.Errorf("failed to save MP3 tags: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported file format: %s", filepath.Ext(filePath))
+	}
+}
+
+func fileSize(path string) int64 {
+	if info, err := os.Stat(path); err == nil {
+		return info.Size()
+	}
+	return 0
+}
+
+// DeduplicateEmbeddedArt looks for album directories whose tracks all embed the same picture.
+// Without stripping enabled it only reports the redundant bytes; with it, the art is written
+// once as a cover sidecar and removed from the tracks. Albums with differing art (compilations,
+// per-track art) and M4A files are left alone.
+func DeduplicateEmbeddedArt(dirPath string) (*ArtDedupResult, error) {
+	dirPath = NormalizePath(dirPath)
+	if _, err := os.Stat(dirPath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("directory does not exist: %s", dirPath)
+	}
+
+	albums := make(map[string][]string)
+	err := filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".flac", ".mp3":
+			albums[filepath.Dir(path)] = append(albums[filepath.Dir(path)], path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan directory: %v", err)
+	}
+
+	dirs := make([]string, 0, len(albums))
+	for dir, files := range albums {
+		if len(files) > 1 {
+			dirs = append(dirs, dir)
+		}
+	}
+	sort.Strings(dirs)
+
+	strip := isStripDuplicateArtEnabled()
+	result := &ArtDedupResult{Albums: make([]ArtDedupAlbum, 0, len(dirs)), Stripped: strip}
+	for _, dir := range dirs {
+		album := dedupAlbumArt(dir, albums[dir], strip)
+		result.RedundantBytes += album.Redundant
+		result.SavedBytes += album.SavedBytes
+		result.Albums = append(result.Albums, album)
+	}
+
+	fmt.Printf("[Art Dedup] %d albums, %d redundant bytes, %d bytes saved\n", len(dirs), result.RedundantBytes, result.SavedBytes)
+	return result, nil
+}
+
+func dedupAlbumArt(dir string, files []string, strip bool) ArtDedupAlbum {
+	album := ArtDedupAlbum{Dir: dir, Tracks: len(files)}
+
+	var first *embeddedArt
+	var firstHash [sha1.Size]byte
+	for _, file := range files {
+		art, err := readEmbeddedArt(file)
+		if err != nil {
+			album.Error = fmt.Sprintf("%s: %v", filepath.Base(file), err)
+			return album
+		}
+		if art == nil {
+			// A track without art means the album isn't uniform; stripping the rest would lose nothing but is pointless
+			return album
+		}
+		hash := sha1.Sum(art.data)
+		if first == nil {
+			first, firstHash = art, hash
+			continue
+		}
+		if hash != firstHash {
+			return album
+		}
+	}
+
+	album.Identical = true
+	album.Redundant = int64(len(first.data)) * int64(len(files)-1)
+	if !strip {
+		return album
+	}
+
+	sidecar := filepath.Join(dir, "cover.jpg")
+	if first.mime == "image/png" {
+		sidecar = filepath.Join(dir, "cover.png")
+	}
+	// Only drop the embedded copies once a sidecar with exactly this picture exists
+	if existing, err := os.ReadFile(sidecar); err == nil {
+		if !bytes.Equal(existing, first.data) {
+			album.Error = fmt.Sprintf("%s already exists with different art", filepath.Base(sidecar))
+			return album
+		}
+	} else {
+		if err := os.WriteFile(sidecar, first.data, 0644); err != nil {
+			album.Error = fmt.Sprintf("failed to write %s: %v", filepath.Base(sidecar), err)
+			return album
+		}
+		album.SavedBytes -= int64(len(first.data))
+	}
+	album.SidecarPath = sidecar
+
+	for _, file := range files {
+		before := fileSize(file)
+		if err := stripEmbeddedArt(file); err != nil {
+			fmt.Printf("[Art Dedup] Failed to strip art from %s: %v\n", file, err)
+			album.Error = fmt.Sprintf("%s: %v", filepath.Base(file), err)
+			continue
+		}
+		album.SavedBytes += before - fileSize(file)
+		album.StrippedFiles++
+	}
+
+	fmt.Printf("[Art Dedup] %s: moved art to %s, saved %d bytes\n", dir, filepath.Base(sidecar), album.SavedBytes)
+	return album
+}
@@ -0,0 +1,201 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// ArtistFolderMerge describes one set of artist folders folded into a canonical folder
+type ArtistFolderMerge struct {
+	Canonical  string   `json:"canonical"`
+	Variants   []string `json:"variants"`
+	MovedFiles int      `json:"moved_files"`
+	Conflicts  []string `json:"conflicts,omitempty"` // Files left in place because the target already exists
+	Error      string   `json:"error,omitempty"`
+}
+
+// ArtistFolderMergeResult reports the merges done (or planned, for a dry run) under a library root
+type ArtistFolderMergeResult struct {
+	Success       bool                `json:"success"`
+	RootPath      string              `json:"root_path"`
+	DryRun        bool                `json:"dry_run"`
+	Merges        []ArtistFolderMerge `json:"merges"`
+	MergedFolders int                 `json:"merged_folders"`
+	MovedFiles    int                 `json:"moved_files"`
+	Error         string              `json:"error,omitempty"`
+}
+
+// artistFolderKey folds the differences that split one artist across folders: accents,
+// case, repeated or trailing whitespace and trailing dots
+func artistFolderKey(name string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(name) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	key := strings.Join(strings.Fields(b.String()), " ")
+	return strings.TrimRight(key, ". ")
+}
+
+// MergeArtistFolders finds artist folders directly under rootPath that are variants of the same
+// name and moves their contents into one canonical folder. aliases maps a folder name (or any
+// variant of it) to the canonical name to use; without an alias the folder holding the most
+// files wins. With dryRun set nothing is moved.
+func MergeArtistFolders(rootPath string, aliases map[string]string, dryRun bool) (*ArtistFolderMergeResult, error) {
+	rootPath = NormalizePath(rootPath)
+	result := &ArtistFolderMergeResult{Success: true, RootPath: rootPath, DryRun: dryRun}
+
+	entries, err := os.ReadDir(rootPath)
+	if err != nil {
+		result.Success = false
+		result.Error = fmt.Sprintf("Failed to read directory: %v", err)
+		return result, err
+	}
+
+	aliasByKey := make(map[string]string, len(aliases))
+	for variant, canonical := range aliases {
+		if canonical = strings.TrimSpace(canonical); canonical != "" {
+			aliasByKey[artistFolderKey(variant)] = canonical
+		}
+	}
+
+	groups := make(map[string][]string)
+	var order []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		key := artistFolderKey(entry.Name())
+		if canonical, ok := aliasByKey[key]; ok {
+			key = artistFolderKey(canonical)
+		}
+		if _, seen := groups[key]; !seen {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], entry.Name())
+	}
+
+	for _, key := range order {
+		folders := groups[key]
+		canonical := pickCanonicalArtistFolder(rootPath, folders, aliasByKey[key])
+
+		var variants []string
+		for _, folder := range folders {
+			if folder != canonical {
+				variants = append(variants, folder)
+			}
+		}
+		if len(variants) == 0 {
+			continue
+		}
+
+		merge := ArtistFolderMerge{Canonical: canonical, Variants: variants}
+		if !dryRun {
+			mergeArtistVariants(rootPath, &merge)
+		}
+		fmt.Printf("[Artist Merge] %s <- %v (%d files moved)\n", canonical, variants, merge.MovedFiles)
+
+		result.Merges = append(result.Merges, merge)
+		result.MergedFolders += len(variants)
+		result.MovedFiles += merge.MovedFiles
+	}
+
+	fmt.Printf("[Artist Merge] %d folders merged, %d files moved (dry run: %v)\n", result.MergedFolders, result.MovedFiles, dryRun)
+	return result, nil
+}
+
+// pickCanonicalArtistFolder prefers the configured alias, then the folder with the most files,
+// then a name without stray whitespace
+func pickCanonicalArtistFolder(rootPath string, folders []string, alias string) string {
+	if alias != "" {
+		return sanitizeFolderName(alias)
+	}
+
+	counts := make(map[string]int, len(folders))
+	for _, folder := range folders {
+		counts[folder] = countFiles(filepath.Join(rootPath, folder))
+	}
+
+	sorted := append([]string(nil), folders...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if counts[a] != counts[b] {
+			return counts[a] > counts[b]
+		}
+		aClean, bClean := a == strings.TrimSpace(a), b == strings.TrimSpace(b)
+		if aClean != bClean {
+			return aClean
+		}
+		return a < b
+	})
+	return sorted[0]
+}
+
+func countFiles(dir string) int {
+	count := 0
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			count++
+		}
+		return nil
+	})
+	return count
+}
+
+// mergeArtistVariants moves every file of each variant folder into the canonical folder,
+// keeping the relative layout, and removes variant folders that end up empty
+func mergeArtistVariants(rootPath string, merge *ArtistFolderMerge) {
+	canonicalDir := filepath.Join(rootPath, merge.Canonical)
+	if err := os.MkdirAll(canonicalDir, 0755); err != nil {
+		merge.Error = fmt.Sprintf("failed to create %s: %v", merge.Canonical, err)
+		return
+	}
+
+	for _, variant := range merge.Variants {
+		variantDir := filepath.Join(rootPath, variant)
+		var dirs []string
+
+		filepath.Walk(variantDir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return nil
+			}
+			if info.IsDir() {
+				dirs = append(dirs, path)
+				return nil
+			}
+
+			rel, err := filepath.Rel(variantDir, path)
+			if err != nil {
+				return nil
+			}
+			target := filepath.Join(canonicalDir, rel)
+			if fileExists(target) {
+				merge.Conflicts = append(merge.Conflicts, path)
+				return nil
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				merge.Conflicts = append(merge.Conflicts, path)
+				return nil
+			}
+			if err := os.Rename(path, target); err != nil {
+				merge.Conflicts = append(merge.Conflicts, path)
+				return nil
+			}
+			merge.MovedFiles++
+			return nil
+		})
+
+		// Deepest first so parents are empty by the time they are removed
+		for i := len(dirs) - 1; i >= 0; i-- {
+			os.Remove(dirs[i])
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const maxArtistImageWorkers = 4
+
+var (
+	artistImageFilename     string
+	artistImageFilenameLock sync.RWMutex
+)
+
+// SetArtistImageFilename sets the artist image layout. Empty saves "<Artist>.jpg" in the output
+// folder; a name such as "folder.jpg" saves "<Artist>/folder.jpg" for library browsers that
+// expect one folder per artist.
+func SetArtistImageFilename(name string) {
+	artistImageFilenameLock.Lock()
+	artistImageFilename = strings.TrimSpace(name)
+	artistImageFilenameLock.Unlock()
+}
+
+func getArtistImageFilename() string {
+	artistImageFilenameLock.RLock()
+	defer artistImageFilenameLock.RUnlock()
+	return artistImageFilename
+}
+
+// artistImagePath builds where an artist's image is saved under outDir
+func artistImagePath(outDir, artistName string) string {
+	if fixed := getArtistImageFilename(); fixed != "" {
+		return filepath.Join(outDir, sanitizeFolderName(artistName), fixed)
+	}
+	return filepath.Join(outDir, sanitizeFilename(artistName)+".jpg")
+}
+
+// DownloadArtistImages fetches each artist's Spotify image and saves it named by artist,
+// skipping images that already exist. Results are in the same order as artistIDs.
+func DownloadArtistImages(ctx context.Context, artistIDs []string, outDir string) []CoverDownloadResponse {
+	results := make([]CoverDownloadResponse, len(artistIDs))
+
+	outDir = NormalizePath(outDir)
+	if outDir == "" {
+		outDir = GetDefaultMusicPath()
+	}
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		for i := range results {
+			results[i] = CoverDownloadResponse{Success: false, Error: fmt.Sprintf("failed to create output directory: %v", err)}
+		}
+		return results
+	}
+
+	client := NewSpotifyMetadataClient()
+	token, err := client.getAccessToken(ctx)
+	if err != nil {
+		for i := range results {
+			results[i] = CoverDownloadResponse{Success: false, Error: fmt.Sprintf("failed to get Spotify token: %v", err)}
+		}
+		return results
+	}
+
+	coverClient := NewCoverClient()
+	indexChan := make(chan int, len(artistIDs))
+	for i := range artistIDs {
+		indexChan <- i
+	}
+	close(indexChan)
+
+	var wg sync.WaitGroup
+	for w := 0; w < maxArtistImageWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexChan {
+				results[i] = downloadArtistImage(ctx, client, coverClient, token, artistIDs[i], outDir)
+			}
+		}()
+	}
+	wg.Wait()
+
+	downloaded := 0
+	for _, result := range results {
+		if result.Success && !result.AlreadyExists {
+			downloaded++
+		}
+	}
+	fmt.Printf("[Artist Images] Downloaded %d/%d artist images\n", downloaded, len(artistIDs))
+	return results
+}
+
+func downloadArtistImage(ctx context.Context, client *SpotifyMetadataClient, coverClient *CoverClient, token, artistID, outDir string) CoverDownloadResponse {
+	if artistID == "" {
+		return CoverDownloadResponse{Success: false, Error: "artist ID is required"}
+	}
+
+	artist, err := client.fetchArtist(ctx, artistID, token)
+	if err != nil {
+		return CoverDownloadResponse{Success: false, Error: fmt.Sprintf("failed to fetch artist %s: %v", artistID, err)}
+	}
+
+	// Spotify lists artist images largest first
+	imageURL := firstImageURL(artist.Images)
+	if imageURL == "" {
+		return CoverDownloadResponse{Success: false, Error: fmt.Sprintf("no image for artist: %s", artist.Name)}
+	}
+
+	imagePath := artistImagePath(outDir, artist.Name)
+	if info, err := os.Stat(imagePath); err == nil && info.Size() > 0 {
+		return CoverDownloadResponse{
+			Success:       true,
+			Message:       "Artist image already exists",
+			File:          imagePath,
+			AlreadyExists: true,
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(imagePath), 0755); err != nil {
+		return CoverDownloadResponse{Success: false, Error: fmt.Sprintf("failed to create directory: %v", err)}
+	}
+	if err := coverClient.DownloadCoverToPath(imageURL, imagePath, false); err != nil {
+		os.Remove(imagePath)
+		return CoverDownloadResponse{Success: false, Error: err.Error()}
+	}
+
+	return CoverDownloadResponse{
+		Success: true,
+		Message: "Artist image downloaded successfully",
+		File:    imagePath,
+	}
+}
@@ -0,0 +1,60 @@
+package backend
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/go-flac/flacvorbis"
+)
+
+// displayArtistField holds the joined artist string when ARTIST is written once per artist
+const displayArtistField = "DISPLAY ARTIST"
+
+var (
+	multiArtistTags     bool
+	multiArtistTagsLock sync.RWMutex
+)
+
+// SetMultiArtistTags toggles writing one ARTIST Vorbis comment per artist instead of a single joined value
+func SetMultiArtistTags(enabled bool) {
+	multiArtistTagsLock.Lock()
+	multiArtistTags = enabled
+	multiArtistTagsLock.Unlock()
+}
+
+func getMultiArtistTags() bool {
+	multiArtistTagsLock.RLock()
+	defer multiArtistTagsLock.RUnlock()
+	return multiArtistTags
+}
+
+// splitArtists splits Spotify's ", "-joined artist string into individual names
+func splitArtists(joined string) []string {
+	var artists []string
+	for _, artist := range strings.Split(joined, ", ") {
+		if artist = strings.TrimSpace(artist); artist != "" {
+			artists = append(artists, artist)
+		}
+	}
+	return artists
+}
+
+// joinArtistValues merges repeated ARTIST values back into the joined form the rest of the app expects
+func joinArtistValues(values []string) string {
+	return strings.Join(values, ", ")
+}
+
+// addArtistComments writes the ARTIST field, either as one joined value or, when enabled,
+// once per artist with the joined value kept in DISPLAY ARTIST for players that read only one
+func addArtistComments(cmt *flacvorbis.MetaDataBlockVorbisComment, artist string) {
+	artists := splitArtists(artist)
+	if !getMultiArtistTags() || len(artists) < 2 {
+		_ = cmt.Add(flacvorbis.FIELD_ARTIST, artist)
+		return
+	}
+
+	for _, name := range artists {
+		_ = cmt.Add(flacvorbis.FIELD_ARTIST, name)
+	}
+	_ = cmt.Add(displayArtistField, artist)
+}
@@ -0,0 +1,171 @@
+package backend
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/go-flac/go-flac"
+)
+
+const (
+	// A file is flagged when it differs from Spotify by more than this many seconds
+	// and by more than auditTolerancePercent of the expected length
+	auditToleranceSeconds = 5.0
+	auditTolerancePercent = 0.05
+)
+
+// Audit statuses for a single track
+const (
+	AuditStatusOK         = "ok"
+	AuditStatusMismatch   = "mismatch"
+	AuditStatusMissing    = "missing"
+	AuditStatusUnreadable = "unreadable"
+)
+
+// AuditTrackResult compares one downloaded file against its Spotify duration
+type AuditTrackResult struct {
+	Track           CSVTrack `json:"track"`
+	Status          string   `json:"status"`
+	FilePath        string   `json:"file_path,omitempty"`
+	MatchedBy       string   `json:"matched_by,omitempty"`
+	ExpectedSeconds float64  `json:"expected_seconds"`
+	ActualSeconds   float64  `json:"actual_seconds,omitempty"`
+	DiffSeconds     float64  `json:"diff_seconds,omitempty"`
+	Error           string   `json:"error,omitempty"`
+}
+
+// AuditReport summarizes a post-batch duration audit
+type AuditReport struct {
+	Success    bool               `json:"success"`
+	Total      int                `json:"total"`
+	OK         int                `json:"ok"`
+	Mismatched int                `json:"mismatched"`
+	Missing    int                `json:"missing"`
+	Unreadable int                `json:"unreadable"`
+	Tracks     []AuditTrackResult `json:"tracks"`
+	Error      string             `json:"error,omitempty"`
+}
+
+// readAudioDuration returns a file's duration in seconds, from STREAMINFO for FLAC
+// and from ffprobe for everything else
+func readAudioDuration(filePath string) (float64, error) {
+	if strings.ToLower(filepath.Ext(filePath)) == ".flac" {
+		f, err := flac.ParseFile(filePath)
+		if err == nil && len(f.Meta) > 0 && f.Meta[0].Type == flac.StreamInfo && len(f.Meta[0].Data) >= 18 {
+			data := f.Meta[0].Data
+			sampleRate := uint32(data[10])<<12 | uint32(data[11])<<4 | uint32(data[12])>>4
+			totalSamples := uint64(data[13]&0x0F)<<32 |
+				uint64(data[14])<<24 |
+				uint64(data[15])<<16 |
+				uint64(data[16])<<8 |
+				uint64(data[17])
+			if sampleRate > 0 && totalSamples > 0 {
+				return float64(totalSamples) / float64(sampleRate), nil
+			}
+		}
+	}
+	return probeAudioDuration(filePath)
+}
+
+// isDurationMismatch reports whether actual is too far from expected to be the same edit
+func isDurationMismatch(expected, actual float64) bool {
+	diff := math.Abs(actual - expected)
+	return diff > auditToleranceSeconds && diff > expected*auditTolerancePercent
+}
+
+// AuditBatchAgainstSpotify locates each track in dirPath and compares the file's duration
+// to the Spotify duration, flagging likely wrong versions and tracks that never arrived
+func AuditBatchAgainstSpotify(dirPath string, tracks []CSVTrack) (*AuditReport, error) {
+	dirPath = NormalizePath(dirPath)
+	if _, err := os.Stat(dirPath); os.IsNotExist(err) {
+		return &AuditReport{Success: false, Error: fmt.Sprintf("Directory does not exist: %s", dirPath)},
+			fmt.Errorf("directory does not exist: %s", dirPath)
+	}
+
+	index, err := buildFolderIndex(dirPath, defaultCompareConcurrency)
+	if err != nil {
+		return &AuditReport{Success: false, Error: err.Error()}, err
+	}
+
+	report := &AuditReport{
+		Success: true,
+		Total:   len(tracks),
+		Tracks:  make([]AuditTrackResult, len(tracks)),
+	}
+
+	var wg sync.WaitGroup
+	jobs := make(chan int, len(tracks))
+	for i := range tracks {
+		jobs <- i
+	}
+	close(jobs)
+
+	for w := 0; w < defaultCompareConcurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				report.Tracks[i] = auditTrack(index, tracks[i])
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, result := range report.Tracks {
+		switch result.Status {
+		case AuditStatusOK:
+			report.OK++
+		case AuditStatusMismatch:
+			report.Mismatched++
+		case AuditStatusMissing:
+			report.Missing++
+		case AuditStatusUnreadable:
+			report.Unreadable++
+		}
+	}
+
+	fmt.Printf("[Audit] %d tracks: %d ok, %d duration mismatches, %d missing, %d unreadable\n",
+		report.Total, report.OK, report.Mismatched, report.Missing, report.Unreadable)
+	return report, nil
+}
+
+func auditTrack(index *folderIndex, track CSVTrack) AuditTrackResult {
+	result := AuditTrackResult{
+		Track:           track,
+		ExpectedSeconds: float64(track.DurationMs) / 1000,
+	}
+
+	path, matchedBy := index.find(track)
+	if path == "" {
+		result.Status = AuditStatusMissing
+		return result
+	}
+	result.FilePath = path
+	result.MatchedBy = matchedBy
+
+	actual, err := readAudioDuration(path)
+	if err != nil {
+		result.Status = AuditStatusUnreadable
+		result.Error = err.Error()
+		return result
+	}
+	result.ActualSeconds = actual
+
+	// Without a Spotify duration there is nothing to compare against
+	if track.DurationMs <= 0 {
+		result.Status = AuditStatusOK
+		return result
+	}
+
+	result.DiffSeconds = actual - result.ExpectedSeconds
+	if isDurationMismatch(result.ExpectedSeconds, actual) {
+		result.Status = AuditStatusMismatch
+	} else {
+		result.Status = AuditStatusOK
+	}
+	return result
+}
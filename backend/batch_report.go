@@ -0,0 +1,45 @@
+package backend
+
+// UnavailableTrack is a track no supported service carries, with what users need to look for it elsewhere
+type UnavailableTrack struct {
+	TrackName  string `json:"track_name"`
+	ArtistName string `json:"artist_name"`
+	AlbumName  string `json:"album_name"`
+	ISRC       string `json:"isrc"`
+	SpotifyURL string `json:"spotify_url,omitempty"`
+}
+
+// BatchReport summarizes the current queue, keeping "no source exists" apart from "download errored"
+type BatchReport struct {
+	Completed   int                `json:"completed"`
+	Skipped     int                `json:"skipped"`
+	Failed      []DownloadItem     `json:"failed"`
+	Unavailable []UnavailableTrack `json:"unavailable"`
+}
+
+// GetBatchReport builds a report of the current download queue
+func GetBatchReport() BatchReport {
+	queue := GetDownloadQueue()
+
+	report := BatchReport{
+		Completed:   queue.CompletedCount,
+		Skipped:     queue.SkippedCount,
+		Failed:      make([]DownloadItem, 0),
+		Unavailable: make([]UnavailableTrack, 0),
+	}
+	for _, item := range queue.Queue {
+		switch item.Status {
+		case StatusFailed:
+			report.Failed = append(report.Failed, item)
+		case StatusUnavailable:
+			report.Unavailable = append(report.Unavailable, UnavailableTrack{
+				TrackName:  item.TrackName,
+				ArtistName: item.ArtistName,
+				AlbumName:  item.AlbumName,
+				ISRC:       item.ISRC,
+				SpotifyURL: item.SpotifyURL,
+			})
+		}
+	}
+	return report
+}
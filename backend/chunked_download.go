@@ -0,0 +1,248 @@
+package backend
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	defaultChunkedMinSizeMB = 50
+	maxDownloadChunks       = 16
+	chunkReadSize           = 64 * 1024
+)
+
+var (
+	downloadChunks     int // 0 or 1 keeps the single-stream download
+	chunkedMinSizeMB   = defaultChunkedMinSizeMB
+	chunkedDownloadMux sync.RWMutex
+
+	contentRangeTotal = regexp.MustCompile(`^bytes \d+-\d+/(\d+)$`)
+	md5ETag           = regexp.MustCompile(`^"?([0-9a-fA-F]{32})"?$`)
+)
+
+// SetChunkedDownload sets how many ranged connections a single large file is split across and
+// the size below which files are still fetched in one stream
+func SetChunkedDownload(chunks, minSizeMB int) {
+	if chunks > maxDownloadChunks {
+		chunks = maxDownloadChunks
+	}
+	if minSizeMB <= 0 {
+		minSizeMB = defaultChunkedMinSizeMB
+	}
+
+	chunkedDownloadMux.Lock()
+	downloadChunks = chunks
+	chunkedMinSizeMB = minSizeMB
+	chunkedDownloadMux.Unlock()
+}
+
+func getChunkedDownload() (int, int64) {
+	chunkedDownloadMux.RLock()
+	defer chunkedDownloadMux.RUnlock()
+	return downloadChunks, int64(chunkedMinSizeMB) * 1024 * 1024
+}
+
+// rangeProbe is what a one-byte ranged request tells us about the remote file
+type rangeProbe struct {
+	size int64
+	etag string
+}
+
+// probeRangeSupport asks for the first byte of url. Only a 206 with a full Content-Range counts
+// as Range support; anything else means the caller should use a single stream.
+func probeRangeSupport(client *http.Client, url string) (*rangeProbe, bool) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, false
+	}
+	req.Header.Set("Range", "bytes=0-0")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, false
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != http.StatusPartialContent {
+		return nil, false
+	}
+	match := contentRangeTotal.FindStringSubmatch(resp.Header.Get("Content-Range"))
+	if match == nil {
+		return nil, false
+	}
+	size, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil || size <= 0 {
+		return nil, false
+	}
+	return &rangeProbe{size: size, etag: resp.Header.Get("ETag")}, true
+}
+
+// downloadChunked fetches url over several ranged connections into filepath. It returns false
+// without touching filepath when chunking is disabled, the file is small or the server doesn't
+// support Range, and removes a partial file on error, so the caller can fall back to a single stream.
+func downloadChunked(client *http.Client, url, filepath string) (bool, error) {
+	chunks, minSize := getChunkedDownload()
+	if chunks <= 1 {
+		return false, nil
+	}
+
+	probe, ok := probeRangeSupport(client, url)
+	if !ok {
+		fmt.Println("[Chunked] Server doesn't support range requests, using single stream")
+		return false, nil
+	}
+	if probe.size < minSize {
+		return false, nil
+	}
+
+	fmt.Printf("[Chunked] Downloading %.2f MB in %d parts\n", float64(probe.size)/(1024*1024), chunks)
+	if err := fetchChunks(client, url, filepath, probe, chunks); err != nil {
+		os.Remove(filepath)
+		return false, err
+	}
+	return true, nil
+}
+
+func fetchChunks(client *http.Client, url, filepath string, probe *rangeProbe, chunks int) error {
+	out, err := os.Create(filepath)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer out.Close()
+
+	if err := out.Truncate(probe.size); err != nil {
+		return fmt.Errorf("failed to allocate file: %w", err)
+	}
+
+	// Parts write at their own offsets; progress is counted through one shared writer
+	var progressMu sync.Mutex
+	pw := NewProgressWriter(io.Discard)
+	pw.setTransferSize(0, probe.size)
+	counted := make([]byte, chunkReadSize)
+	report := func(n int) {
+		progressMu.Lock()
+		pw.Write(counted[:n])
+		progressMu.Unlock()
+	}
+
+	chunkSize := (probe.size + int64(chunks) - 1) / int64(chunks)
+	errs := make([]error, chunks)
+	var wg sync.WaitGroup
+	for i := 0; i < chunks; i++ {
+		start := int64(i) * chunkSize
+		if start >= probe.size {
+			break
+		}
+		end := start + chunkSize - 1
+		if end >= probe.size {
+			end = probe.size - 1
+		}
+
+		wg.Add(1)
+		go func(i int, start, end int64) {
+			defer wg.Done()
+			errs[i] = fetchChunk(client, url, out, start, end, probe.etag, report)
+		}(i, start, end)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return fmt.Errorf("part %d failed: %w", i+1, err)
+		}
+	}
+
+	if err := verifyChunkedFile(out, probe); err != nil {
+		return err
+	}
+
+	fmt.Printf("\rDownloaded: %.2f MB (Complete)\n", float64(pw.GetTotal())/(1024*1024))
+	return nil
+}
+
+// fetchChunk downloads bytes start..end into out at the same offset. If-Range makes the server
+// send the whole file instead of a part if it changed since the probe, which is treated as an error.
+func fetchChunk(client *http.Client, url string, out *os.File, start, end int64, etag string, report func(int)) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+	if etag != "" {
+		req.Header.Set("If-Range", etag)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusPartialContent {
+		return fmt.Errorf("unexpected status %d for range %d-%d", resp.StatusCode, start, end)
+	}
+
+	writer := io.NewOffsetWriter(out, start)
+	buf := make([]byte, chunkReadSize)
+	var written int64
+	for {
+		n, readErr := resp.Body.Read(buf)
+		if n > 0 {
+			if _, err := writer.Write(buf[:n]); err != nil {
+				return err
+			}
+			written += int64(n)
+			report(n)
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+
+	if want := end - start + 1; written != want {
+		return fmt.Errorf("got %d bytes for range %d-%d, expected %d", written, start, end, want)
+	}
+	return nil
+}
+
+// verifyChunkedFile checks the assembled size and, when the server's ETag is a plain MD5, the checksum
+func verifyChunkedFile(out *os.File, probe *rangeProbe) error {
+	info, err := out.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat assembled file: %w", err)
+	}
+	if info.Size() != probe.size {
+		return fmt.Errorf("assembled file is %d bytes, expected %d", info.Size(), probe.size)
+	}
+
+	// Weak and multipart ETags aren't content hashes
+	match := md5ETag.FindStringSubmatch(probe.etag)
+	if match == nil {
+		return nil
+	}
+
+	if _, err := out.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read assembled file: %w", err)
+	}
+	hash := md5.New()
+	if _, err := io.Copy(hash, out); err != nil {
+		return fmt.Errorf("failed to checksum assembled file: %w", err)
+	}
+	if sum := hex.EncodeToString(hash.Sum(nil)); !strings.EqualFold(sum, match[1]) {
+		return fmt.Errorf("checksum mismatch: got %s, expected %s", sum, match[1])
+	}
+	fmt.Println("[Chunked] Checksum verified")
+	return nil
+}
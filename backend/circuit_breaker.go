@@ -0,0 +1,191 @@
+package backend
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBreakerThreshold = 5
+	defaultBreakerCooldown  = 5 * time.Minute
+)
+
+// ServiceBreakerStatus reports the circuit-breaker state of one download service
+type ServiceBreakerStatus struct {
+	Service             string `json:"service"`
+	Open                bool   `json:"open"`
+	ConsecutiveFailures int    `json:"consecutive_failures"`
+	ErrorCode           string `json:"error_code,omitempty"`
+	LastError           string `json:"last_error,omitempty"`
+	TrippedAt           int64  `json:"tripped_at,omitempty"`
+	ResumesAt           int64  `json:"resumes_at,omitempty"`
+}
+
+type serviceBreaker struct {
+	failures  int
+	errorCode string
+	lastError string
+	open      bool
+	trippedAt time.Time
+}
+
+var (
+	breakers         = make(map[string]*serviceBreaker)
+	breakerThreshold = defaultBreakerThreshold
+	breakerCooldown  = defaultBreakerCooldown
+	breakerPauseAll  bool
+	breakerLock      sync.Mutex
+)
+
+var httpStatusPattern = regexp.MustCompile(`status(?: code)? (\d{3})`)
+
+// SetBreakerConfig sets how many consecutive identical failures trip a service, how long it
+// stays paused, and whether a trip pauses every service. A threshold of 0 disables the breaker.
+func SetBreakerConfig(threshold int, cooldown time.Duration, pauseAll bool) {
+	if threshold < 0 {
+		threshold = 0
+	}
+	if cooldown <= 0 {
+		cooldown = defaultBreakerCooldown
+	}
+
+	breakerLock.Lock()
+	breakerThreshold = threshold
+	breakerCooldown = cooldown
+	breakerPauseAll = pauseAll
+	breakerLock.Unlock()
+}
+
+// classifyDownloadError reduces an error to a coarse code so "the same failure" can be counted
+func classifyDownloadError(err error) string {
+	msg := strings.ToLower(err.Error())
+	if match := httpStatusPattern.FindStringSubmatch(msg); match != nil {
+		return "http_" + match[1]
+	}
+
+	switch {
+	case strings.Contains(msg, "timeout") || strings.Contains(msg, "deadline exceeded"):
+		return "timeout"
+	case strings.Contains(msg, "connection refused") || strings.Contains(msg, "no such host") || strings.Contains(msg, "connection reset"):
+		return "network"
+	case strings.Contains(msg, "all") && strings.Contains(msg, "apis failed"):
+		return "all_apis_failed"
+	case strings.Contains(msg, "not found") || strings.Contains(msg, "no results"):
+		return "not_found"
+	}
+	return "other"
+}
+
+// RecordServiceResult feeds a download outcome into the service's breaker. Returns true when
+// this failure tripped the breaker.
+func RecordServiceResult(service string, err error) bool {
+	breakerLock.Lock()
+	defer breakerLock.Unlock()
+
+	b, ok := breakers[service]
+	if !ok {
+		b = &serviceBreaker{}
+		breakers[service] = b
+	}
+
+	if err == nil {
+		b.failures = 0
+		b.errorCode = ""
+		b.lastError = ""
+		return false
+	}
+
+	code := classifyDownloadError(err)
+	// A track that simply isn't on the service says nothing about the service's health
+	if code == "not_found" {
+		return false
+	}
+
+	if code == b.errorCode {
+		b.failures++
+	} else {
+		b.errorCode = code
+		b.failures = 1
+	}
+	b.lastError = err.Error()
+
+	if breakerThreshold > 0 && !b.open && b.failures >= breakerThreshold {
+		b.open = true
+		b.trippedAt = time.Now()
+		fmt.Printf("[Circuit Breaker] %s paused after %d consecutive failures (%s)\n", service, b.failures, code)
+		return true
+	}
+	return false
+}
+
+// CheckServiceAvailable returns an error while a service's breaker is open. Breakers close
+// again on their own once the cooldown has passed.
+func CheckServiceAvailable(service string) error {
+	breakerLock.Lock()
+	defer breakerLock.Unlock()
+
+	for name, b := range breakers {
+		if name != service && !breakerPauseAll {
+			continue
+		}
+		if !b.open {
+			continue
+		}
+		if time.Since(b.trippedAt) >= breakerCooldown {
+			fmt.Printf("[Circuit Breaker] %s cooldown elapsed, resuming\n", name)
+			b.open = false
+			b.failures = 0
+			continue
+		}
+		return fmt.Errorf("%s paused after %d consecutive failures (%s); resumes in %s",
+			name, b.failures, b.errorCode, (breakerCooldown - time.Since(b.trippedAt)).Round(time.Second))
+	}
+	return nil
+}
+
+// ResumeService manually closes a service's breaker. An empty service resumes all of them.
+func ResumeService(service string) {
+	breakerLock.Lock()
+	defer breakerLock.Unlock()
+
+	for name, b := range breakers {
+		if service != "" && name != service {
+			continue
+		}
+		b.open = false
+		b.failures = 0
+		b.errorCode = ""
+		fmt.Printf("[Circuit Breaker] %s resumed\n", name)
+	}
+}
+
+// GetBreakerStatus returns the breaker state of every service that has recorded a result
+func GetBreakerStatus() []ServiceBreakerStatus {
+	breakerLock.Lock()
+	defer breakerLock.Unlock()
+
+	statuses := make([]ServiceBreakerStatus, 0, len(breakers))
+	for name, b := range breakers {
+		status := ServiceBreakerStatus{
+			Service:             name,
+			Open:                b.open,
+			ConsecutiveFailures: b.failures,
+			ErrorCode:           b.errorCode,
+			LastError:           b.lastError,
+		}
+		if b.open {
+			status.TrippedAt = b.trippedAt.Unix()
+			status.ResumesAt = b.trippedAt.Add(breakerCooldown).Unix()
+		}
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Service < statuses[j].Service
+	})
+	return statuses
+}
@@ -1,10 +1,112 @@
 package backend
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 )
 
+// Settings holds user configuration shared by the UI and the backend
+type Settings struct {
+	DownloadPath         string   `json:"download_path"`
+	DatabasePath         string   `json:"database_path,omitempty"`
+	AcoustIDKey          string   `json:"acoustid_key,omitempty"`
+	FilenameFormat       string   `json:"filename_format,omitempty"`
+	DefaultCoverPath     string   `json:"default_cover_path,omitempty"`
+	LyricsFormat         string   `json:"lyrics_format,omitempty"`
+	EmbedLyrics          bool     `json:"embed_lyrics"`
+	PreferLocalLyrics    bool     `json:"prefer_local_lyrics"`
+	LRCMetadataTags      bool     `json:"lrc_metadata_tags"` // Start LRC output with title/artist/album/length tags
+	EmbedMaxQualityCover bool     `json:"embed_max_quality_cover"`
+	EmbedProvenanceTags  bool     `json:"embed_provenance_tags"`
+	EmbedSourceURL       bool     `json:"embed_source_url"`
+	ValidateCSVTracks    bool     `json:"validate_csv_tracks"`            // Check CSV Spotify IDs still resolve before queueing
+	StripDuplicateArt    bool     `json:"strip_duplicate_art"`            // Art dedup moves identical album art to cover.jpg
+	CatalogRelativePaths bool     `json:"catalog_rel_paths"`              // Library catalog paths relative to the library root
+	MinDurationSeconds   int      `json:"min_duration_seconds,omitempty"` // CSV batch tracks shorter than this are dropped; 0 = keep all
+	TempDir              string   `json:"temp_dir,omitempty"`
+	FinalMoveDir         string   `json:"final_move_dir,omitempty"` // Import folder finished tracks are moved into
+	DownloadChunks       int      `json:"download_chunks"`          // Parallel ranged connections per large file; 0 or 1 disables
+	ChunkedMinSizeMB     int      `json:"chunked_min_size_mb,omitempty"`
+	ConcurrentDownloads  int      `json:"concurrent_downloads,omitempty"`   // Items the download workers process at once (1-5)
+	PrefetchDepth        int      `json:"prefetch_depth,omitempty"`         // Upcoming queue items resolved ahead while one downloads; 0 = off
+	URLRefreshRetries    int      `json:"url_refresh_retries"`              // Re-resolve an expired signed download URL this many times
+	ResumeRetries        int      `json:"resume_retries"`                   // Resume a broken download from its .part file this many times
+	PersistQueue         bool     `json:"persist_queue"`                    // Save pending queue items and restore them on the next start
+	ShutdownGraceSeconds int      `json:"shutdown_grace_seconds"`           // Time quitting waits for running downloads to finish
+	FinalizeRetries      int      `json:"finalize_retries"`                 // Retries for moving files into place on network/cloud folders
+	MaxFFmpegProcesses   int      `json:"max_ffmpeg_processes,omitempty"`   // ffmpeg/ffprobe processes allowed at once; 0 = number of cores
+	DuplicateHashWorkers int      `json:"duplicate_hash_workers,omitempty"` // Files hashed at once when finding duplicates; 0 = automatic
+	ArchiveAfterDownload bool     `json:"archive_after_download"`           // Zip each finished album or CSV batch into its output folder
+	FFmpegPath           string   `json:"ffmpeg_path,omitempty"`
+	CoverTransliteration bool     `json:"cover_transliteration"`
+	CoverPriority        []string `json:"cover_priority,omitempty"`
+	CoverSearchDelayMs   int      `json:"cover_search_delay_ms,omitempty"` // Minimum gap between requests to one cover source
+	CoverSearchOrder     []string `json:"cover_search_order,omitempty"`    // "album" and/or "track" match passes, in order
+	PreferDatabaseCover  bool     `json:"prefer_database_cover"`           // Use the local database cover even when Spotify supplies one
+	VerifyBeforeSkip     bool     `json:"verify_before_skip"`              // Decode an ISRC-matched file before skipping its download
+	TrackNumberTolerance int      `json:"track_number_tolerance"`
+	AlbumMatchThreshold  float64  `json:"album_match_threshold"`
+	MultiArtistTags      bool     `json:"multi_artist_tags"`
+	SquareCoverCrop      bool     `json:"square_cover_crop"`
+	NativeCoverSize      bool     `json:"native_cover_size"` // Embed source art untouched, overriding max quality and square crop
+	EnforceSpotifyISRC   bool     `json:"enforce_spotify_isrc"`
+	TidalBestQualityAPI  bool     `json:"tidal_best_quality_api"` // With the "auto" API, use the one serving the highest quality
+	MaxPlaylistTracks    int      `json:"max_playlist_tracks,omitempty"`
+	ReadOnlyHandling     string   `json:"read_only_handling,omitempty"` // "skip" or "chmod"
+	FilenameCharset      string   `json:"filename_charset,omitempty"`   // "unicode" (default) or "ascii"
+	TitleCollisionMode   string   `json:"title_collisions,omitempty"`   // "track_number" (default) or "none"
+	EnrichRetries        int      `json:"enrich_retries"`
+	ArtistImageFilename  string   `json:"artist_image_filename,omitempty"`
+	RecordToDatabase     bool     `json:"record_to_database"` // Add each finished download to the local database
+
+	// Artist folder name -> canonical folder name used when merging duplicate artist folders
+	ArtistFolderAliases map[string]string `json:"artist_folder_aliases,omitempty"`
+
+	// Lyrics lookup limits (0 = default): per-source timeout, whole-lookup deadline, attempts per source
+	LyricsSourceTimeoutSeconds int `json:"lyrics_source_timeout_seconds,omitempty"`
+	LyricsTotalTimeoutSeconds  int `json:"lyrics_total_timeout_seconds,omitempty"`
+	LyricsFetchRetries         int `json:"lyrics_fetch_retries,omitempty"`
+
+	// Circuit breaker: pause a service after this many identical consecutive failures (0 = off)
+	BreakerThreshold       int  `json:"breaker_threshold"`
+	BreakerCooldownSeconds int  `json:"breaker_cooldown_seconds"`
+	BreakerPauseAll        bool `json:"breaker_pause_all"`
+
+	SkipAlternateVersions   bool     `json:"skip_alternate_versions"`
+	ExcludedVersionKeywords []string `json:"excluded_version_keywords,omitempty"`
+}
+
+var (
+	currentSettings  = defaultSettings()
+	settingsLock     sync.RWMutex
+	settingsFilePath string
+)
+
+func defaultSettings() Settings {
+	return Settings{
+		DownloadPath:         GetDefaultMusicPath(),
+		FilenameFormat:       "title-artist",
+		LyricsFormat:         LyricsFormatLRC,
+		LRCMetadataTags:      true,
+		CoverTransliteration: true,
+		EnforceSpotifyISRC:   true,
+		EnrichRetries:        defaultEnrichRetries,
+		FinalizeRetries:      defaultFinalizeRetries,
+		ShutdownGraceSeconds: defaultShutdownGraceSeconds,
+		URLRefreshRetries:    defaultURLRefreshRetries,
+		ResumeRetries:        defaultResumeRetries,
+		PersistQueue:         true,
+		AlbumMatchThreshold:  defaultAlbumMatchThreshold,
+
+		BreakerThreshold:       defaultBreakerThreshold,
+		BreakerCooldownSeconds: int(defaultBreakerCooldown / time.Second),
+	}
+}
+
 func GetDefaultMusicPath() string {
 	// Get user's home directory
 	homeDir, err := os.UserHomeDir()
@@ -16,3 +118,152 @@ func GetDefaultMusicPath() string {
 	// Return path to user's Music folder
 	return filepath.Join(homeDir, "Music")
 }
+
+// GetSettingsPath returns the default settings file location (~/.spotiflac/settings.json)
+func GetSettingsPath() (string, error) {
+	dir, err := GetFFmpegDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "settings.json"), nil
+}
+
+// LoadSettings reads settings from a JSON file and applies them. A missing file
+// keeps the defaults and is not an error.
+func LoadSettings(path string) error {
+	settingsLock.Lock()
+	settingsFilePath = path
+	settingsLock.Unlock()
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		fmt.Printf("[Settings] No settings file at %s, using defaults\n", path)
+		applySettings(GetSettings())
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read settings: %v", err)
+	}
+
+	settings := defaultSettings()
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return fmt.Errorf("failed to parse settings: %v", err)
+	}
+
+	settingsLock.Lock()
+	currentSettings = settings
+	settingsLock.Unlock()
+
+	fmt.Printf("[Settings] Loaded settings from %s\n", path)
+	applySettings(settings)
+	return nil
+}
+
+// SaveSettings writes the current settings to a JSON file
+func SaveSettings(path string) error {
+	settings := GetSettings()
+
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode settings: %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create settings directory: %v", err)
+	}
+
+	// Write to a temp file first so a crash never leaves a half-written settings file
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write settings: %v", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to save settings: %v", err)
+	}
+
+	return nil
+}
+
+// GetSettings returns a copy of the current settings
+func GetSettings() Settings {
+	settingsLock.RLock()
+	defer settingsLock.RUnlock()
+	return currentSettings
+}
+
+// UpdateSettings replaces the current settings, applies them and persists them to the
+// file they were loaded from
+func UpdateSettings(settings Settings) error {
+	settingsLock.Lock()
+	currentSettings = settings
+	path := settingsFilePath
+	settingsLock.Unlock()
+
+	applySettings(settings)
+
+	if path == "" {
+		var err error
+		path, err = GetSettingsPath()
+		if err != nil {
+			return err
+		}
+	}
+	return SaveSettings(path)
+}
+
+// applySettings pushes settings into the backend components that hold their own state
+func applySettings(settings Settings) {
+	if err := SetDefaultCover(settings.DefaultCoverPath); err != nil {
+		fmt.Printf("[Settings] Warning: %v\n", err)
+	}
+	SetTrackNumberTolerance(settings.TrackNumberTolerance)
+	SetCoverTransliteration(settings.CoverTransliteration)
+	SetAlbumMatchThreshold(settings.AlbumMatchThreshold)
+	SetMultiArtistTags(settings.MultiArtistTags)
+	SetSquareCoverCrop(settings.SquareCoverCrop)
+	SetNativeCoverSize(settings.NativeCoverSize)
+	SetEnforceSpotifyISRC(settings.EnforceSpotifyISRC)
+	SetMaxPlaylistTracks(settings.MaxPlaylistTracks)
+	SetEnrichRetries(settings.EnrichRetries)
+	SetLyricsFetchLimits(settings.LyricsSourceTimeoutSeconds, settings.LyricsTotalTimeoutSeconds, settings.LyricsFetchRetries)
+	SetArtistImageFilename(settings.ArtistImageFilename)
+	if err := SetReadOnlyHandling(settings.ReadOnlyHandling); err != nil {
+		fmt.Printf("[Settings] Warning: %v\n", err)
+	}
+	SetPreferDatabaseCover(settings.PreferDatabaseCover)
+	SetStripDuplicateArt(settings.StripDuplicateArt)
+	SetCatalogRelativePaths(settings.CatalogRelativePaths)
+	SetFinalizeRetries(settings.FinalizeRetries)
+	SetShutdownGrace(settings.ShutdownGraceSeconds)
+	SetURLRefreshRetries(settings.URLRefreshRetries)
+	SetResumeRetries(settings.ResumeRetries)
+	SetPersistQueue(settings.PersistQueue)
+	SetAcoustIDKey(settings.AcoustIDKey)
+	SetPrefetchDepth(settings.PrefetchDepth)
+	SetMaxConcurrentDownloads(settings.ConcurrentDownloads)
+	SetMaxFFmpegProcesses(settings.MaxFFmpegProcesses)
+	SetDuplicateHashWorkers(settings.DuplicateHashWorkers)
+	SetLRCMetadataTags(settings.LRCMetadataTags)
+	SetVerifyBeforeSkip(settings.VerifyBeforeSkip)
+	if err := SetTitleCollisionMode(settings.TitleCollisionMode); err != nil {
+		fmt.Printf("[Settings] Warning: %v\n", err)
+	}
+	SetFinalMoveDir(settings.FinalMoveDir)
+	SetTidalPreferBestQuality(settings.TidalBestQualityAPI)
+	SetCoverSearchInterval(settings.CoverSearchDelayMs)
+	if err := SetFilenameCharset(settings.FilenameCharset); err != nil {
+		fmt.Printf("[Settings] Warning: %v\n", err)
+	}
+	SetChunkedDownload(settings.DownloadChunks, settings.ChunkedMinSizeMB)
+	if err := SetCoverPriority(settings.CoverPriority); err != nil {
+		fmt.Printf("[Settings] Warning: %v\n", err)
+	}
+	if err := SetCoverSearchOrder(settings.CoverSearchOrder); err != nil {
+		fmt.Printf("[Settings] Warning: %v\n", err)
+	}
+	SetBreakerConfig(settings.BreakerThreshold, time.Duration(settings.BreakerCooldownSeconds)*time.Second, settings.BreakerPauseAll)
+	if err := SetFFmpegPath(settings.FFmpegPath); err != nil {
+		fmt.Printf("[Settings] Warning: %v\n", err)
+	}
+}
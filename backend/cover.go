@@ -136,9 +136,14 @@ func (c *CoverClient) DownloadCoverToPath(coverURL, outputPath string, embedMaxQ
 		return fmt.Errorf("cover URL is required")
 	}
 
-	// Use max quality URL if setting is enabled
+	// Album downloads fetch the cover once; reuse those bytes instead of fetching again
+	if shared := sharedCoverPathForURL(coverURL); shared != "" {
+		return copyFile(shared, outputPath)
+	}
+
+	// Use max quality URL if setting is enabled; native mode keeps the URL's own size
 	downloadURL := coverURL
-	if embedMaxQualityCover {
+	if embedMaxQualityCover && !isNativeCoverSizeEnabled() {
 		downloadURL = c.getMaxResolutionURL(coverURL)
 	}
 
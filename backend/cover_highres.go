@@ -0,0 +1,122 @@
+package backend
+
+import (
+	"fmt"
+	stdimage "image"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// coverProbeBytes is how much of an image is read to find its dimensions; JPEG and PNG headers
+// sit well inside this even with embedded EXIF or ICC data
+const coverProbeBytes = 256 * 1024
+
+// CoverSourceSpotify labels Spotify search results among the cover sources compared by resolution
+const CoverSourceSpotify = "spotify"
+
+// coverCandidate is one source's cover and its measured size
+type coverCandidate struct {
+	source string
+	url    string
+	width  int
+	height int
+}
+
+// shortSide ranks candidates; a wide banner shouldn't beat a larger square cover
+func (c coverCandidate) shortSide() int {
+	if c.height < c.width {
+		return c.height
+	}
+	return c.width
+}
+
+// probeCoverSize downloads the start of an image and reads its dimensions without decoding pixels
+func probeCoverSize(coverURL string) (int, int, error) {
+	req, err := http.NewRequest("GET", coverURL, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=0-%d", coverProbeBytes-1))
+
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return 0, 0, fmt.Errorf("cover returned status %d", resp.StatusCode)
+	}
+
+	config, _, err := stdimage.DecodeConfig(io.LimitReader(resp.Body, coverProbeBytes))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read cover dimensions: %v", err)
+	}
+	return config.Width, config.Height, nil
+}
+
+// FetchHighestResCover queries iTunes, Deezer, Spotify and MusicBrainz at the same time and returns
+// the cover with the largest dimensions, instead of the first source that has one
+func FetchHighestResCover(title, artist, album string) (string, int, error) {
+	if title == "" || artist == "" {
+		return "", 0, fmt.Errorf("track name and artist name are required")
+	}
+
+	// Listed in preference order; it breaks ties between equally sized covers
+	sources := []string{CoverSourceITunes, CoverSourceDeezer, CoverSourceSpotify, CoverSourceMusicBrainz}
+	candidates := make([]*coverCandidate, len(sources))
+
+	var wg sync.WaitGroup
+	for i, source := range sources {
+		wg.Add(1)
+		go func(i int, source string) {
+			defer wg.Done()
+
+			var coverURL string
+			var err error
+			if source == CoverSourceSpotify {
+				query := fmt.Sprintf("track:%s artist:%s", title, artist)
+				if album != "" {
+					query += fmt.Sprintf(" album:%s", album)
+				}
+				coverURL, err = SearchSpotifyForCover(query, title, artist)
+				if err == nil {
+					coverURL = NewCoverClient().getMaxResolutionURL(coverURL)
+				}
+			} else {
+				coverURL, _, err = SearchCoverWithVariants(source, title, artist)
+			}
+			if err != nil || coverURL == "" {
+				return
+			}
+
+			width, height, err := probeCoverSize(coverURL)
+			if err != nil {
+				fmt.Printf("[Cover] Could not measure %s cover: %v\n", source, err)
+				return
+			}
+			candidates[i] = &coverCandidate{source: source, url: coverURL, width: width, height: height}
+		}(i, source)
+	}
+	wg.Wait()
+
+	var best *coverCandidate
+	for _, candidate := range candidates {
+		if candidate == nil {
+			continue
+		}
+		fmt.Printf("[Cover] %s: %dx%d\n", candidate.source, candidate.width, candidate.height)
+		if best == nil || candidate.shortSide() > best.shortSide() {
+			best = candidate
+		}
+	}
+	if best == nil {
+		return "", 0, fmt.Errorf("cover not found from any source")
+	}
+
+	fmt.Printf("[Cover] Highest resolution cover from %s (%dx%d): %s\n", best.source, best.width, best.height, best.url)
+	return best.url, best.width, nil
+}
@@ -0,0 +1,56 @@
+package backend
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+)
+
+// CoverSize is the pixel size of a cover as it was embedded
+type CoverSize struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+var (
+	nativeCoverSize     bool
+	nativeCoverSizeLock sync.RWMutex
+
+	embeddedCoverSizes     = make(map[string]CoverSize)
+	embeddedCoverSizesLock sync.Mutex
+)
+
+// SetNativeCoverSize toggles embedding the cover exactly as the source serves it: no max-resolution
+// URL swap and no square crop, so the tagged art is bit-identical to the source file
+func SetNativeCoverSize(enabled bool) {
+	nativeCoverSizeLock.Lock()
+	nativeCoverSize = enabled
+	nativeCoverSizeLock.Unlock()
+}
+
+func isNativeCoverSizeEnabled() bool {
+	nativeCoverSizeLock.RLock()
+	defer nativeCoverSizeLock.RUnlock()
+	return nativeCoverSize
+}
+
+// recordEmbeddedCoverSize remembers the dimensions of the art embedded into a file
+func recordEmbeddedCoverSize(audioPath string, width, height int) {
+	embeddedCoverSizesLock.Lock()
+	embeddedCoverSizes[audioPath] = CoverSize{Width: width, Height: height}
+	embeddedCoverSizesLock.Unlock()
+
+	fmt.Printf("[Cover] Embedding %dx%d cover into: %s\n", width, height, filepath.Base(audioPath))
+}
+
+// TakeEmbeddedCoverSize returns and clears the cover dimensions recorded for a file, if any
+func TakeEmbeddedCoverSize(audioPath string) (CoverSize, bool) {
+	embeddedCoverSizesLock.Lock()
+	defer embeddedCoverSizesLock.Unlock()
+
+	size, ok := embeddedCoverSizes[audioPath]
+	if ok {
+		delete(embeddedCoverSizes, audioPath)
+	}
+	return size, ok
+}
@@ -0,0 +1,240 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Album art origins, in the order they can appear in a cover priority chain
+const (
+	CoverOriginEmbedded = "embedded"
+	CoverOriginSidecar  = "sidecar"
+	CoverOriginDatabase = "database"
+	CoverOriginOnline   = "online"
+
+	// CoverOriginRequest is the Spotify cover URL supplied with a download request
+	CoverOriginRequest = "spotify"
+)
+
+// DefaultCoverPriority checks the file itself before going to the database or the network
+var DefaultCoverPriority = []string{CoverOriginEmbedded, CoverOriginSidecar, CoverOriginDatabase, CoverOriginOnline}
+
+var (
+	coverPriority     = DefaultCoverPriority
+	coverPriorityLock sync.RWMutex
+
+	preferDatabaseCover     bool
+	preferDatabaseCoverLock sync.RWMutex
+)
+
+// SetPreferDatabaseCover toggles using the local database cover even when a download request
+// already carries a Spotify cover URL
+func SetPreferDatabaseCover(enabled bool) {
+	preferDatabaseCoverLock.Lock()
+	preferDatabaseCover = enabled
+	preferDatabaseCoverLock.Unlock()
+}
+
+func isPreferDatabaseCoverEnabled() bool {
+	preferDatabaseCoverLock.RLock()
+	defer preferDatabaseCoverLock.RUnlock()
+	return preferDatabaseCover
+}
+
+// SetCoverPriority sets the order album art sources are tried in. An empty list restores the default.
+func SetCoverPriority(priority []string) error {
+	if len(priority) == 0 {
+		priority = DefaultCoverPriority
+	}
+
+	seen := make(map[string]bool)
+	cleaned := make([]string, 0, len(priority))
+	for _, origin := range priority {
+		origin = strings.ToLower(strings.TrimSpace(origin))
+		switch origin {
+		case CoverOriginEmbedded, CoverOriginSidecar, CoverOriginDatabase, CoverOriginOnline:
+		default:
+			return fmt.Errorf("unknown cover source: %s", origin)
+		}
+		if !seen[origin] {
+			seen[origin] = true
+			cleaned = append(cleaned, origin)
+		}
+	}
+
+	coverPriorityLock.Lock()
+	coverPriority = cleaned
+	coverPriorityLock.Unlock()
+	return nil
+}
+
+// GetCoverPriority returns a copy of the configured cover priority chain
+func GetCoverPriority() []string {
+	coverPriorityLock.RLock()
+	defer coverPriorityLock.RUnlock()
+
+	priority := make([]string, len(coverPriority))
+	copy(priority, coverPriority)
+	return priority
+}
+
+// CoverLookup identifies the track whose album art should be resolved
+type CoverLookup struct {
+	AudioPath    string
+	Title        string
+	Artist       string
+	Album        string
+	DatabasePath string
+	SkipLocal    bool // Skip embedded and sidecar art, e.g. when replacing a low-resolution cover
+	MaxQuality   bool // Compare online sources by resolution instead of taking the first hit
+}
+
+// CoverResolution is where album art was found. Exactly one of LocalPath or URL is set;
+// LocalPath may be a temp file (embedded art) that the caller must remove when IsTemp is true.
+type CoverResolution struct {
+	Origin    string
+	LocalPath string
+	URL       string
+	IsTemp    bool
+	Variant   string
+}
+
+// ResolveCover walks the cover priority chain and returns the first source that has art
+func ResolveCover(lookup CoverLookup) (*CoverResolution, error) {
+	for _, origin := range GetCoverPriority() {
+		if lookup.SkipLocal && (origin == CoverOriginEmbedded || origin == CoverOriginSidecar) {
+			continue
+		}
+		var resolution *CoverResolution
+		switch origin {
+		case CoverOriginEmbedded:
+			resolution = resolveEmbeddedCover(lookup)
+		case CoverOriginSidecar:
+			resolution = resolveSidecarCover(lookup)
+		case CoverOriginDatabase:
+			resolution = resolveDatabaseCover(lookup)
+		case CoverOriginOnline:
+			resolution = resolveOnlineCover(lookup)
+		}
+		if resolution != nil {
+			resolution.Origin = origin
+			return resolution, nil
+		}
+	}
+
+	return nil, fmt.Errorf("cover not found from any source")
+}
+
+// ResolveDownloadCover picks the cover URL for a download: the database cover when preferred
+// and available, then the request's Spotify URL, then the priority chain. With MaxQuality the
+// largest online cover replaces a smaller Spotify one. Only URL sources apply since the audio
+// file doesn't exist yet. Returns the URL and its origin.
+func ResolveDownloadCover(requestURL string, lookup CoverLookup) (string, string) {
+	if isPreferDatabaseCoverEnabled() {
+		if resolution := resolveDatabaseCover(lookup); resolution != nil {
+			return resolution.URL, CoverOriginDatabase
+		}
+	}
+	if lookup.MaxQuality && lookup.Title != "" && lookup.Artist != "" {
+		if coverURL, width, err := FetchHighestResCover(lookup.Title, lookup.Artist, lookup.Album); err == nil {
+			// The request's own cover still wins unless another source is actually larger
+			if requestURL == "" {
+				return coverURL, CoverOriginOnline
+			}
+			requestWidth, _, probeErr := probeCoverSize(NewCoverClient().getMaxResolutionURL(requestURL))
+			if probeErr != nil || width > requestWidth {
+				return coverURL, CoverOriginOnline
+			}
+		}
+	}
+	if requestURL != "" {
+		return requestURL, CoverOriginRequest
+	}
+	if lookup.Title == "" || lookup.Artist == "" {
+		return "", ""
+	}
+
+	resolution, err := ResolveCover(lookup)
+	if err != nil || resolution.URL == "" {
+		if err == nil && resolution.IsTemp {
+			os.Remove(resolution.LocalPath)
+		}
+		return "", ""
+	}
+	return resolution.URL, resolution.Origin
+}
+
+func resolveEmbeddedCover(lookup CoverLookup) *CoverResolution {
+	if lookup.AudioPath == "" || !fileExists(lookup.AudioPath) {
+		return nil
+	}
+	tmpPath, err := ExtractCoverArt(lookup.AudioPath)
+	if err != nil || tmpPath == "" {
+		return nil
+	}
+	return &CoverResolution{LocalPath: tmpPath, IsTemp: true}
+}
+
+func resolveSidecarCover(lookup CoverLookup) *CoverResolution {
+	if lookup.AudioPath == "" {
+		return nil
+	}
+	basePath := strings.TrimSuffix(lookup.AudioPath, filepath.Ext(lookup.AudioPath))
+	for _, ext := range []string{".jpg", ".png"} {
+		if info, err := os.Stat(basePath + ext); err == nil && info.Size() > 0 {
+			return &CoverResolution{LocalPath: basePath + ext}
+		}
+	}
+	return nil
+}
+
+func resolveDatabaseCover(lookup CoverLookup) *CoverResolution {
+	if lookup.DatabasePath == "" {
+		return nil
+	}
+	byAlbum := func() *CoverResolution {
+		if coverURL, err := GetAlbumCoverFromDatabase(lookup.DatabasePath, lookup.Album); err == nil && coverURL != "" {
+			return &CoverResolution{URL: coverURL}
+		}
+		return nil
+	}
+	byTrack := func() *CoverResolution {
+		if coverURL, err := GetCoverByTrackFromDatabase(lookup.DatabasePath, lookup.Title, lookup.Artist); err == nil && coverURL != "" {
+			return &CoverResolution{URL: coverURL}
+		}
+		return nil
+	}
+	return searchCoverInOrder(lookup, byAlbum, byTrack)
+}
+
+func resolveOnlineCover(lookup CoverLookup) *CoverResolution {
+	byAlbum := func() *CoverResolution {
+		if coverURL, err := searchOnlineAlbumCover(lookup.Album, lookup.Artist); err == nil && coverURL != "" {
+			return &CoverResolution{URL: coverURL, Variant: CoverQueryAlbum}
+		}
+		return nil
+	}
+	return searchCoverInOrder(lookup, byAlbum, func() *CoverResolution { return resolveOnlineTrackCover(lookup) })
+}
+
+// resolveOnlineTrackCover searches the online sources by track and artist
+func resolveOnlineTrackCover(lookup CoverLookup) *CoverResolution {
+	for _, source := range []string{CoverSourceITunes, CoverSourceDeezer} {
+		if coverURL, variant, err := SearchCoverWithVariants(source, lookup.Title, lookup.Artist); err == nil && coverURL != "" {
+			return &CoverResolution{URL: coverURL, Variant: variant}
+		}
+	}
+
+	searchQuery := fmt.Sprintf("track:%s artist:%s", lookup.Title, lookup.Artist)
+	if coverURL, err := SearchSpotifyForCover(searchQuery, lookup.Title, lookup.Artist); err == nil && coverURL != "" {
+		return &CoverResolution{URL: coverURL, Variant: CoverQueryOriginal}
+	}
+
+	if coverURL, variant, err := SearchCoverWithVariants(CoverSourceMusicBrainz, lookup.Title, lookup.Artist); err == nil && coverURL != "" {
+		return &CoverResolution{URL: coverURL, Variant: variant}
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cover match passes. Album search is cheap but can land on the wrong release of a title
+// (deluxe vs. standard, compilations); track + artist search is slower but more precise.
+const (
+	CoverMatchAlbum = "album"
+	CoverMatchTrack = "track"
+
+	// CoverQueryAlbum is the query variant reported for covers found by album name
+	CoverQueryAlbum = "album"
+)
+
+// DefaultCoverSearchOrder tries the fast album match first
+var DefaultCoverSearchOrder = []string{CoverMatchAlbum, CoverMatchTrack}
+
+var (
+	coverSearchOrder     = DefaultCoverSearchOrder
+	coverSearchOrderLock sync.RWMutex
+)
+
+// SetCoverSearchOrder sets which match pass the database and online cover lookups try first.
+// A pass left out of the list is skipped; an empty list restores the default.
+func SetCoverSearchOrder(order []string) error {
+	if len(order) == 0 {
+		order = DefaultCoverSearchOrder
+	}
+
+	seen := make(map[string]bool)
+	cleaned := make([]string, 0, len(order))
+	for _, pass := range order {
+		pass = strings.ToLower(strings.TrimSpace(pass))
+		if pass != CoverMatchAlbum && pass != CoverMatchTrack {
+			return fmt.Errorf("unknown cover search pass: %s", pass)
+		}
+		if !seen[pass] {
+			seen[pass] = true
+			cleaned = append(cleaned, pass)
+		}
+	}
+
+	coverSearchOrderLock.Lock()
+	coverSearchOrder = cleaned
+	coverSearchOrderLock.Unlock()
+	return nil
+}
+
+func getCoverSearchOrder() []string {
+	coverSearchOrderLock.RLock()
+	defer coverSearchOrderLock.RUnlock()
+	return coverSearchOrder
+}
+
+// searchCoverInOrder runs the album and track passes in the configured order and returns the
+// first hit. Passes the lookup has no data for are skipped.
+func searchCoverInOrder(lookup CoverLookup, byAlbum, byTrack func() *CoverResolution) *CoverResolution {
+	for _, pass := range getCoverSearchOrder() {
+		var resolution *CoverResolution
+		switch pass {
+		case CoverMatchAlbum:
+			if lookup.Album != "" {
+				resolution = byAlbum()
+			}
+		case CoverMatchTrack:
+			if lookup.Title != "" && lookup.Artist != "" {
+				resolution = byTrack()
+			}
+		}
+		if resolution != nil {
+			return resolution
+		}
+	}
+	return nil
+}
+
+// searchOnlineAlbumCover looks the album up by name on iTunes, then Deezer. Only a result whose
+// album title matches is accepted, since album search is fuzzy.
+func searchOnlineAlbumCover(albumName, artistName string) (string, error) {
+	var lastErr error
+	for _, source := range []string{CoverSourceITunes, CoverSourceDeezer} {
+		if isCoverSourceThrottled(source) {
+			continue
+		}
+		waitForCoverSource(source)
+
+		var coverURL string
+		var err error
+		if source == CoverSourceITunes {
+			coverURL, err = queryITunesAlbumCover(albumName, artistName)
+		} else {
+			coverURL, err = queryDeezerAlbumCover(albumName, artistName)
+		}
+		recordCoverSourceResult(source, err)
+		if err == nil && coverURL != "" {
+			return coverURL, nil
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no album cover found")
+	}
+	return "", lastErr
+}
+
+func queryITunesAlbumCover(albumName, artistName string) (string, error) {
+	client := &http.Client{Timeout: 15 * time.Second}
+
+	query := strings.TrimSpace(albumName + " " + artistName)
+	apiURL := fmt.Sprintf("https://itunes.apple.com/search?term=%s&media=music&entity=album&limit=5", url.QueryEscape(query))
+
+	req, err := http.NewRequest("GET", apiURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "SpotiFLAC/1.0")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("iTunes API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("iTunes API returned status %d", resp.StatusCode)
+	}
+
+	var searchResp iTunesSearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&searchResp); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	want := normalizeMatchKey(albumName)
+	for _, result := range searchResp.Results {
+		if normalizeMatchKey(result.CollectionName) != want || result.ArtworkUrl100 == "" {
+			continue
+		}
+		artworkURL := strings.Replace(result.ArtworkUrl100, "100x100bb", "3000x3000bb", 1)
+		fmt.Printf("[iTunes] Found album cover for '%s - %s': %s\n", albumName, artistName, artworkURL)
+		return artworkURL, nil
+	}
+	return "", fmt.Errorf("no matching album found")
+}
+
+func queryDeezerAlbumCover(albumName, artistName string) (string, error) {
+	client := &http.Client{Timeout: 15 * time.Second}
+
+	query := strings.TrimSpace(albumName + " " + artistName)
+	apiURL := fmt.Sprintf("https://api.deezer.com/search/album?q=%s&limit=5", url.QueryEscape(query))
+
+	resp, err := client.Get(apiURL)
+	if err != nil {
+		return "", fmt.Errorf("Deezer API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("Deezer API returned status %d", resp.StatusCode)
+	}
+
+	var searchResp struct {
+		Data []struct {
+			Title   string `json:"title"`
+			CoverXL string `json:"cover_xl"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&searchResp); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	want := normalizeMatchKey(albumName)
+	for _, album := range searchResp.Data {
+		if normalizeMatchKey(album.Title) != want || album.CoverXL == "" {
+			continue
+		}
+		fmt.Printf("[Deezer] Found album cover for '%s - %s': %s\n", albumName, artistName, album.CoverXL)
+		return album.CoverXL, nil
+	}
+	return "", fmt.Errorf("no matching album found")
+}
@@ -7,7 +7,11 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 // iTunesSearchResponse represents the response from iTunes Search API
@@ -37,6 +41,12 @@ type MusicBrainzSearchResponse struct {
 
 // SearchITunesForCover searches iTunes API for album cover
 func SearchITunesForCover(trackName, artistName string) (string, error) {
+	coverURL, _, err := SearchCoverWithVariants(CoverSourceITunes, trackName, artistName)
+	return coverURL, err
+}
+
+// queryITunesCover runs a single iTunes cover search
+func queryITunesCover(trackName, artistName string) (string, error) {
 	if trackName == "" || artistName == "" {
 		return "", fmt.Errorf("track name and artist name are required")
 	}
@@ -96,6 +106,12 @@ func SearchITunesForCover(trackName, artistName string) (string, error) {
 
 // SearchMusicBrainzForCover searches MusicBrainz + Cover Art Archive for album cover
 func SearchMusicBrainzForCover(trackName, artistName string) (string, error) {
+	coverURL, _, err := SearchCoverWithVariants(CoverSourceMusicBrainz, trackName, artistName)
+	return coverURL, err
+}
+
+// queryMusicBrainzCover runs a single MusicBrainz + Cover Art Archive lookup
+func queryMusicBrainzCover(trackName, artistName string) (string, error) {
 	if trackName == "" || artistName == "" {
 		return "", fmt.Errorf("track name and artist name are required")
 	}
@@ -117,9 +133,6 @@ func SearchMusicBrainzForCover(trackName, artistName string) (string, error) {
 	// MusicBrainz requires User-Agent
 	req.Header.Set("User-Agent", "SpotiFLAC/1.0 (https://github.com/spotflac)")
 
-	// Rate limiting - MusicBrainz allows 1 request per second
-	time.Sleep(1100 * time.Millisecond)
-
 	resp, err := client.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("MusicBrainz API request failed: %w", err)
@@ -176,6 +189,12 @@ func SearchMusicBrainzForCover(trackName, artistName string) (string, error) {
 
 // SearchDeezerForCover searches Deezer API for album cover
 func SearchDeezerForCover(trackName, artistName string) (string, error) {
+	coverURL, _, err := SearchCoverWithVariants(CoverSourceDeezer, trackName, artistName)
+	return coverURL, err
+}
+
+// queryDeezerCover runs a single Deezer cover search
+func queryDeezerCover(trackName, artistName string) (string, error) {
 	if trackName == "" || artistName == "" {
 		return "", fmt.Errorf("track name and artist name are required")
 	}
@@ -233,3 +252,134 @@ func SearchDeezerForCover(trackName, artistName string) (string, error) {
 	fmt.Printf("[Deezer] Found cover for '%s - %s': %s\n", trackName, artistName, coverURL)
 	return coverURL, nil
 }
+
+// Cover sources accepted by SearchCoverWithVariants
+const (
+	CoverSourceITunes      = "itunes"
+	CoverSourceDeezer      = "deezer"
+	CoverSourceMusicBrainz = "musicbrainz"
+)
+
+// Query variants reported when a cover is found
+const (
+	CoverQueryOriginal       = "original"
+	CoverQueryTransliterated = "transliterated"
+)
+
+var (
+	coverTransliteration     = true
+	coverTransliterationLock sync.RWMutex
+)
+
+// SetCoverTransliteration enables or disables the romanized fallback query for cover searches
+func SetCoverTransliteration(enabled bool) {
+	coverTransliterationLock.Lock()
+	coverTransliteration = enabled
+	coverTransliterationLock.Unlock()
+}
+
+func isCoverTransliterationEnabled() bool {
+	coverTransliterationLock.RLock()
+	defer coverTransliterationLock.RUnlock()
+	return coverTransliteration
+}
+
+// coverQueryVariant is one spelling of a track/artist pair to search with
+type coverQueryVariant struct {
+	Label      string
+	TrackName  string
+	ArtistName string
+}
+
+// normalizeCoverQuery composes Unicode to NFC and collapses whitespace so the same
+// accented name always encodes the same way
+func normalizeCoverQuery(s string) string {
+	return strings.Join(strings.Fields(norm.NFC.String(s)), " ")
+}
+
+// transliterateCoverQuery romanizes Japanese and strips diacritics (é -> e), dropping
+// whatever is still non-ASCII afterwards
+func transliterateCoverQuery(s string) string {
+	s = JapaneseToRomaji(s)
+
+	var result strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		result.WriteRune(r)
+	}
+	return cleanToASCII(result.String())
+}
+
+// buildCoverQueryVariants returns the original query and, if it differs and the fallback is
+// enabled, a transliterated one
+func buildCoverQueryVariants(trackName, artistName string) []coverQueryVariant {
+	original := coverQueryVariant{
+		Label:      CoverQueryOriginal,
+		TrackName:  normalizeCoverQuery(trackName),
+		ArtistName: normalizeCoverQuery(artistName),
+	}
+	variants := []coverQueryVariant{original}
+
+	if !isCoverTransliterationEnabled() {
+		return variants
+	}
+
+	romanized := coverQueryVariant{
+		Label:      CoverQueryTransliterated,
+		TrackName:  transliterateCoverQuery(original.TrackName),
+		ArtistName: transliterateCoverQuery(original.ArtistName),
+	}
+	if romanized.TrackName == "" || romanized.ArtistName == "" {
+		return variants
+	}
+	if romanized.TrackName == original.TrackName && romanized.ArtistName == original.ArtistName {
+		return variants
+	}
+	return append(variants, romanized)
+}
+
+// SearchCoverWithVariants searches one cover source with the original query first, then the
+// transliterated one. Returns the cover URL and the variant that matched.
+func SearchCoverWithVariants(source, trackName, artistName string) (string, string, error) {
+	if trackName == "" || artistName == "" {
+		return "", "", fmt.Errorf("track name and artist name are required")
+	}
+
+	var search func(trackName, artistName string) (string, error)
+	switch source {
+	case CoverSourceITunes:
+		search = queryITunesCover
+	case CoverSourceDeezer:
+		search = queryDeezerCover
+	case CoverSourceMusicBrainz:
+		search = queryMusicBrainzCover
+	default:
+		return "", "", fmt.Errorf("unknown cover source: %s", source)
+	}
+
+	// A source that keeps answering 429 is skipped so callers fall through to the next one
+	if isCoverSourceThrottled(source) {
+		return "", "", fmt.Errorf("%s is rate limited, skipping", source)
+	}
+
+	var lastErr error
+	for _, variant := range buildCoverQueryVariants(trackName, artistName) {
+		waitForCoverSource(source)
+		coverURL, err := search(variant.TrackName, variant.ArtistName)
+		recordCoverSourceResult(source, err)
+		if isCoverSourceThrottled(source) {
+			return "", "", err
+		}
+		if err == nil && coverURL != "" {
+			if variant.Label != CoverQueryOriginal {
+				fmt.Printf("[Cover] %s matched %s query: %s - %s\n", source, variant.Label, variant.TrackName, variant.ArtistName)
+			}
+			return coverURL, variant.Label, nil
+		}
+		lastErr = err
+	}
+
+	return "", "", lastErr
+}
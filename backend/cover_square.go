@@ -0,0 +1,181 @@
+package backend
+
+import (
+	"fmt"
+	stdimage "image"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// coverSquareTolerance is how far width and height may differ (relative to the larger side)
+// before art counts as non-square; it absorbs off-by-a-few-pixels scans
+const coverSquareTolerance = 0.02
+
+var (
+	squareCoverCrop     bool
+	squareCoverCropLock sync.RWMutex
+
+	croppedCoverTracks []string
+	croppedCoverLock   sync.Mutex
+)
+
+// SetSquareCoverCrop toggles center-cropping non-square album art to a square before embedding
+func SetSquareCoverCrop(enabled bool) {
+	squareCoverCropLock.Lock()
+	squareCoverCrop = enabled
+	squareCoverCropLock.Unlock()
+}
+
+func isSquareCoverCropEnabled() bool {
+	squareCoverCropLock.RLock()
+	defer squareCoverCropLock.RUnlock()
+	return squareCoverCrop
+}
+
+// isSquareCover reports whether the dimensions are square within coverSquareTolerance
+func isSquareCover(width, height int) bool {
+	longest := width
+	if height > longest {
+		longest = height
+	}
+	if longest == 0 {
+		return true
+	}
+	return float64(absInt(width-height))/float64(longest) <= coverSquareTolerance
+}
+
+// CheckCoverSquare reads an image's dimensions without decoding the pixels
+func CheckCoverSquare(coverPath string) (int, int, bool, error) {
+	file, err := os.Open(coverPath)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("failed to open cover: %v", err)
+	}
+	defer file.Close()
+
+	config, _, err := stdimage.DecodeConfig(file)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("failed to read cover dimensions: %v", err)
+	}
+	return config.Width, config.Height, isSquareCover(config.Width, config.Height), nil
+}
+
+// cropCoverToSquare writes a center-cropped square copy of a cover to a temp file
+func cropCoverToSquare(coverPath string) (string, error) {
+	file, err := os.Open(coverPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open cover: %v", err)
+	}
+	defer file.Close()
+
+	img, format, err := stdimage.Decode(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode cover: %v", err)
+	}
+
+	bounds := img.Bounds()
+	side := bounds.Dx()
+	if bounds.Dy() < side {
+		side = bounds.Dy()
+	}
+	x0 := bounds.Min.X + (bounds.Dx()-side)/2
+	y0 := bounds.Min.Y + (bounds.Dy()-side)/2
+
+	subImager, ok := img.(interface {
+		SubImage(r stdimage.Rectangle) stdimage.Image
+	})
+	if !ok {
+		return "", fmt.Errorf("cover image type does not support cropping")
+	}
+	cropped := subImager.SubImage(stdimage.Rect(x0, y0, x0+side, y0+side))
+
+	ext := ".jpg"
+	if format == "png" {
+		ext = ".png"
+	}
+	out, err := os.CreateTemp("", "spotiflac-cover-square-*"+ext)
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %v", err)
+	}
+	defer out.Close()
+
+	if format == "png" {
+		err = png.Encode(out, cropped)
+	} else {
+		err = jpeg.Encode(out, cropped, &jpeg.Options{Quality: 95})
+	}
+	if err != nil {
+		os.Remove(out.Name())
+		return "", fmt.Errorf("failed to encode cropped cover: %v", err)
+	}
+	return out.Name(), nil
+}
+
+// prepareCoverForEmbed validates that a cover is square and, when cropping is enabled, returns
+// a square temp copy for non-square art. The returned cleanup removes any temp file.
+func prepareCoverForEmbed(audioPath, coverPath string) (string, func()) {
+	noop := func() {}
+
+	width, height, square, err := CheckCoverSquare(coverPath)
+	if err != nil {
+		return coverPath, noop
+	}
+	recordEmbeddedCoverSize(audioPath, width, height)
+
+	// Native mode embeds the source art untouched, square or not
+	if square || isNativeCoverSizeEnabled() {
+		return coverPath, noop
+	}
+
+	if !isSquareCoverCropEnabled() {
+		fmt.Printf("[Cover] Warning: non-square cover (%dx%d) for: %s\n", width, height, filepath.Base(audioPath))
+		return coverPath, noop
+	}
+
+	croppedPath, err := cropCoverToSquare(coverPath)
+	if err != nil {
+		fmt.Printf("[Cover] Warning: failed to crop %dx%d cover: %v\n", width, height, err)
+		return coverPath, noop
+	}
+
+	fmt.Printf("[Cover] Cropped %dx%d cover to square for: %s\n", width, height, filepath.Base(audioPath))
+	recordCroppedCoverTrack(audioPath)
+	side := width
+	if height < side {
+		side = height
+	}
+	recordEmbeddedCoverSize(audioPath, side, side)
+	return croppedPath, func() { os.Remove(croppedPath) }
+}
+
+// recordCroppedCoverTrack flags a track whose embedded art was cropped to square
+func recordCroppedCoverTrack(path string) {
+	croppedCoverLock.Lock()
+	defer croppedCoverLock.Unlock()
+
+	for _, p := range croppedCoverTracks {
+		if p == path {
+			return
+		}
+	}
+	croppedCoverTracks = append(croppedCoverTracks, path)
+}
+
+// GetCroppedCoverTracks returns the tracks whose album art was cropped to square
+func GetCroppedCoverTracks() []string {
+	croppedCoverLock.Lock()
+	defer croppedCoverLock.Unlock()
+
+	tracks := make([]string, len(croppedCoverTracks))
+	copy(tracks, croppedCoverTracks)
+	return tracks
+}
+
+// ClearCroppedCoverTracks resets the list of tracks with cropped album art
+func ClearCroppedCoverTracks() {
+	croppedCoverLock.Lock()
+	defer croppedCoverLock.Unlock()
+	croppedCoverTracks = nil
+}
@@ -0,0 +1,178 @@
+package backend
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// coverThrottleThreshold consecutive 429s pause a source
+	coverThrottleThreshold = 2
+	coverThrottleBaseDelay = 30 * time.Second
+	coverThrottleMaxDelay  = 10 * time.Minute
+
+	// MusicBrainz asks clients to stay at one request per second
+	musicBrainzMinInterval = 1100 * time.Millisecond
+)
+
+// defaultCoverSourceIntervals is the minimum gap between requests to each cover search host,
+// shared by every goroutine searching that source
+var defaultCoverSourceIntervals = map[string]time.Duration{
+	CoverSourceITunes:      300 * time.Millisecond,
+	CoverSourceDeezer:      150 * time.Millisecond,
+	CoverSourceMusicBrainz: musicBrainzMinInterval,
+}
+
+// CoverSourceThrottle reports how a cover source has been rate limiting us
+type CoverSourceThrottle struct {
+	Source      string `json:"source"`
+	RateLimited int    `json:"rate_limited"` // 429 responses since the last reset
+	Throttled   bool   `json:"throttled"`    // Currently skipped in favor of other sources
+	Pauses      int    `json:"pauses"`
+	ResumesAt   int64  `json:"resumes_at,omitempty"`
+}
+
+type coverSourceLimiter struct {
+	nextSlot     time.Time
+	consecutive  int
+	rateLimited  int
+	pauses       int
+	pausedUntil  time.Time
+	currentDelay time.Duration
+}
+
+var (
+	coverLimiters       = make(map[string]*coverSourceLimiter)
+	coverSearchInterval time.Duration // 0 uses the per-source defaults
+	coverLimitersLock   sync.Mutex
+)
+
+// SetCoverSearchInterval sets the minimum gap in milliseconds between requests to one cover
+// source; 0 restores the per-source defaults. MusicBrainz never goes below its 1 req/s policy.
+func SetCoverSearchInterval(intervalMs int) {
+	if intervalMs < 0 {
+		intervalMs = 0
+	}
+	coverLimitersLock.Lock()
+	coverSearchInterval = time.Duration(intervalMs) * time.Millisecond
+	coverLimitersLock.Unlock()
+}
+
+func coverSourceInterval(source string) time.Duration {
+	interval := defaultCoverSourceIntervals[source]
+	if coverSearchInterval > 0 {
+		interval = coverSearchInterval
+	}
+	if source == CoverSourceMusicBrainz && interval < musicBrainzMinInterval {
+		interval = musicBrainzMinInterval
+	}
+	return interval
+}
+
+func getCoverLimiter(source string) *coverSourceLimiter {
+	limiter, ok := coverLimiters[source]
+	if !ok {
+		limiter = &coverSourceLimiter{}
+		coverLimiters[source] = limiter
+	}
+	return limiter
+}
+
+// isCoverSourceThrottled reports whether a source is paused after repeated rate limiting
+func isCoverSourceThrottled(source string) bool {
+	coverLimitersLock.Lock()
+	defer coverLimitersLock.Unlock()
+	return time.Now().Before(getCoverLimiter(source).pausedUntil)
+}
+
+// waitForCoverSource reserves the next request slot for a source and sleeps until it arrives
+func waitForCoverSource(source string) {
+	coverLimitersLock.Lock()
+	limiter := getCoverLimiter(source)
+	now := time.Now()
+	slot := limiter.nextSlot
+	if slot.Before(now) {
+		slot = now
+	}
+	limiter.nextSlot = slot.Add(coverSourceInterval(source))
+	coverLimitersLock.Unlock()
+
+	time.Sleep(time.Until(slot))
+}
+
+// recordCoverSourceResult tracks 429s per source. Repeated ones pause the source with a
+// doubling delay so searches move on to other sources; any other response resets the streak.
+func recordCoverSourceResult(source string, err error) {
+	coverLimitersLock.Lock()
+	defer coverLimitersLock.Unlock()
+
+	limiter := getCoverLimiter(source)
+	if err == nil || classifyDownloadError(err) != "http_429" {
+		limiter.consecutive = 0
+		if err == nil {
+			limiter.currentDelay = 0
+		}
+		return
+	}
+
+	limiter.consecutive++
+	limiter.rateLimited++
+	if limiter.consecutive < coverThrottleThreshold {
+		return
+	}
+
+	if limiter.currentDelay == 0 {
+		limiter.currentDelay = coverThrottleBaseDelay
+	} else if limiter.currentDelay < coverThrottleMaxDelay {
+		limiter.currentDelay *= 2
+		if limiter.currentDelay > coverThrottleMaxDelay {
+			limiter.currentDelay = coverThrottleMaxDelay
+		}
+	}
+	limiter.consecutive = 0
+	limiter.pauses++
+	limiter.pausedUntil = time.Now().Add(limiter.currentDelay)
+	fmt.Printf("[Cover] %s is rate limiting, pausing it for %v\n", source, limiter.currentDelay)
+}
+
+// GetCoverSourceThrottles reports the sources that have been rate limited since the last reset
+func GetCoverSourceThrottles() []CoverSourceThrottle {
+	coverLimitersLock.Lock()
+	defer coverLimitersLock.Unlock()
+
+	now := time.Now()
+	throttles := make([]CoverSourceThrottle, 0)
+	for source, limiter := range coverLimiters {
+		if limiter.rateLimited == 0 {
+			continue
+		}
+		status := CoverSourceThrottle{
+			Source:      source,
+			RateLimited: limiter.rateLimited,
+			Pauses:      limiter.pauses,
+			Throttled:   now.Before(limiter.pausedUntil),
+		}
+		if status.Throttled {
+			status.ResumesAt = limiter.pausedUntil.Unix()
+		}
+		throttles = append(throttles, status)
+	}
+	sort.Slice(throttles, func(i, j int) bool { return throttles[i].Source < throttles[j].Source })
+	return throttles
+}
+
+// ResetCoverSourceThrottles clears rate-limit counters and lifts any pauses
+func ResetCoverSourceThrottles() {
+	coverLimitersLock.Lock()
+	defer coverLimitersLock.Unlock()
+
+	for _, limiter := range coverLimiters {
+		limiter.consecutive = 0
+		limiter.rateLimited = 0
+		limiter.pauses = 0
+		limiter.currentDelay = 0
+		limiter.pausedUntil = time.Time{}
+	}
+}
@@ -0,0 +1,131 @@
+package backend
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// CSV fields the parser understands, each with the header names used by Exportify (first),
+// TuneMyMusic, Soundiiz and other playlist exporters. Headers are compared normalized, so case,
+// spacing and punctuation don't matter ("Artist Name(s)" matches "artist_names").
+const (
+	csvColTrackURI    = "track_uri"
+	csvColTrackName   = "track_name"
+	csvColArtistName  = "artist_name"
+	csvColAlbumName   = "album_name"
+	csvColReleaseDate = "release_date"
+	csvColDurationMs  = "duration_ms"
+	csvColDuration    = "duration"
+	csvColPopularity  = "popularity"
+	csvColExplicit    = "explicit"
+	csvColISRC        = "isrc"
+	csvColAddedAt     = "added_at"
+	csvColGenre       = "genre"
+)
+
+var csvColumnAliases = map[string][]string{
+	csvColTrackURI:    {"Track URI", "Spotify URI", "Spotify URL", "Spotify Link", "Spotify Track URL", "Spotify Track ID", "Spotify ID", "Track URL", "Track Link", "URI", "URL", "Link"},
+	csvColTrackName:   {"Track Name", "Track Title", "Song Name", "Song Title", "Title", "Track", "Song", "Name"},
+	csvColArtistName:  {"Artist Name(s)", "Artist Names", "Artist Name", "Artists", "Artist", "Artist(s)"},
+	csvColAlbumName:   {"Album Name", "Album Title", "Album"},
+	csvColReleaseDate: {"Release Date", "Album Release Date", "Released", "Year"},
+	csvColDurationMs:  {"Duration (ms)", "Duration ms", "Track Duration (ms)", "Length (ms)"},
+	csvColDuration:    {"Duration", "Length", "Time"},
+	csvColPopularity:  {"Popularity"},
+	csvColExplicit:    {"Explicit"},
+	csvColISRC:        {"ISRC", "Track ISRC", "ISRC Code"},
+	csvColAddedAt:     {"Added At", "Date Added", "Added"},
+	csvColGenre:       {"Genres", "Genre", "Artist Genres"},
+}
+
+var spotifyBareID = regexp.MustCompile(`^[0-9A-Za-z]{22}$`)
+
+// normalizeCSVHeader reduces a header to lowercase letters and digits
+func normalizeCSVHeader(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+// detectCSVColumns maps each known field to its column index. Aliases are tried in order, so a
+// more specific header ("Track Name") wins over a generic one ("Name") when both exist.
+func detectCSVColumns(header []string) map[string]int {
+	normalized := make(map[string]int, len(header))
+	for i, col := range header {
+		key := normalizeCSVHeader(col)
+		if _, seen := normalized[key]; !seen {
+			normalized[key] = i
+		}
+	}
+
+	columns := make(map[string]int)
+	used := make(map[int]bool)
+	for _, field := range []string{csvColTrackURI, csvColTrackName, csvColArtistName, csvColAlbumName, csvColReleaseDate,
+		csvColDurationMs, csvColDuration, csvColPopularity, csvColExplicit, csvColISRC, csvColAddedAt, csvColGenre} {
+		for _, alias := range csvColumnAliases[field] {
+			if idx, ok := normalized[normalizeCSVHeader(alias)]; ok && !used[idx] {
+				columns[field] = idx
+				used[idx] = true
+				break
+			}
+		}
+	}
+	return columns
+}
+
+// spotifyTrackIDFromCSV extracts a track ID from a spotify:track: URI, an open.spotify.com
+// track URL (with or without scheme, locale prefix or query string) or a bare 22-character ID
+func spotifyTrackIDFromCSV(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return ""
+	}
+	if spotifyBareID.MatchString(value) {
+		return value
+	}
+	if strings.HasPrefix(value, "open.spotify.com/") || strings.HasPrefix(value, "play.spotify.com/") {
+		value = "https://" + value
+	}
+
+	parsed, err := parseSpotifyURI(value)
+	if err != nil || parsed.Type != "track" {
+		return ""
+	}
+	return parsed.ID
+}
+
+// parseCSVDuration reads a duration column that may hold "m:ss", "h:mm:ss", seconds or
+// milliseconds, returning milliseconds
+func parseCSVDuration(value string) int {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	if strings.Contains(value, ":") {
+		total := 0
+		for _, part := range strings.Split(value, ":") {
+			n, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil {
+				return 0
+			}
+			total = total*60 + n
+		}
+		return total * 1000
+	}
+
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	// No track is 3 hours long, so larger numbers are already milliseconds
+	if seconds > 10800 {
+		return int(seconds)
+	}
+	return int(seconds * 1000)
+}
@@ -0,0 +1,98 @@
+package backend
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// csvExportHeader matches the Spotify/Exportify columns ParseCSVPlaylist reads, so an
+// exported file can be imported again here or in other tools
+var csvExportHeader = []string{
+	"Track URI", "Track Name", "Album Name", "Artist Name(s)", "Release Date",
+	"Duration (ms)", "Popularity", "Explicit", "ISRC", "Added At",
+}
+
+// WriteCSVTracks writes tracks to filePath in the Spotify playlist CSV format
+func WriteCSVTracks(filePath string, tracks []CSVTrack) error {
+	filePath = NormalizePath(filePath)
+	if dir := filepath.Dir(filePath); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %v", err)
+		}
+	}
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to create CSV file: %v", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write(csvExportHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %v", err)
+	}
+
+	for _, track := range tracks {
+		trackURI := track.TrackURI
+		if trackURI == "" && track.SpotifyID != "" {
+			trackURI = "spotify:track:" + track.SpotifyID
+		}
+
+		record := []string{
+			trackURI,
+			track.TrackName,
+			track.AlbumName,
+			track.ArtistName,
+			track.ReleaseDate,
+			strconv.Itoa(track.DurationMs),
+			strconv.Itoa(track.Popularity),
+			strconv.FormatBool(track.Explicit),
+			track.ISRC,
+			track.AddedAt,
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV row: %v", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV file: %v", err)
+	}
+	return nil
+}
+
+// ExportFailedAsCSV writes the failed and unavailable queue items to filePath so the batch can
+// be retried later. Items queued without a Spotify ID get an empty Track URI and come back as
+// local rows that can be matched by name. Returns the number of rows written.
+func ExportFailedAsCSV(filePath string) (int, error) {
+	queue := GetDownloadQueue().Queue
+
+	var tracks []CSVTrack
+	for _, item := range queue {
+		if item.Status != StatusFailed && item.Status != StatusUnavailable {
+			continue
+		}
+		tracks = append(tracks, CSVTrack{
+			TrackName:  item.TrackName,
+			ArtistName: item.ArtistName,
+			AlbumName:  item.AlbumName,
+			SpotifyID:  item.SpotifyID,
+			ISRC:       item.ISRC,
+			Position:   len(tracks) + 1,
+		})
+	}
+
+	if len(tracks) == 0 {
+		return 0, fmt.Errorf("no failed tracks to export")
+	}
+	if err := WriteCSVTracks(filePath, tracks); err != nil {
+		return 0, err
+	}
+
+	fmt.Printf("[CSV Export] Wrote %d failed tracks to %s\n", len(tracks), filePath)
+	return len(tracks), nil
+}
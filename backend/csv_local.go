@@ -0,0 +1,123 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// LocalTrackMatch pairs a local/unavailable CSV row with the Spotify track found for it
+type LocalTrackMatch struct {
+	Track   CSVTrack `json:"track"`
+	Matched bool     `json:"matched"`
+	Match   CSVTrack `json:"match,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// fillFromLocalURI fills empty fields from a "spotify:local:artist:album:title:seconds" URI,
+// which is all Spotify exports for some local files
+func fillFromLocalURI(track *CSVTrack) {
+	parts := strings.Split(track.TrackURI, ":")
+	if len(parts) != 6 || parts[0] != "spotify" || parts[1] != "local" {
+		return
+	}
+
+	unescape := func(s string) string {
+		if decoded, err := url.QueryUnescape(s); err == nil {
+			return strings.TrimSpace(decoded)
+		}
+		return strings.TrimSpace(s)
+	}
+
+	if track.ArtistName == "" {
+		track.ArtistName = unescape(parts[2])
+	}
+	if track.AlbumName == "" {
+		track.AlbumName = unescape(parts[3])
+	}
+	if track.TrackName == "" {
+		track.TrackName = unescape(parts[4])
+	}
+	if track.DurationMs == 0 {
+		if seconds, err := strconv.Atoi(parts[5]); err == nil {
+			track.DurationMs = seconds * 1000
+		}
+	}
+}
+
+// MatchLocalCSVTracks searches Spotify by title and artist for each local/unavailable row.
+// Matched rows come back as regular CSV tracks that can be queued like any other.
+func MatchLocalCSVTracks(ctx context.Context, tracks []CSVTrack) []LocalTrackMatch {
+	results := make([]LocalTrackMatch, len(tracks))
+	client := NewSpotifyMetadataClient()
+
+	const maxWorkers = 4
+	var wg sync.WaitGroup
+	jobs := make(chan int, len(tracks))
+	for i := range tracks {
+		jobs <- i
+	}
+	close(jobs)
+
+	for w := 0; w < maxWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = matchLocalCSVTrack(ctx, client, tracks[i])
+			}
+		}()
+	}
+	wg.Wait()
+
+	matched := 0
+	for _, result := range results {
+		if result.Matched {
+			matched++
+		}
+	}
+	fmt.Printf("[CSV Parser] Matched %d/%d local tracks on Spotify\n", matched, len(tracks))
+	return results
+}
+
+func matchLocalCSVTrack(ctx context.Context, client *SpotifyMetadataClient, track CSVTrack) LocalTrackMatch {
+	result := LocalTrackMatch{Track: track}
+
+	query := track.TrackName
+	if track.ArtistName != "" {
+		query = fmt.Sprintf("track:%s artist:%s", track.TrackName, track.ArtistName)
+	}
+	candidates, err := client.SearchByType(ctx, query, "track", 5, 0)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	wantTitle := normalizeMatchKey(track.TrackName)
+	for _, candidate := range candidates {
+		if normalizeMatchKey(candidate.Name) != wantTitle {
+			continue
+		}
+		result.Matched = true
+		result.Match = CSVTrack{
+			TrackURI:    "spotify:track:" + candidate.ID,
+			TrackName:   candidate.Name,
+			AlbumName:   candidate.AlbumName,
+			ArtistName:  candidate.Artists,
+			ReleaseDate: candidate.ReleaseDate,
+			DurationMs:  candidate.Duration,
+			SpotifyID:   candidate.ID,
+			ISRC:        candidate.ISRC,
+			AddedAt:     track.AddedAt,
+			Position:    track.Position,
+			Playlist:    track.Playlist,
+		}
+		return result
+	}
+
+	result.Error = "no matching Spotify track found"
+	return result
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/csv"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"unicode"
@@ -20,16 +22,46 @@ type CSVTrack struct {
 	Popularity  int    `json:"popularity"`
 	Explicit    bool   `json:"explicit"`
 	SpotifyID   string `json:"spotify_id"`
+	ISRC        string `json:"isrc,omitempty"`
+	AddedAt     string `json:"added_at,omitempty"`
+	Genre       string `json:"genre,omitempty"`
+	Position    int    `json:"position"`
+	Playlist    string `json:"playlist,omitempty"`
+	IsLocal     bool   `json:"is_local,omitempty"`    // Local file or unavailable row without a Spotify track URI
+	Unavailable bool   `json:"unavailable,omitempty"` // Spotify ID no longer resolves (set by ValidateCSVTracks)
 }
 
-// ParseCSVPlaylist parses a Spotify exported CSV file
+// CSV sort options
+const (
+	CSVSortOriginal    = "original"
+	CSVSortArtist      = "artist"
+	CSVSortAlbum       = "album"
+	CSVSortReleaseDate = "release_date"
+)
+
+// ParseCSVPlaylist parses a Spotify exported CSV file, keeping the original playlist order
 func ParseCSVPlaylist(filePath string) ([]CSVTrack, error) {
+	return ParseCSVPlaylistSorted(filePath, CSVSortOriginal)
+}
+
+// ParseCSVPlaylistSorted parses a Spotify exported CSV file and reorders tracks by sortBy
+func ParseCSVPlaylistSorted(filePath string, sortBy string) ([]CSVTrack, error) {
+	tracks, _, err := ParseCSVPlaylistWithLocal(filePath, sortBy)
+	if err == nil && len(tracks) == 0 {
+		return nil, fmt.Errorf("no valid tracks found in CSV file")
+	}
+	return tracks, err
+}
+
+// ParseCSVPlaylistWithLocal parses a Spotify exported CSV file and also returns the rows that
+// have no Spotify track URI (local files, removed tracks) instead of dropping them
+func ParseCSVPlaylistWithLocal(filePath string, sortBy string) ([]CSVTrack, []CSVTrack, error) {
 	fmt.Printf("\n[CSV Parser] Opening file: %s\n", filePath)
 
 	file, err := os.Open(filePath)
 	if err != nil {
 		fmt.Printf("[CSV Parser] ERROR opening file: %v\n", err)
-		return nil, fmt.Errorf("failed to open CSV file: %v", err)
+		return nil, nil, fmt.Errorf("failed to open CSV file: %v", err)
 	}
 	defer file.Close()
 
@@ -42,7 +74,7 @@ func ParseCSVPlaylist(filePath string) ([]CSVTrack, error) {
 	header, err := reader.Read()
 	if err != nil {
 		fmt.Printf("[CSV Parser] ERROR reading header: %v\n", err)
-		return nil, fmt.Errorf("failed to read CSV header: %v", err)
+		return nil, nil, fmt.Errorf("failed to read CSV header: %v", err)
 	}
 
 	// Clean header columns - remove BOM, trim space, and remove non-printable characters
@@ -63,24 +95,23 @@ func ParseCSVPlaylist(filePath string) ([]CSVTrack, error) {
 
 	fmt.Printf("[CSV Parser] Header columns (cleaned): %v\n", header)
 
-	// Find column indices
-	colMap := make(map[string]int)
-	for i, col := range header {
-		colMap[col] = i
-	}
-
-	// Verify required columns exist
-	requiredCols := []string{"Track URI", "Track Name", "Artist Name(s)"}
-	for _, col := range requiredCols {
-		if _, ok := colMap[col]; !ok {
-			fmt.Printf("[CSV Parser] ERROR: Missing required column: %s\n", col)
-			fmt.Printf("[CSV Parser] Available columns: %v\n", header)
-			return nil, fmt.Errorf("missing required column: %s", col)
-		}
+	// Find column indices; header names from other exporters are matched through aliases
+	colMap := detectCSVColumns(header)
+	fmt.Printf("[CSV Parser] Detected columns: %v\n", colMap)
+
+	// A Spotify link is enough; without one, rows are matched by title and artist
+	_, hasURI := colMap[csvColTrackURI]
+	_, hasName := colMap[csvColTrackName]
+	_, hasArtist := colMap[csvColArtistName]
+	if !hasURI && !(hasName && hasArtist) {
+		fmt.Printf("[CSV Parser] ERROR: No Spotify link column and no title/artist columns\n")
+		fmt.Printf("[CSV Parser] Available columns: %v\n", header)
+		return nil, nil, fmt.Errorf("missing required columns: need a Spotify URI/URL column, or track name and artist columns")
 	}
 	fmt.Println("[CSV Parser] All required columns found")
 
 	var tracks []CSVTrack
+	var localTracks []CSVTrack
 
 	// Read all rows
 	fmt.Println("[CSV Parser] Reading rows...")
@@ -101,92 +132,197 @@ func ParseCSVPlaylist(filePath string) ([]CSVTrack, error) {
 
 		track := CSVTrack{}
 
-		// Track URI (e.g., "spotify:track:7LsYnC8kNpGZSDDDulmXph")
-		if idx, ok := colMap["Track URI"]; ok && idx < len(record) {
+		// Track URI (e.g., "spotify:track:7LsYnC8kNpGZSDDDulmXph" or an open.spotify.com URL)
+		if idx, ok := colMap[csvColTrackURI]; ok && idx < len(record) {
 			track.TrackURI = strings.TrimSpace(record[idx])
-			// Extract Spotify ID from URI
-			parts := strings.Split(track.TrackURI, ":")
-			if len(parts) == 3 && parts[0] == "spotify" && parts[1] == "track" {
-				track.SpotifyID = parts[2]
+			track.SpotifyID = spotifyTrackIDFromCSV(track.TrackURI)
+			// Local file URIs are parsed later by fillFromLocalURI
+			if track.SpotifyID != "" && !strings.HasPrefix(track.TrackURI, "spotify:track:") {
+				track.TrackURI = "spotify:track:" + track.SpotifyID
 			}
 		}
 
-		// Skip if no valid Spotify ID
-		if track.SpotifyID == "" {
-			fmt.Printf("[CSV Parser] Row %d: Skipping - no valid Spotify ID\n", rowCount)
-			continue
-		}
-
 		// Track Name
-		if idx, ok := colMap["Track Name"]; ok && idx < len(record) {
+		if idx, ok := colMap[csvColTrackName]; ok && idx < len(record) {
 			track.TrackName = strings.TrimSpace(record[idx])
 		}
 
 		// Album Name
-		if idx, ok := colMap["Album Name"]; ok && idx < len(record) {
+		if idx, ok := colMap[csvColAlbumName]; ok && idx < len(record) {
 			track.AlbumName = strings.TrimSpace(record[idx])
 		}
 
 		// Artist Name(s)
-		if idx, ok := colMap["Artist Name(s)"]; ok && idx < len(record) {
+		if idx, ok := colMap[csvColArtistName]; ok && idx < len(record) {
 			track.ArtistName = strings.TrimSpace(record[idx])
 		}
 
 		// Release Date
-		if idx, ok := colMap["Release Date"]; ok && idx < len(record) {
+		if idx, ok := colMap[csvColReleaseDate]; ok && idx < len(record) {
 			track.ReleaseDate = strings.TrimSpace(record[idx])
 		}
 
-		// Duration (ms)
-		if idx, ok := colMap["Duration (ms)"]; ok && idx < len(record) {
+		// Duration (ms), or a duration in m:ss or seconds from other exporters
+		if idx, ok := colMap[csvColDurationMs]; ok && idx < len(record) {
 			if duration, err := strconv.Atoi(strings.TrimSpace(record[idx])); err == nil {
 				track.DurationMs = duration
 			}
+		} else if idx, ok := colMap[csvColDuration]; ok && idx < len(record) {
+			track.DurationMs = parseCSVDuration(record[idx])
 		}
 
 		// Popularity
-		if idx, ok := colMap["Popularity"]; ok && idx < len(record) {
+		if idx, ok := colMap[csvColPopularity]; ok && idx < len(record) {
 			if popularity, err := strconv.Atoi(strings.TrimSpace(record[idx])); err == nil {
 				track.Popularity = popularity
 			}
 		}
 
 		// Explicit
-		if idx, ok := colMap["Explicit"]; ok && idx < len(record) {
+		if idx, ok := colMap[csvColExplicit]; ok && idx < len(record) {
 			explicit := strings.ToLower(strings.TrimSpace(record[idx]))
 			track.Explicit = explicit == "true"
 		}
 
+		// ISRC (optional, lets the download skip Spotify ID -> ISRC resolution)
+		if idx, ok := colMap[csvColISRC]; ok && idx < len(record) {
+			track.ISRC = strings.ToUpper(strings.TrimSpace(record[idx]))
+		}
+
+		// Added At (optional)
+		if idx, ok := colMap[csvColAddedAt]; ok && idx < len(record) {
+			track.AddedAt = strings.TrimSpace(record[idx])
+		}
+
+		// Genre (optional, Exportify uses "Genres" with comma separated values)
+		if idx, ok := colMap[csvColGenre]; ok && idx < len(record) {
+			track.Genre = strings.TrimSpace(record[idx])
+		}
+
+		// Rows without a Spotify ID can't be downloaded directly; keep them for review and matching
+		if track.SpotifyID == "" {
+			fillFromLocalURI(&track)
+			if track.TrackName == "" {
+				fmt.Printf("[CSV Parser] Row %d: Skipping - no valid Spotify ID or track name\n", rowCount)
+				continue
+			}
+			fmt.Printf("[CSV Parser] Row %d: Local/unavailable track: %s - %s\n", rowCount, track.TrackName, track.ArtistName)
+			track.IsLocal = true
+			track.Position = len(localTracks) + 1
+			localTracks = append(localTracks, track)
+			continue
+		}
+
 		tracks = append(tracks, track)
 	}
 
-	fmt.Printf("[CSV Parser] Processed %d rows, found %d valid tracks\n", rowCount, len(tracks))
+	fmt.Printf("[CSV Parser] Processed %d rows, found %d valid tracks, %d local/unavailable\n", rowCount, len(tracks), len(localTracks))
 
-	if len(tracks) == 0 {
+	if len(tracks) == 0 && len(localTracks) == 0 {
 		fmt.Println("[CSV Parser] ERROR: No valid tracks found")
-		return nil, fmt.Errorf("no valid tracks found in CSV file")
+		return nil, nil, fmt.Errorf("no valid tracks found in CSV file")
 	}
 
+	tracks = SortCSVTracks(tracks, sortBy)
+
 	fmt.Printf("[CSV Parser] Successfully parsed %d tracks\n", len(tracks))
-	return tracks, nil
+	return tracks, localTracks, nil
+}
+
+// SortCSVTracks reorders tracks by artist, album, release date or original order and
+// reassigns Position accordingly. Sorting is stable so equal keys keep input order.
+func SortCSVTracks(tracks []CSVTrack, sortBy string) []CSVTrack {
+	sorted := make([]CSVTrack, len(tracks))
+	copy(sorted, tracks)
+
+	sortBy = strings.ToLower(strings.TrimSpace(sortBy))
+	switch sortBy {
+	case CSVSortArtist:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return strings.ToLower(sorted[i].ArtistName) < strings.ToLower(sorted[j].ArtistName)
+		})
+	case CSVSortAlbum:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return strings.ToLower(sorted[i].AlbumName) < strings.ToLower(sorted[j].AlbumName)
+		})
+	case CSVSortReleaseDate:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].ReleaseDate < sorted[j].ReleaseDate
+		})
+	case "", CSVSortOriginal:
+		// Keep playlist order
+	default:
+		fmt.Printf("[CSV Parser] Unknown sort option '%s', keeping original order\n", sortBy)
+	}
+
+	for i := range sorted {
+		sorted[i].Position = i + 1
+	}
+
+	return sorted
+}
+
+// CSVDurationFilterResult represents the result of filtering CSV tracks by duration
+type CSVDurationFilterResult struct {
+	Tracks             []CSVTrack `json:"tracks"`
+	TrackCount         int        `json:"track_count"`
+	SkippedCount       int        `json:"skipped_count"`
+	SkippedTracks      []CSVTrack `json:"skipped_tracks,omitempty"`
+	MinDurationSeconds int        `json:"min_duration_seconds"`
+}
+
+// FilterCSVTracksByDuration drops tracks shorter than minDurationSeconds (0 = no filter).
+// Tracks with unknown duration are kept.
+func FilterCSVTracksByDuration(tracks []CSVTrack, minDurationSeconds int) CSVDurationFilterResult {
+	result := CSVDurationFilterResult{
+		MinDurationSeconds: minDurationSeconds,
+	}
+
+	if minDurationSeconds <= 0 {
+		result.Tracks = tracks
+		result.TrackCount = len(tracks)
+		return result
+	}
+
+	minMs := minDurationSeconds * 1000
+	kept := make([]CSVTrack, 0, len(tracks))
+	for _, track := range tracks {
+		if track.DurationMs > 0 && track.DurationMs < minMs {
+			fmt.Printf("[CSV Parser] Skipping short track (%ds): %s - %s\n", track.DurationMs/1000, track.TrackName, track.ArtistName)
+			result.SkippedTracks = append(result.SkippedTracks, track)
+			continue
+		}
+		kept = append(kept, track)
+	}
+
+	result.Tracks = kept
+	result.TrackCount = len(kept)
+	result.SkippedCount = len(result.SkippedTracks)
+	fmt.Printf("[CSV Parser] Duration filter (min %ds): kept %d, skipped %d\n", minDurationSeconds, result.TrackCount, result.SkippedCount)
+	return result
 }
 
 // CSVParseResult represents the result of parsing a CSV file
 type CSVParseResult struct {
-	Success    bool       `json:"success"`
-	TrackCount int        `json:"track_count"`
-	Tracks     []CSVTrack `json:"tracks"`
-	Error      string     `json:"error,omitempty"`
+	Success     bool       `json:"success"`
+	TrackCount  int        `json:"track_count"`
+	Tracks      []CSVTrack `json:"tracks"`
+	LocalTracks []CSVTrack `json:"local_tracks,omitempty"` // Rows without a Spotify track URI
+	Error       string     `json:"error,omitempty"`
+
+	Validation *CSVValidationReport `json:"validation,omitempty"` // Set when CSV validation is enabled
 }
 
 // CSVFileParseResult represents the result of parsing a single CSV file with its filename
 type CSVFileParseResult struct {
-	FilePath   string     `json:"file_path"`
-	FileName   string     `json:"file_name"`
-	Success    bool       `json:"success"`
-	TrackCount int        `json:"track_count"`
-	Tracks     []CSVTrack `json:"tracks"`
-	Error      string     `json:"error,omitempty"`
+	FilePath     string     `json:"file_path"`
+	FileName     string     `json:"file_name"`
+	PlaylistName string     `json:"playlist_name"`
+	Success      bool       `json:"success"`
+	TrackCount   int        `json:"track_count"`
+	Tracks       []CSVTrack `json:"tracks"`
+	LocalTracks  []CSVTrack `json:"local_tracks,omitempty"`
+	Error        string     `json:"error,omitempty"`
 }
 
 // BatchCSVParseResult represents the result of parsing multiple CSV files
@@ -195,8 +331,11 @@ type BatchCSVParseResult struct {
 	TotalFiles      int                  `json:"total_files"`
 	SuccessfulFiles int                  `json:"successful_files"`
 	TotalTracks     int                  `json:"total_tracks"`
+	TotalLocal      int                  `json:"total_local"`
 	Files           []CSVFileParseResult `json:"files"`
 	Error           string               `json:"error,omitempty"`
+
+	Validation *CSVValidationReport `json:"validation,omitempty"` // All files combined, set when CSV validation is enabled
 }
 
 // ParseMultipleCSVFiles parses multiple CSV files and returns aggregated results
@@ -217,23 +356,32 @@ func ParseMultipleCSVFiles(filePaths []string) BatchCSVParseResult {
 		fileName := parts[len(parts)-1]
 
 		fileResult := CSVFileParseResult{
-			FilePath: filePath,
-			FileName: fileName,
+			FilePath:     filePath,
+			FileName:     fileName,
+			PlaylistName: strings.TrimSuffix(fileName, filepath.Ext(fileName)),
 		}
 
-		// Parse the CSV file
-		tracks, err := ParseCSVPlaylist(filePath)
+		// Parse the CSV, text or M3U file
+		tracks, localTracks, err := ParsePlaylistFileWithLocal(filePath, CSVSortOriginal)
 		if err != nil {
 			fmt.Printf("[Batch CSV Parser] ERROR parsing file %s: %v\n", fileName, err)
 			fileResult.Success = false
 			fileResult.Error = err.Error()
 		} else {
 			fmt.Printf("[Batch CSV Parser] Successfully parsed %d tracks from %s\n", len(tracks), fileName)
+			for j := range tracks {
+				tracks[j].Playlist = fileResult.PlaylistName
+			}
+			for j := range localTracks {
+				localTracks[j].Playlist = fileResult.PlaylistName
+			}
 			fileResult.Success = true
 			fileResult.TrackCount = len(tracks)
 			fileResult.Tracks = tracks
+			fileResult.LocalTracks = localTracks
 			result.SuccessfulFiles++
 			result.TotalTracks += len(tracks)
+			result.TotalLocal += len(localTracks)
 		}
 
 		result.Files = append(result.Files, fileResult)
@@ -250,3 +398,12 @@ func ParseMultipleCSVFiles(filePaths []string) BatchCSVParseResult {
 
 	return result
 }
+
+// PlaylistOutputDir returns the folder a playlist's tracks go to when a batch routes each
+// CSV into its own subfolder of baseDir
+func PlaylistOutputDir(baseDir, playlistName string) string {
+	if playlistName == "" {
+		return baseDir
+	}
+	return filepath.Join(baseDir, sanitizeFolderName(playlistName))
+}
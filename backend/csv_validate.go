@@ -0,0 +1,172 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// CSV validation statuses
+const (
+	CSVTrackValid      = "valid"
+	CSVTrackRenamed    = "renamed"    // Still resolves, but Spotify now has a different title
+	CSVTrackDead       = "dead"       // Spotify no longer knows the ID; the download would fail
+	CSVTrackUnverified = "unverified" // Lookup failed for another reason (network, rate limit)
+)
+
+// CSVTrackValidation is the Spotify check result for one CSV track
+type CSVTrackValidation struct {
+	SpotifyID   string `json:"spotify_id"`
+	TrackName   string `json:"track_name"`
+	ArtistName  string `json:"artist_name"`
+	Position    int    `json:"position"`
+	Status      string `json:"status"`
+	CurrentName string `json:"current_name,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// CSVValidationReport summarizes a validation run. Results only lists tracks that aren't valid.
+type CSVValidationReport struct {
+	Checked    int                  `json:"checked"`
+	Valid      int                  `json:"valid"`
+	Renamed    int                  `json:"renamed"`
+	Dead       int                  `json:"dead"`
+	Unverified int                  `json:"unverified"`
+	Results    []CSVTrackValidation `json:"results,omitempty"`
+}
+
+const csvValidationWorkers = 4
+
+// csvValidationCache remembers definite answers per Spotify ID, so re-importing the same
+// playlists doesn't hit Spotify again; unverified lookups are retried next time
+var (
+	csvValidationCache     = make(map[string]CSVTrackValidation)
+	csvValidationCacheLock sync.RWMutex
+)
+
+// ValidateCSVTracks checks that every track's Spotify ID still resolves before it is queued.
+// Tracks Spotify no longer knows are flagged Unavailable in place.
+func ValidateCSVTracks(ctx context.Context, tracks []CSVTrack) CSVValidationReport {
+	results := make([]CSVTrackValidation, len(tracks))
+	client := NewSpotifyMetadataClient()
+
+	token, tokenErr := client.getAccessToken(ctx)
+
+	var wg sync.WaitGroup
+	jobs := make(chan int, len(tracks))
+	for i := range tracks {
+		jobs <- i
+	}
+	close(jobs)
+
+	for w := 0; w < csvValidationWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if tokenErr != nil {
+					results[i] = newCSVTrackValidation(tracks[i], CSVTrackUnverified)
+					results[i].Error = fmt.Sprintf("failed to get access token: %v", tokenErr)
+					continue
+				}
+				results[i] = validateCSVTrack(ctx, client, token, tracks[i])
+			}
+		}()
+	}
+	wg.Wait()
+
+	report := CSVValidationReport{Checked: len(tracks)}
+	for i, result := range results {
+		switch result.Status {
+		case CSVTrackValid:
+			report.Valid++
+			continue
+		case CSVTrackRenamed:
+			report.Renamed++
+		case CSVTrackDead:
+			report.Dead++
+			tracks[i].Unavailable = true
+		default:
+			report.Unverified++
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	fmt.Printf("[CSV Validate] %d tracks: %d valid, %d renamed, %d dead, %d unverified\n",
+		report.Checked, report.Valid, report.Renamed, report.Dead, report.Unverified)
+	return report
+}
+
+func newCSVTrackValidation(track CSVTrack, status string) CSVTrackValidation {
+	return CSVTrackValidation{
+		SpotifyID:  track.SpotifyID,
+		TrackName:  track.TrackName,
+		ArtistName: track.ArtistName,
+		Position:   track.Position,
+		Status:     status,
+	}
+}
+
+func validateCSVTrack(ctx context.Context, client *SpotifyMetadataClient, token string, track CSVTrack) CSVTrackValidation {
+	if track.SpotifyID == "" {
+		result := newCSVTrackValidation(track, CSVTrackDead)
+		result.Error = "no Spotify ID"
+		return result
+	}
+
+	csvValidationCacheLock.RLock()
+	cached, ok := csvValidationCache[track.SpotifyID]
+	csvValidationCacheLock.RUnlock()
+	if ok {
+		result := newCSVTrackValidation(track, cached.Status)
+		result.CurrentName = cached.CurrentName
+		result.Error = cached.Error
+		return result
+	}
+
+	result := newCSVTrackValidation(track, CSVTrackValid)
+	raw, err := client.fetchTrack(ctx, track.SpotifyID, token)
+	switch {
+	case err != nil && isSpotifyNotFound(err):
+		result.Status = CSVTrackDead
+		result.Error = "track no longer exists on Spotify"
+	case err != nil:
+		result.Status = CSVTrackUnverified
+		result.Error = err.Error()
+		return result
+	case track.TrackName != "" && normalizeMatchKey(raw.Name) != normalizeMatchKey(track.TrackName):
+		result.Status = CSVTrackRenamed
+		result.CurrentName = raw.Name
+	}
+
+	csvValidationCacheLock.Lock()
+	csvValidationCache[track.SpotifyID] = result
+	csvValidationCacheLock.Unlock()
+	return result
+}
+
+// isSpotifyNotFound reports whether a Spotify API error means the ID doesn't resolve
+// (404, or 400 for IDs that are no longer valid)
+func isSpotifyNotFound(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "status 404") || strings.Contains(msg, "status 400")
+}
+
+// ValidateBatchCSVTracks validates the tracks of every parsed file and attaches the combined report
+func ValidateBatchCSVTracks(ctx context.Context, batch *BatchCSVParseResult) {
+	combined := CSVValidationReport{}
+	for i := range batch.Files {
+		if !batch.Files[i].Success || len(batch.Files[i].Tracks) == 0 {
+			continue
+		}
+		report := ValidateCSVTracks(ctx, batch.Files[i].Tracks)
+		combined.Checked += report.Checked
+		combined.Valid += report.Valid
+		combined.Renamed += report.Renamed
+		combined.Dead += report.Dead
+		combined.Unverified += report.Unverified
+		combined.Results = append(combined.Results, report.Results...)
+	}
+	batch.Validation = &combined
+}
@@ -3,6 +3,7 @@ package backend
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 
 	_ "modernc.org/sqlite"
 )
@@ -150,8 +151,14 @@ func GetAlbumCoverFromDatabase(databasePath string, albumName string) (string, e
 	err = db.QueryRow(albumQuery, albumName).Scan(&albumRowID)
 
 	if err == sql.ErrNoRows {
-		// Album not found, return empty
-		return "", nil
+		// No exact match: fall back to a normalized fuzzy match (edition suffixes, punctuation, case)
+		rowID, matchedName, score, fuzzyErr := findAlbumRowIDFuzzy(db, albumName)
+		if fuzzyErr != nil {
+			return "", nil
+		}
+		fmt.Printf("[Database] Fuzzy album match '%s' -> '%s' (%.2f)\n", albumName, matchedName, score)
+		albumRowID = rowID
+		err = nil
 	}
 
 	if err != nil {
@@ -246,3 +253,191 @@ func GetCoverByTrackFromDatabase(databasePath string, trackName string, artistNa
 	fmt.Printf("[Database] Found cover via track search '%s - %s': %s\n", trackName, artistName, coverURL)
 	return coverURL, nil
 }
+
+// GetISRCByTrackFromDatabase queries the local SQLite database for ISRC by track name and artist
+// Returns empty string if database is not configured or no match is found
+func GetISRCByTrackFromDatabase(databasePath string, trackName string, artistName string) (string, error) {
+	if databasePath == "" {
+		return "", nil
+	}
+
+	db, err := sql.Open("sqlite", databasePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		return "", fmt.Errorf("failed to connect to database: %v", err)
+	}
+
+	var isrc string
+	query := `
+		SELECT external_id_isrc 
+		FROM tracks 
+		WHERE LOWER(name) LIKE LOWER(?) 
+		AND (
+			LOWER(artists) LIKE LOWER(?) 
+			OR LOWER(artists) LIKE LOWER(?)
+		)
+		AND external_id_isrc IS NOT NULL AND external_id_isrc != ''
+		LIMIT 1
+	`
+	err = db.QueryRow(query, trackName, "%"+artistName+"%", artistName+"%").Scan(&isrc)
+
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to query track: %v", err)
+	}
+
+	fmt.Printf("[Database] Found ISRC via track search '%s - %s': %s\n", trackName, artistName, isrc)
+	return isrc, nil
+}
+
+// ISRCMatch is one tracks row linking a Spotify ID and an ISRC, with the album details used to
+// tell re-releases apart
+type ISRCMatch struct {
+	SpotifyID   string `json:"spotify_id"`
+	ISRC        string `json:"isrc"`
+	TrackName   string `json:"track_name"`
+	Artists     string `json:"artists"`
+	AlbumName   string `json:"album_name,omitempty"`
+	ReleaseDate string `json:"release_date,omitempty"`
+}
+
+// queryISRCMatches runs a tracks lookup on column = value, joining album name and release date
+// when the database has them. Dumps without albums.release_date fall back to tracks alone.
+func queryISRCMatches(databasePath, column, value string) ([]ISRCMatch, error) {
+	if databasePath == "" {
+		return nil, nil
+	}
+
+	db, err := sql.Open("sqlite", databasePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %v", err)
+	}
+
+	withAlbum := true
+	rows, err := db.Query(fmt.Sprintf(`
+		SELECT t.id, COALESCE(t.external_id_isrc, ''), COALESCE(t.name, ''), COALESCE(t.artists, ''),
+			COALESCE(a.name, ''), COALESCE(a.release_date, '')
+		FROM tracks t
+		LEFT JOIN albums a ON a.rowid = t.album_rowid
+		WHERE t.%s = ? COLLATE NOCASE
+	`, column), value)
+	if err != nil {
+		withAlbum = false
+		rows, err = db.Query(fmt.Sprintf(`
+			SELECT id, COALESCE(external_id_isrc, ''), COALESCE(name, ''), COALESCE(artists, '')
+			FROM tracks
+			WHERE %s = ? COLLATE NOCASE
+		`, column), value)
+		if err != nil {
+			return nil, fmt.Errorf("database query error: %v", err)
+		}
+	}
+	defer rows.Close()
+
+	matches := make([]ISRCMatch, 0)
+	for rows.Next() {
+		var match ISRCMatch
+		if withAlbum {
+			err = rows.Scan(&match.SpotifyID, &match.ISRC, &match.TrackName, &match.Artists, &match.AlbumName, &match.ReleaseDate)
+		} else {
+			err = rows.Scan(&match.SpotifyID, &match.ISRC, &match.TrackName, &match.Artists)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read row: %v", err)
+		}
+		if match.ISRC == "" {
+			continue
+		}
+		matches = append(matches, match)
+	}
+	return matches, rows.Err()
+}
+
+// GetAllISRCsForSpotifyID returns every ISRC recorded for a Spotify ID instead of an arbitrary first one
+func GetAllISRCsForSpotifyID(databasePath string, spotifyID string) ([]ISRCMatch, error) {
+	matches, err := queryISRCMatches(databasePath, "id", spotifyID)
+	if err == nil && len(matches) > 1 {
+		fmt.Printf("[Database] Spotify ID %s has %d ISRCs\n", spotifyID, len(matches))
+	}
+	return matches, err
+}
+
+// GetSpotifyIDsForISRC returns every Spotify ID (original release, remasters, compilations, ...) sharing an ISRC
+func GetSpotifyIDsForISRC(databasePath string, isrc string) ([]ISRCMatch, error) {
+	matches, err := queryISRCMatches(databasePath, "external_id_isrc", strings.TrimSpace(isrc))
+	if err == nil && len(matches) > 1 {
+		fmt.Printf("[Database] ISRC %s is shared by %d Spotify IDs\n", isrc, len(matches))
+	}
+	return matches, err
+}
+
+// GetSpotifyIDFromISRC is the reverse of GetISRCFromDatabase: it returns the Spotify ID of the
+// first track with the ISRC, or an empty string when none is recorded
+func GetSpotifyIDFromISRC(databasePath string, isrc string) (string, error) {
+	ids, err := GetAllSpotifyIDsFromISRC(databasePath, isrc)
+	if err != nil || len(ids) == 0 {
+		return "", err
+	}
+	fmt.Printf("[Database] Found Spotify ID: %s for ISRC: %s\n", ids[0], isrc)
+	return ids[0], nil
+}
+
+// GetAllSpotifyIDsFromISRC returns the Spotify IDs of every track with the ISRC, in database
+// order and without duplicates. Use GetSpotifyIDsForISRC when the album details are needed.
+func GetAllSpotifyIDsFromISRC(databasePath string, isrc string) ([]string, error) {
+	matches, err := GetSpotifyIDsForISRC(databasePath, isrc)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(matches))
+	seen := make(map[string]bool, len(matches))
+	for _, match := range matches {
+		if match.SpotifyID == "" || seen[match.SpotifyID] {
+			continue
+		}
+		seen[match.SpotifyID] = true
+		ids = append(ids, match.SpotifyID)
+	}
+	return ids, nil
+}
+
+// PickISRCMatch chooses among colliding rows: an exact album name match first, then the same
+// release year, then the first row. ok is false when there are no matches.
+func PickISRCMatch(matches []ISRCMatch, albumName, releaseDate string) (ISRCMatch, bool) {
+	if len(matches) == 0 {
+		return ISRCMatch{}, false
+	}
+
+	if albumName != "" {
+		albumKey := normalizeMatchKey(albumName)
+		for _, match := range matches {
+			if match.AlbumName != "" && normalizeMatchKey(match.AlbumName) == albumKey {
+				return match, true
+			}
+		}
+	}
+
+	if len(releaseDate) >= 4 {
+		year := releaseDate[:4]
+		for _, match := range matches {
+			if strings.HasPrefix(match.ReleaseDate, year) {
+				return match, true
+			}
+		}
+	}
+
+	return matches[0], true
+}
@@ -0,0 +1,133 @@
+package backend
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+
+	"modernc.org/sqlite"
+)
+
+// sqliteBackupConn is implemented by the modernc.org/sqlite driver connection
+type sqliteBackupConn interface {
+	NewBackup(dstUri string) (*sqlite.Backup, error)
+	NewRestore(srcUri string) (*sqlite.Backup, error)
+}
+
+// runSQLiteBackup opens databasePath and runs the online backup API in the given direction.
+// When restore is false the database is copied to otherPath, otherwise otherPath is copied into it.
+func runSQLiteBackup(databasePath, otherPath string, restore bool) error {
+	db, err := sql.Open("sqlite", databasePath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		return fmt.Errorf("failed to connect to database: %v", err)
+	}
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %v", err)
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn interface{}) error {
+		bc, ok := driverConn.(sqliteBackupConn)
+		if !ok {
+			return fmt.Errorf("sqlite driver does not support online backup")
+		}
+
+		var backup *sqlite.Backup
+		if restore {
+			backup, err = bc.NewRestore(otherPath)
+		} else {
+			backup, err = bc.NewBackup(otherPath)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to start backup: %v", err)
+		}
+
+		for {
+			more, err := backup.Step(-1)
+			if err != nil {
+				backup.Finish()
+				return fmt.Errorf("backup step failed: %v", err)
+			}
+			if !more {
+				break
+			}
+		}
+
+		if err := backup.Finish(); err != nil {
+			return fmt.Errorf("failed to finish backup: %v", err)
+		}
+		return nil
+	})
+}
+
+// BackupDatabase copies a live SQLite database to dest using the online backup API
+// and verifies the copy before reporting success
+func BackupDatabase(src, dest string) error {
+	if src == "" || dest == "" {
+		return fmt.Errorf("source and destination paths are required")
+	}
+
+	src = NormalizePath(src)
+	dest = NormalizePath(dest)
+	if src == dest {
+		return fmt.Errorf("destination must differ from source")
+	}
+
+	if _, err := os.Stat(src); err != nil {
+		return fmt.Errorf("source database not found: %v", err)
+	}
+
+	fmt.Printf("[Database] Backing up %s -> %s\n", src, dest)
+
+	if err := runSQLiteBackup(src, dest, false); err != nil {
+		os.Remove(dest)
+		return err
+	}
+
+	if err := TestDatabaseConnection(dest); err != nil {
+		os.Remove(dest)
+		return fmt.Errorf("backup verification failed: %v", err)
+	}
+
+	fmt.Println("[Database] ✓ Backup completed and verified")
+	return nil
+}
+
+// RestoreDatabase replaces the contents of dest with a verified backup file
+func RestoreDatabase(backupPath, dest string) error {
+	if backupPath == "" || dest == "" {
+		return fmt.Errorf("backup and destination paths are required")
+	}
+
+	backupPath = NormalizePath(backupPath)
+	dest = NormalizePath(dest)
+	if backupPath == dest {
+		return fmt.Errorf("destination must differ from backup")
+	}
+
+	// Never overwrite a working database with a broken backup
+	if err := TestDatabaseConnection(backupPath); err != nil {
+		return fmt.Errorf("backup is not a valid database: %v", err)
+	}
+
+	fmt.Printf("[Database] Restoring %s -> %s\n", backupPath, dest)
+
+	if err := runSQLiteBackup(dest, backupPath, true); err != nil {
+		return err
+	}
+
+	if err := TestDatabaseConnection(dest); err != nil {
+		return fmt.Errorf("restore verification failed: %v", err)
+	}
+
+	fmt.Println("[Database] ✓ Restore completed and verified")
+	return nil
+}
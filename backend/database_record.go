@@ -0,0 +1,192 @@
+package backend
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TrackMeta is the metadata of a finished download that gets written to the local database
+type TrackMeta struct {
+	SpotifyID   string `json:"spotify_id"`
+	Name        string `json:"name"`
+	Artists     string `json:"artists"`
+	ISRC        string `json:"isrc,omitempty"`
+	DurationMs  int    `json:"duration_ms,omitempty"`
+	TrackNumber int    `json:"track_number,omitempty"`
+	DiscNumber  int    `json:"disc_number,omitempty"`
+	AlbumID     string `json:"album_id,omitempty"`
+	AlbumName   string `json:"album_name,omitempty"`
+	AlbumArtist string `json:"album_artist,omitempty"`
+	ReleaseDate string `json:"release_date,omitempty"`
+	CoverURL    string `json:"cover_url,omitempty"`
+	CoverWidth  int    `json:"cover_width,omitempty"`
+	CoverHeight int    `json:"cover_height,omitempty"`
+}
+
+// databaseSchema matches the columns the lookups in database.go read, so a DB built from
+// downloads works the same as an imported one
+var databaseSchema = []string{
+	`CREATE TABLE IF NOT EXISTS albums (
+		id TEXT,
+		name TEXT,
+		artists TEXT,
+		release_date TEXT
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_albums_id ON albums(id)`,
+	`CREATE INDEX IF NOT EXISTS idx_albums_name ON albums(name)`,
+	`CREATE TABLE IF NOT EXISTS tracks (
+		id TEXT PRIMARY KEY,
+		name TEXT,
+		artists TEXT,
+		external_id_isrc TEXT,
+		album_rowid INTEGER,
+		duration_ms INTEGER,
+		track_number INTEGER,
+		disc_number INTEGER
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_tracks_isrc ON tracks(external_id_isrc)`,
+	`CREATE TABLE IF NOT EXISTS album_images (
+		album_rowid INTEGER,
+		url TEXT,
+		width INTEGER,
+		height INTEGER
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_album_images_album ON album_images(album_rowid)`,
+}
+
+// RecordToDatabase inserts or updates a downloaded track, its album and the album cover in the
+// local database, creating the schema when the file is new, so later lookups work offline
+func RecordToDatabase(databasePath string, meta TrackMeta) error {
+	if databasePath == "" {
+		return fmt.Errorf("no database path provided")
+	}
+	if meta.SpotifyID == "" {
+		return fmt.Errorf("spotify ID is required")
+	}
+
+	databasePath = NormalizePath(databasePath)
+	if dir := filepath.Dir(databasePath); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create database directory: %v", err)
+		}
+	}
+
+	db, err := sql.Open("sqlite", databasePath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		return fmt.Errorf("failed to connect to database: %v", err)
+	}
+
+	for _, stmt := range databaseSchema {
+		if _, err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("failed to create schema: %v", err)
+		}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var albumRowID sql.NullInt64
+	if meta.AlbumName != "" || meta.AlbumID != "" {
+		rowID, err := upsertAlbum(tx, meta)
+		if err != nil {
+			return err
+		}
+		albumRowID = sql.NullInt64{Int64: rowID, Valid: true}
+
+		if meta.CoverURL != "" {
+			if err := insertAlbumImage(tx, rowID, meta); err != nil {
+				return err
+			}
+		}
+	}
+
+	isrc := strings.ToUpper(strings.TrimSpace(meta.ISRC))
+	result, err := tx.Exec(`
+		UPDATE tracks
+		SET name = ?, artists = ?,
+			external_id_isrc = COALESCE(NULLIF(?, ''), external_id_isrc),
+			album_rowid = COALESCE(?, album_rowid)
+		WHERE id = ?
+	`, meta.Name, meta.Artists, isrc, albumRowID, meta.SpotifyID)
+	if err != nil {
+		return fmt.Errorf("failed to update track: %v", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		_, err = tx.Exec(`
+			INSERT INTO tracks (id, name, artists, external_id_isrc, album_rowid, duration_ms, track_number, disc_number)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`, meta.SpotifyID, meta.Name, meta.Artists, isrc, albumRowID, meta.DurationMs, meta.TrackNumber, meta.DiscNumber)
+		if err != nil {
+			return fmt.Errorf("failed to insert track: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit: %v", err)
+	}
+
+	fmt.Printf("[Database] Recorded %s - %s (%s)\n", meta.Name, meta.Artists, meta.SpotifyID)
+	return nil
+}
+
+// upsertAlbum returns the rowid of the album, matched by Spotify ID and then by name, inserting it if new
+func upsertAlbum(tx *sql.Tx, meta TrackMeta) (int64, error) {
+	var rowID int64
+	var err error
+	if meta.AlbumID != "" {
+		err = tx.QueryRow("SELECT rowid FROM albums WHERE id = ? LIMIT 1", meta.AlbumID).Scan(&rowID)
+	} else {
+		err = tx.QueryRow("SELECT rowid FROM albums WHERE name = ? LIMIT 1", meta.AlbumName).Scan(&rowID)
+	}
+	if err == nil {
+		return rowID, nil
+	}
+	if err != sql.ErrNoRows {
+		return 0, fmt.Errorf("failed to query album: %v", err)
+	}
+
+	artists := meta.AlbumArtist
+	if artists == "" {
+		artists = meta.Artists
+	}
+	result, err := tx.Exec("INSERT INTO albums (id, name, artists, release_date) VALUES (?, ?, ?, ?)",
+		meta.AlbumID, meta.AlbumName, artists, meta.ReleaseDate)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert album: %v", err)
+	}
+	return result.LastInsertId()
+}
+
+// insertAlbumImage adds the cover URL for an album unless it is already recorded
+func insertAlbumImage(tx *sql.Tx, albumRowID int64, meta TrackMeta) error {
+	var exists int
+	err := tx.QueryRow("SELECT 1 FROM album_images WHERE album_rowid = ? AND url = ? LIMIT 1", albumRowID, meta.CoverURL).Scan(&exists)
+	if err == nil {
+		return nil
+	}
+	if err != sql.ErrNoRows {
+		return fmt.Errorf("failed to query album image: %v", err)
+	}
+
+	// Spotify cover URLs without known dimensions are the 640px size
+	width, height := meta.CoverWidth, meta.CoverHeight
+	if width == 0 {
+		width, height = 640, 640
+	}
+	if _, err := tx.Exec("INSERT INTO album_images (album_rowid, url, width, height) VALUES (?, ?, ?, ?)",
+		albumRowID, meta.CoverURL, width, height); err != nil {
+		return fmt.Errorf("failed to insert album image: %v", err)
+	}
+	return nil
+}
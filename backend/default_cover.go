@@ -0,0 +1,187 @@
+package backend
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+var (
+	defaultCoverPath string
+	defaultCoverLock sync.RWMutex
+
+	missingCoverTracks []string
+	missingCoverLock   sync.Mutex
+
+	// ISRCs whose cover is embedded after the download, so the placeholder mustn't be used meanwhile
+	deferredCoverISRCs = make(map[string]int)
+	deferredCoverLock  sync.Mutex
+)
+
+// SetDefaultCover sets the placeholder image embedded when no album art can be found.
+// An empty path disables the placeholder.
+func SetDefaultCover(path string) error {
+	if path == "" {
+		defaultCoverLock.Lock()
+		defaultCoverPath = ""
+		defaultCoverLock.Unlock()
+		fmt.Println("[Default Cover] Placeholder cover disabled")
+		return nil
+	}
+
+	path = NormalizePath(path)
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to access default cover: %v", err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("default cover path is a directory: %s", path)
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".jpg" && ext != ".jpeg" && ext != ".png" {
+		return fmt.Errorf("unsupported default cover format: %s", ext)
+	}
+
+	defaultCoverLock.Lock()
+	defaultCoverPath = path
+	defaultCoverLock.Unlock()
+
+	fmt.Printf("[Default Cover] Placeholder cover set: %s\n", path)
+	return nil
+}
+
+// GetDefaultCover returns the configured placeholder image path, or empty if none is set
+func GetDefaultCover() string {
+	defaultCoverLock.RLock()
+	defer defaultCoverLock.RUnlock()
+	return defaultCoverPath
+}
+
+// DeferCover marks a track whose cover is embedded by post-processing, so the download doesn't
+// embed the placeholder in its place. Call the returned func once the download is done.
+func DeferCover(isrc string) func() {
+	key := strings.ToUpper(strings.TrimSpace(isrc))
+	if key == "" {
+		return func() {}
+	}
+
+	deferredCoverLock.Lock()
+	deferredCoverISRCs[key]++
+	deferredCoverLock.Unlock()
+
+	return func() {
+		deferredCoverLock.Lock()
+		if deferredCoverISRCs[key]--; deferredCoverISRCs[key] <= 0 {
+			delete(deferredCoverISRCs, key)
+		}
+		deferredCoverLock.Unlock()
+	}
+}
+
+func isCoverDeferred(isrc string) bool {
+	deferredCoverLock.Lock()
+	defer deferredCoverLock.Unlock()
+	return deferredCoverISRCs[strings.ToUpper(strings.TrimSpace(isrc))] > 0
+}
+
+// resolveEmbedCover returns the cover to embed: the given one, or the placeholder when there is
+// none and the real cover isn't coming later from post-processing
+func resolveEmbedCover(filePath, isrc, coverPath string) string {
+	if coverPath != "" && fileExists(coverPath) {
+		return coverPath
+	}
+	if isCoverDeferred(isrc) {
+		return ""
+	}
+	if defaultCover := GetDefaultCover(); defaultCover != "" && fileExists(defaultCover) {
+		fmt.Printf("No album art found, using default cover for: %s\n", filePath)
+		recordMissingCoverTrack(filePath)
+		return defaultCover
+	}
+	return ""
+}
+
+// recordMissingCoverTrack flags a track that received the placeholder instead of real album art
+func recordMissingCoverTrack(path string) {
+	missingCoverLock.Lock()
+	defer missingCoverLock.Unlock()
+
+	for _, p := range missingCoverTracks {
+		if p == path {
+			return
+		}
+	}
+	missingCoverTracks = append(missingCoverTracks, path)
+}
+
+// isMissingCoverTrack reports whether a track was given the placeholder cover
+func isMissingCoverTrack(path string) bool {
+	missingCoverLock.Lock()
+	defer missingCoverLock.Unlock()
+
+	for _, p := range missingCoverTracks {
+		if p == path {
+			return true
+		}
+	}
+	return false
+}
+
+// GetMissingCoverTracks returns the tracks that were given the placeholder cover
+func GetMissingCoverTracks() []string {
+	missingCoverLock.Lock()
+	defer missingCoverLock.Unlock()
+
+	tracks := make([]string, len(missingCoverTracks))
+	copy(tracks, missingCoverTracks)
+	return tracks
+}
+
+// clearMissingCoverTrack unflags a track once real album art has been embedded
+func clearMissingCoverTrack(path string) {
+	missingCoverLock.Lock()
+	defer missingCoverLock.Unlock()
+
+	for i, p := range missingCoverTracks {
+		if p == path {
+			missingCoverTracks = append(missingCoverTracks[:i], missingCoverTracks[i+1:]...)
+			return
+		}
+	}
+}
+
+// ClearMissingCoverTracks resets the list of tracks flagged with the placeholder cover
+func ClearMissingCoverTracks() {
+	missingCoverLock.Lock()
+	defer missingCoverLock.Unlock()
+	missingCoverTracks = nil
+}
+
+// copyDefaultCoverTo copies the placeholder image next to an audio file as its sidecar cover
+func copyDefaultCoverTo(destPath string) error {
+	src := GetDefaultCover()
+	if src == "" {
+		return fmt.Errorf("no default cover configured")
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open default cover: %v", err)
+	}
+	defer in.Close()
+
+	out, err := os.Create(destPath)
+	if err != nil {
+		return fmt.Errorf("failed to create cover file: %v", err)
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, in); err != nil {
+		return fmt.Errorf("failed to copy default cover: %v", err)
+	}
+	return nil
+}
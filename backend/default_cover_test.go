@@ -0,0 +1,77 @@
+package backend
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setTestDefaultCover configures a placeholder cover for one test and resets the cover state after it
+func setTestDefaultCover(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	coverPath := filepath.Join(dir, "default.jpg")
+	if err := os.WriteFile(coverPath, []byte("placeholder"), 0644); err != nil {
+		t.Fatalf("failed to write default cover: %v", err)
+	}
+	if err := SetDefaultCover(coverPath); err != nil {
+		t.Fatalf("SetDefaultCover: %v", err)
+	}
+	t.Cleanup(func() {
+		SetDefaultCover("")
+		ClearMissingCoverTracks()
+	})
+	return NormalizePath(coverPath)
+}
+
+func TestResolveEmbedCoverDeferredSkipsDefaultCover(t *testing.T) {
+	defaultCover := setTestDefaultCover(t)
+	track := filepath.Join(t.TempDir(), "track.flac")
+
+	release := DeferCover("usabc1234567")
+	if got := resolveEmbedCover(track, "USABC1234567", ""); got != "" {
+		t.Errorf("deferred cover: got %q, want no cover", got)
+	}
+	if isMissingCoverTrack(track) {
+		t.Errorf("deferred cover: track flagged as using the placeholder")
+	}
+	release()
+
+	if got := resolveEmbedCover(track, "USABC1234567", ""); got != defaultCover {
+		t.Errorf("after release: got %q, want %q", got, defaultCover)
+	}
+	if !isMissingCoverTrack(track) {
+		t.Errorf("after release: track not flagged as using the placeholder")
+	}
+}
+
+func TestResolveEmbedCoverPrefersRealCover(t *testing.T) {
+	setTestDefaultCover(t)
+	dir := t.TempDir()
+	cover := filepath.Join(dir, "cover.jpg")
+	if err := os.WriteFile(cover, []byte("cover"), 0644); err != nil {
+		t.Fatalf("failed to write cover: %v", err)
+	}
+	track := filepath.Join(dir, "track.flac")
+
+	if got := resolveEmbedCover(track, "USABC1234567", cover); got != cover {
+		t.Errorf("got %q, want %q", got, cover)
+	}
+	if isMissingCoverTrack(track) {
+		t.Errorf("track with real cover flagged as using the placeholder")
+	}
+}
+
+func TestSkipExistingEnrichmentKeepsPlaceholderCoverStep(t *testing.T) {
+	setTestDefaultCover(t)
+	track := filepath.Join(t.TempDir(), "track.flac")
+	recordMissingCoverTrack(track)
+
+	embedCover, _, skipped := SkipExistingEnrichment(track, true, false)
+	if !embedCover {
+		t.Errorf("cover step skipped for a track holding the placeholder")
+	}
+	if len(skipped) != 0 {
+		t.Errorf("skipped = %v, want none", skipped)
+	}
+}
@@ -0,0 +1,143 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/bogem/id3v2"
+	"github.com/go-flac/flacvorbis"
+	"github.com/go-flac/go-flac"
+)
+
+// discTagValue formats a disc position as "n/m", the form TPOS and the M4A disk atom expect
+func discTagValue(discNumber, totalDiscs int) string {
+	if discNumber <= 0 {
+		discNumber = 1
+	}
+	return fmt.Sprintf("%d/%d", discNumber, totalDiscs)
+}
+
+// EmbedDiscTotal writes the album's disc count next to the disc number: DISCTOTAL for FLAC,
+// TPOS "n/m" for MP3 and the disk atom for M4A. A zero totalDiscs leaves the file untouched.
+func EmbedDiscTotal(filePath string, discNumber, totalDiscs int) error {
+	if totalDiscs <= 0 {
+		return nil
+	}
+
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".flac":
+		fields := map[string]string{"DISCTOTAL": strconv.Itoa(totalDiscs)}
+		if discNumber > 0 {
+			fields["DISCNUMBER"] = strconv.Itoa(discNumber)
+		}
+		return setVorbisFields(filePath, fields)
+	case ".mp3":
+		return setMP3DiscTag(filePath, discTagValue(discNumber, totalDiscs))
+	case ".m4a":
+		return setM4ADiscTag(filePath, discTagValue(discNumber, totalDiscs))
+	default:
+		return fmt.Errorf("unsupported file format for disc tags: %s", filepath.Ext(filePath))
+	}
+}
+
+func setMP3DiscTag(filePath, value string) error {
+	restore, err := prepareWritable(filePath)
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	tag, err := id3v2.Open(filePath, id3v2.Options{Parse: true})
+	if err != nil {
+		return fmt.Errorf("failed to open MP3 file: %w", err)
+	}
+	defer tag.Close()
+
+	tag.DeleteFrames("TPOS")
+	tag.AddTextFrame("TPOS", id3v2.EncodingUTF8, value)
+	if err := tag.Save(); err != nil {
+		return fmt.Errorf("failed to save MP3 tags: %w", err)
+	}
+	return nil
+}
+
+func setM4ADiscTag(filePath, value string) error {
+	ffmpegPath, err := GetFFmpegPath()
+	if err != nil {
+		return fmt.Errorf("ffmpeg not found: %w", err)
+	}
+	if err := ValidateExecutable(ffmpegPath); err != nil {
+		return fmt.Errorf("invalid ffmpeg executable: %w", err)
+	}
+
+	restore, err := prepareWritable(filePath)
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	tmpOutputFile := strings.TrimSuffix(filePath, filepath.Ext(filePath)) + ".tmp" + filepath.Ext(filePath)
+	defer func() {
+		if _, err := os.Stat(tmpOutputFile); err == nil {
+			os.Remove(tmpOutputFile)
+		}
+	}()
+
+	cmd := exec.Command(ffmpegPath,
+		"-i", filePath,
+		"-map", "0",
+		"-map_metadata", "0",
+		"-metadata", "disc="+value,
+		"-codec", "copy",
+		"-f", "ipod",
+		"-y",
+		tmpOutputFile,
+	)
+	setHideWindow(cmd)
+
+	output, err := ffmpegCombinedOutput(cmd)
+	if err != nil {
+		return fmt.Errorf("ffmpeg failed to write disc tag: %s - %w", string(output), err)
+	}
+	if err := os.Rename(tmpOutputFile, filePath); err != nil {
+		return fmt.Errorf("failed to replace original file: %w", err)
+	}
+	return nil
+}
+
+// readFlacDiscTags returns DISCNUMBER and DISCTOTAL (or TOTALDISCS) from a FLAC file, zero when absent
+func readFlacDiscTags(filePath string) (int, int) {
+	f, err := flac.ParseFile(filePath)
+	if err != nil {
+		return 0, 0
+	}
+
+	for _, block := range f.Meta {
+		if block.Type != flac.VorbisComment {
+			continue
+		}
+		cmt, err := flacvorbis.ParseFromMetaDataBlock(*block)
+		if err != nil {
+			continue
+		}
+
+		first := func(names ...string) int {
+			for _, name := range names {
+				if values, err := cmt.Get(name); err == nil && len(values) > 0 {
+					// DISCNUMBER is sometimes already written as "n/m"
+					value := strings.SplitN(values[0], "/", 2)[0]
+					if n, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+						return n
+					}
+				}
+			}
+			return 0
+		}
+		return first("DISCNUMBER"), first("DISCTOTAL", "TOTALDISCS")
+	}
+	return 0, 0
+}
@@ -0,0 +1,232 @@
+package backend
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// HistoryEntry is one completed download in the global history
+type HistoryEntry struct {
+	ID           int64  `json:"id"`
+	ISRC         string `json:"isrc"`
+	SpotifyID    string `json:"spotify_id,omitempty"`
+	TrackName    string `json:"track_name,omitempty"`
+	ArtistName   string `json:"artist_name,omitempty"`
+	Service      string `json:"service,omitempty"`
+	FilePath     string `json:"file_path"`
+	Quality      string `json:"quality,omitempty"`
+	DownloadedAt string `json:"downloaded_at"` // RFC 3339
+}
+
+var historySchema = []string{
+	`CREATE TABLE IF NOT EXISTS download_history (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		isrc TEXT,
+		spotify_id TEXT,
+		track_name TEXT,
+		artist_name TEXT,
+		service TEXT,
+		file_path TEXT,
+		quality TEXT,
+		downloaded_at TEXT
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_history_isrc ON download_history(isrc)`,
+	`CREATE INDEX IF NOT EXISTS idx_history_path ON download_history(file_path)`,
+}
+
+// GetDownloadHistoryPath returns the history database, kept next to the app's other state files
+// so it covers every output folder
+func GetDownloadHistoryPath() (string, error) {
+	dir, err := GetFFmpegDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "history.db"), nil
+}
+
+// openDownloadHistory opens the history database, creating it and its schema when missing
+func openDownloadHistory() (*sql.DB, error) {
+	historyPath, err := GetDownloadHistoryPath()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(historyPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create history directory: %v", err)
+	}
+
+	db, err := sql.Open("sqlite", historyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history database: %v", err)
+	}
+	for _, stmt := range historySchema {
+		if _, err := db.Exec(stmt); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to create history schema: %v", err)
+		}
+	}
+	return db, nil
+}
+
+// historyQuality describes the audio quality of a downloaded file, e.g. "24-bit/96kHz" or "MP3"
+func historyQuality(filePath string) string {
+	if strings.EqualFold(filepath.Ext(filePath), ".flac") {
+		if quality, _, err := flacQualityLabel(filePath); err == nil {
+			return quality
+		}
+	}
+	return strings.ToUpper(strings.TrimPrefix(filepath.Ext(filePath), "."))
+}
+
+// RecordDownloadHistory adds a completed download to the global history. Quality is read from
+// the file and the timestamp set to now when the entry leaves them empty.
+func RecordDownloadHistory(entry HistoryEntry) error {
+	entry.ISRC = strings.ToUpper(strings.TrimSpace(entry.ISRC))
+	if entry.ISRC == "" && entry.SpotifyID == "" {
+		return fmt.Errorf("ISRC or Spotify ID is required")
+	}
+	if entry.Quality == "" && entry.FilePath != "" {
+		entry.Quality = historyQuality(entry.FilePath)
+	}
+	if entry.DownloadedAt == "" {
+		entry.DownloadedAt = time.Now().Format(time.RFC3339)
+	}
+
+	db, err := openDownloadHistory()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`
+		INSERT INTO download_history (isrc, spotify_id, track_name, artist_name, service, file_path, quality, downloaded_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, entry.ISRC, entry.SpotifyID, entry.TrackName, entry.ArtistName, entry.Service, entry.FilePath, entry.Quality, entry.DownloadedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record download history: %v", err)
+	}
+
+	fmt.Printf("[History] Recorded %s (%s) -> %s\n", entry.TrackName, entry.ISRC, entry.FilePath)
+	return nil
+}
+
+// QueryDownloadHistory returns every recorded download of an ISRC, newest first
+func QueryDownloadHistory(isrc string) ([]HistoryEntry, error) {
+	isrc = strings.ToUpper(strings.TrimSpace(isrc))
+	if isrc == "" {
+		return nil, fmt.Errorf("ISRC is required")
+	}
+	return queryDownloadHistory("WHERE isrc = ? ORDER BY downloaded_at DESC, id DESC", isrc)
+}
+
+// queryDownloadHistory reads history rows matching the given clause
+func queryDownloadHistory(clause string, args ...interface{}) ([]HistoryEntry, error) {
+	db, err := openDownloadHistory()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`
+		SELECT id, isrc, spotify_id, track_name, artist_name, service, file_path, quality, downloaded_at
+		FROM download_history `+clause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query download history: %v", err)
+	}
+	defer rows.Close()
+
+	entries := []HistoryEntry{}
+	for rows.Next() {
+		var entry HistoryEntry
+		var isrc, spotifyID, trackName, artistName, service, filePath, quality, downloadedAt sql.NullString
+		if err := rows.Scan(&entry.ID, &isrc, &spotifyID, &trackName, &artistName, &service, &filePath, &quality, &downloadedAt); err != nil {
+			return nil, fmt.Errorf("failed to read download history: %v", err)
+		}
+		entry.ISRC = isrc.String
+		entry.SpotifyID = spotifyID.String
+		entry.TrackName = trackName.String
+		entry.ArtistName = artistName.String
+		entry.Service = service.String
+		entry.FilePath = filePath.String
+		entry.Quality = quality.String
+		entry.DownloadedAt = downloadedAt.String
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// updateDownloadHistoryPath points history entries at a file's new location after it was moved
+func updateDownloadHistoryPath(oldPath, newPath string) {
+	db, err := openDownloadHistory()
+	if err != nil {
+		fmt.Printf("[History] Failed to open history: %v\n", err)
+		return
+	}
+	defer db.Close()
+
+	if _, err := db.Exec("UPDATE download_history SET file_path = ? WHERE file_path = ?", newPath, oldPath); err != nil {
+		fmt.Printf("[History] Failed to update path for %s: %v\n", filepath.Base(oldPath), err)
+	}
+}
+
+// historyExportHeader lists the columns of an exported history CSV
+var historyExportHeader = []string{
+	"Downloaded At", "ISRC", "Spotify ID", "Track Name", "Artist Name(s)", "Service", "Quality", "File Path",
+}
+
+// ExportDownloadHistoryCSV writes the whole history, oldest first, to filePath.
+// Returns the number of rows written.
+func ExportDownloadHistoryCSV(filePath string) (int, error) {
+	entries, err := queryDownloadHistory("ORDER BY downloaded_at ASC, id ASC")
+	if err != nil {
+		return 0, err
+	}
+	if len(entries) == 0 {
+		return 0, fmt.Errorf("download history is empty")
+	}
+
+	filePath = NormalizePath(filePath)
+	if dir := filepath.Dir(filePath); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return 0, fmt.Errorf("failed to create directory: %v", err)
+		}
+	}
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create CSV file: %v", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write(historyExportHeader); err != nil {
+		return 0, fmt.Errorf("failed to write CSV header: %v", err)
+	}
+	for _, entry := range entries {
+		record := []string{
+			entry.DownloadedAt,
+			entry.ISRC,
+			entry.SpotifyID,
+			entry.TrackName,
+			entry.ArtistName,
+			entry.Service,
+			entry.Quality,
+			entry.FilePath,
+		}
+		if err := writer.Write(record); err != nil {
+			return 0, fmt.Errorf("failed to write CSV row: %v", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return 0, fmt.Errorf("failed to write CSV file: %v", err)
+	}
+
+	fmt.Printf("[History] Exported %d entries to %s\n", len(entries), filePath)
+	return len(entries), nil
+}
@@ -0,0 +1,7 @@
+package backend
+
+// DownloadResult is what a service downloader returns for a finished track
+type DownloadResult struct {
+	Path           string // Audio file on disk
+	AlreadyExisted bool   // Path was already there (same ISRC or filename), so nothing was downloaded
+}
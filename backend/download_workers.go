@@ -0,0 +1,273 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	maxConcurrentDownloadsLimit = 5
+	defaultServiceConcurrency   = 2
+)
+
+// serviceConcurrency caps parallel downloads per service so workers stay inside each API's rate limits.
+// Amazon's downloader paces its own calls and gains nothing from running alongside itself.
+var serviceConcurrency = map[string]int{
+	"tidal":  3,
+	"qobuz":  2,
+	"amazon": 1,
+}
+
+// downloadJob is a queued item the worker pool can run
+type downloadJob struct {
+	service string
+	run     func()
+}
+
+var (
+	maxConcurrentDownloads     = 1
+	maxConcurrentDownloadsLock sync.RWMutex
+
+	downloadJobs     = make(map[string]downloadJob)
+	serviceActive    = make(map[string]int)
+	downloadWorkers  int
+	stopWorkers      bool
+	downloadJobsLock sync.Mutex
+	downloadJobsCond = sync.NewCond(&downloadJobsLock)
+
+	isrcDownloadLocks     = make(map[string]*isrcDownloadLock)
+	isrcDownloadLocksLock sync.Mutex
+
+	itemDownloadPaths     = make(map[string]string)
+	itemDownloadPathsLock sync.RWMutex
+)
+
+// SetMaxConcurrentDownloads sets how many queue items the worker pool downloads at once (1-5)
+func SetMaxConcurrentDownloads(n int) {
+	if n < 1 {
+		n = 1
+	}
+	if n > maxConcurrentDownloadsLimit {
+		n = maxConcurrentDownloadsLimit
+	}
+
+	maxConcurrentDownloadsLock.Lock()
+	maxConcurrentDownloads = n
+	maxConcurrentDownloadsLock.Unlock()
+
+	// Wake idle workers so any above a lowered limit exit
+	downloadJobsLock.Lock()
+	downloadJobsCond.Broadcast()
+	downloadJobsLock.Unlock()
+}
+
+func getMaxConcurrentDownloads() int {
+	maxConcurrentDownloadsLock.RLock()
+	defer maxConcurrentDownloadsLock.RUnlock()
+	return maxConcurrentDownloads
+}
+
+// EnqueueDownloadJob hands a queued item (added with AddToQueue) to the worker pool. run performs
+// the whole download and is called on a worker goroutine.
+func EnqueueDownloadJob(itemID, service string, run func()) {
+	downloadJobsLock.Lock()
+	defer downloadJobsLock.Unlock()
+
+	downloadJobs[itemID] = downloadJob{service: strings.ToLower(service), run: run}
+	downloadJobsCond.Broadcast()
+}
+
+// StartDownloadWorkers starts workers until n are running (n <= 0 uses the configured maximum)
+// and returns the number running. Workers take queued items in queue order, skipping items whose
+// service is at its concurrency cap, and wait when there is nothing to do.
+func StartDownloadWorkers(n int) int {
+	if n <= 0 {
+		n = getMaxConcurrentDownloads()
+	}
+	if n > maxConcurrentDownloadsLimit {
+		n = maxConcurrentDownloadsLimit
+	}
+
+	downloadJobsLock.Lock()
+	defer downloadJobsLock.Unlock()
+
+	stopWorkers = false
+	for downloadWorkers < n {
+		downloadWorkers++
+		go runDownloadWorker()
+	}
+	fmt.Printf("[Workers] %d download workers running\n", downloadWorkers)
+	return downloadWorkers
+}
+
+// StopDownloadWorkers makes idle workers exit now and busy ones exit after their current item.
+// Jobs still queued are kept and run once the workers are started again.
+func StopDownloadWorkers() {
+	downloadJobsLock.Lock()
+	defer downloadJobsLock.Unlock()
+
+	stopWorkers = true
+	downloadJobsCond.Broadcast()
+}
+
+// stopDownloadWorkersIfIdle drops the jobs of items that are no longer queued and stops the
+// workers unless jobs remain, so a batch queued while another was being cancelled keeps running
+func stopDownloadWorkersIfIdle() {
+	downloadJobsLock.Lock()
+	defer downloadJobsLock.Unlock()
+
+	if len(queuedDownloadJobs()) == 0 {
+		stopWorkers = true
+		downloadJobsCond.Broadcast()
+	}
+}
+
+func runDownloadWorker() {
+	downloadJobsLock.Lock()
+	defer downloadJobsLock.Unlock()
+
+	for {
+		var job downloadJob
+		for {
+			if stopWorkers || downloadWorkers > getMaxConcurrentDownloads() {
+				downloadWorkers--
+				return
+			}
+			var ok bool
+			if job, ok = claimDownloadJob(); ok {
+				break
+			}
+			downloadJobsCond.Wait()
+		}
+
+		downloadJobsLock.Unlock()
+		job.run()
+		downloadJobsLock.Lock()
+
+		serviceActive[job.service]--
+		downloadJobsCond.Broadcast()
+	}
+}
+
+// queuedDownloadJobs returns the IDs of items with a job in queue order, dropping the jobs of
+// items that were cancelled or cleared. The caller holds downloadJobsLock.
+func queuedDownloadJobs() []string {
+	if len(downloadJobs) == 0 {
+		return nil
+	}
+
+	queued := make(map[string]bool)
+	order := make([]string, 0, len(downloadJobs))
+	downloadQueueLock.RLock()
+	for _, item := range downloadQueue {
+		if _, ok := downloadJobs[item.ID]; ok && item.Status == StatusQueued {
+			queued[item.ID] = true
+			order = append(order, item.ID)
+		}
+	}
+	downloadQueueLock.RUnlock()
+
+	for id := range downloadJobs {
+		if !queued[id] {
+			delete(downloadJobs, id)
+		}
+	}
+	return order
+}
+
+// claimDownloadJob picks the first queued item with a job whose service has a free slot and
+// marks it downloading. The caller holds downloadJobsLock.
+func claimDownloadJob() (downloadJob, bool) {
+	for _, id := range queuedDownloadJobs() {
+		job := downloadJobs[id]
+		limit, ok := serviceConcurrency[job.service]
+		if !ok {
+			limit = defaultServiceConcurrency
+		}
+		if serviceActive[job.service] >= limit {
+			continue
+		}
+
+		delete(downloadJobs, id)
+		serviceActive[job.service]++
+		StartDownloadItem(id)
+		return job, true
+	}
+	return downloadJob{}, false
+}
+
+// isrcDownloadLock serializes workers downloading the same ISRC into the same folder
+type isrcDownloadLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// LockISRCDownload holds off other workers downloading the same ISRC into outputDir until the
+// returned release func is called, so the second one finds the first one's file through
+// CheckISRCExists instead of downloading it twice
+func LockISRCDownload(outputDir, isrc string) func() {
+	if isrc == "" {
+		return func() {}
+	}
+	key := filepath.Clean(outputDir) + "|" + strings.ToUpper(isrc)
+
+	isrcDownloadLocksLock.Lock()
+	lock, ok := isrcDownloadLocks[key]
+	if !ok {
+		lock = &isrcDownloadLock{}
+		isrcDownloadLocks[key] = lock
+	}
+	lock.refs++
+	isrcDownloadLocksLock.Unlock()
+
+	lock.mu.Lock()
+	return func() {
+		lock.mu.Unlock()
+
+		isrcDownloadLocksLock.Lock()
+		if lock.refs--; lock.refs == 0 {
+			delete(isrcDownloadLocks, key)
+		}
+		isrcDownloadLocksLock.Unlock()
+	}
+}
+
+// SetItemDownloadPath records the file an item is expected to download to, so progress writers
+// can attribute bytes to the right item when several download at once. An empty path forgets it.
+func SetItemDownloadPath(itemID, path string) {
+	itemDownloadPathsLock.Lock()
+	defer itemDownloadPathsLock.Unlock()
+
+	if path == "" {
+		delete(itemDownloadPaths, itemID)
+		return
+	}
+	itemDownloadPaths[itemID] = strings.TrimSuffix(filepath.Clean(path), filepath.Ext(path))
+}
+
+// progressItemID finds the queue item a progress writer belongs to: by the file it writes to,
+// or the current item when only one download is running
+func progressItemID(writer interface{}) string {
+	if f, ok := writer.(*os.File); ok {
+		name := filepath.Clean(f.Name())
+		itemDownloadPathsLock.RLock()
+		for id, base := range itemDownloadPaths {
+			if name == base || strings.HasPrefix(name, base+".") {
+				itemDownloadPathsLock.RUnlock()
+				return id
+			}
+		}
+		itemDownloadPathsLock.RUnlock()
+	}
+
+	downloadingLock.RLock()
+	single := activeDownloads == 1
+	downloadingLock.RUnlock()
+	if single {
+		return GetCurrentItemID()
+	}
+	return ""
+}
@@ -0,0 +1,301 @@
+package backend
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-flac/go-flac"
+)
+
+const maxDuplicateHashWorkers = 32
+
+// DuplicateGroup is a set of files whose audio hashes match
+type DuplicateGroup struct {
+	Hash  string   `json:"hash"`
+	Files []string `json:"files"`
+}
+
+// DuplicateScanProgress is emitted for every file hashed during a duplicate scan
+type DuplicateScanProgress struct {
+	File    string `json:"file"`
+	Current int    `json:"current"`
+	Total   int    `json:"total"`
+	Cached  bool   `json:"cached,omitempty"` // Hash came from the cache, the file wasn't read
+	Error   string `json:"error,omitempty"`
+}
+
+// audioHashEntry is a cached hash, valid while the file keeps the same size and modification time
+type audioHashEntry struct {
+	size    int64
+	modTime int64
+	hash    string
+}
+
+var (
+	duplicateHashWorkers     int // 0 = number of cores, capped at 8
+	duplicateHashWorkersLock sync.RWMutex
+
+	audioHashCache     = make(map[string]audioHashEntry)
+	audioHashCacheLock sync.Mutex
+)
+
+// SetDuplicateHashWorkers sets how many files are hashed at once during a duplicate scan (0 = automatic)
+func SetDuplicateHashWorkers(workers int) {
+	if workers < 0 {
+		workers = 0
+	}
+	if workers > maxDuplicateHashWorkers {
+		workers = maxDuplicateHashWorkers
+	}
+
+	duplicateHashWorkersLock.Lock()
+	duplicateHashWorkers = workers
+	duplicateHashWorkersLock.Unlock()
+}
+
+func getDuplicateHashWorkers() int {
+	duplicateHashWorkersLock.RLock()
+	workers := duplicateHashWorkers
+	duplicateHashWorkersLock.RUnlock()
+
+	if workers > 0 {
+		return workers
+	}
+	workers = runtime.NumCPU()
+	if workers > 8 {
+		workers = 8
+	}
+	return workers
+}
+
+// ComputeAudioHash returns a hash of a file's audio only, so copies with different tags or
+// cover art still match. FLAC uses the decoded-audio MD5 from STREAMINFO when the encoder set it.
+func ComputeAudioHash(filePath string) (string, error) {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".flac":
+		return flacAudioHash(filePath)
+	case ".mp3":
+		return mp3AudioHash(filePath)
+	default:
+		return fileHash(filePath)
+	}
+}
+
+func flacAudioHash(filePath string) (string, error) {
+	f, err := flac.ParseFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse FLAC: %w", err)
+	}
+
+	if len(f.Meta) > 0 && f.Meta[0].Type == flac.StreamInfo && len(f.Meta[0].Data) >= 34 {
+		sum := f.Meta[0].Data[18:34]
+		if !bytes.Equal(sum, make([]byte, 16)) {
+			return "md5:" + hex.EncodeToString(sum), nil
+		}
+	}
+
+	hash := sha1.Sum(f.Frames)
+	return "sha1:" + hex.EncodeToString(hash[:]), nil
+}
+
+// mp3AudioHash hashes an MP3 without its leading ID3v2 tag and trailing ID3v1 tag
+func mp3AudioHash(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+	start, end := int64(0), info.Size()
+
+	header := make([]byte, 10)
+	if _, err := io.ReadFull(file, header); err == nil && string(header[:3]) == "ID3" {
+		// Tag size is a 28-bit syncsafe integer; a footer adds another 10 bytes
+		size := int64(header[6]&0x7f)<<21 | int64(header[7]&0x7f)<<14 | int64(header[8]&0x7f)<<7 | int64(header[9]&0x7f)
+		start = 10 + size
+		if header[5]&0x10 != 0 {
+			start += 10
+		}
+	}
+
+	if end-start > 128 {
+		trailer := make([]byte, 3)
+		if _, err := file.ReadAt(trailer, end-128); err == nil && string(trailer) == "TAG" {
+			end -= 128
+		}
+	}
+	if start >= end {
+		return "", fmt.Errorf("no audio data after tags")
+	}
+
+	hash := sha1.New()
+	if _, err := io.Copy(hash, io.NewSectionReader(file, start, end-start)); err != nil {
+		return "", err
+	}
+	return "sha1:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func fileHash(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha1.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return "sha1:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// cachedAudioHash returns the hash for filePath, reusing the cached one when the file's size and
+// modification time haven't changed since it was computed
+func cachedAudioHash(filePath string, info os.FileInfo) (string, bool, error) {
+	size, modTime := info.Size(), info.ModTime().UnixNano()
+
+	audioHashCacheLock.Lock()
+	entry, ok := audioHashCache[filePath]
+	audioHashCacheLock.Unlock()
+	if ok && entry.size == size && entry.modTime == modTime {
+		return entry.hash, true, nil
+	}
+
+	hash, err := ComputeAudioHash(filePath)
+	if err != nil {
+		return "", false, err
+	}
+
+	audioHashCacheLock.Lock()
+	audioHashCache[filePath] = audioHashEntry{size: size, modTime: modTime, hash: hash}
+	audioHashCacheLock.Unlock()
+	return hash, false, nil
+}
+
+// ClearAudioHashCache drops all cached audio hashes
+func ClearAudioHashCache() {
+	audioHashCacheLock.Lock()
+	audioHashCache = make(map[string]audioHashEntry)
+	audioHashCacheLock.Unlock()
+}
+
+// FindDuplicates hashes every audio file under dirPath with a worker pool and groups files whose
+// audio is identical. onProgress is called as each file is hashed. The scan stops early when ctx
+// is cancelled.
+func FindDuplicates(ctx context.Context, dirPath string, onProgress func(DuplicateScanProgress)) ([]DuplicateGroup, error) {
+	dirPath = NormalizePath(dirPath)
+	fmt.Printf("\n[Duplicates] Scanning for duplicate audio in: %s\n", dirPath)
+
+	type candidate struct {
+		path string
+		info os.FileInfo
+	}
+	files := make([]candidate, 0)
+	err := filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if info.IsDir() {
+			return nil
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		if ext == ".flac" || ext == ".mp3" || ext == ".m4a" {
+			files = append(files, candidate{path: path, info: info})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan directory: %w", err)
+	}
+
+	total := len(files)
+	workers := getDuplicateHashWorkers()
+	fmt.Printf("[Duplicates] Hashing %d audio files with %d workers\n", total, workers)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	processed := int32(0)
+	cachedCount := int32(0)
+	byHash := make(map[string][]string)
+
+	fileChan := make(chan candidate)
+	go func() {
+		defer close(fileChan)
+		for _, f := range files {
+			select {
+			case <-ctx.Done():
+				return
+			case fileChan <- f:
+			}
+		}
+	}()
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for f := range fileChan {
+				if ctx.Err() != nil {
+					return
+				}
+
+				progress := DuplicateScanProgress{File: f.path, Total: total}
+				hash, cached, err := cachedAudioHash(f.path, f.info)
+				if err != nil {
+					progress.Error = err.Error()
+				} else if cached {
+					progress.Cached = true
+					atomic.AddInt32(&cachedCount, 1)
+				}
+				progress.Current = int(atomic.AddInt32(&processed, 1))
+
+				mu.Lock()
+				if err == nil {
+					byHash[hash] = append(byHash[hash], f.path)
+				}
+				if onProgress != nil {
+					onProgress(progress)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		fmt.Printf("[Duplicates] Scan cancelled after %d/%d files\n", processed, total)
+		return nil, ctx.Err()
+	}
+
+	groups := make([]DuplicateGroup, 0)
+	for hash, paths := range byHash {
+		if len(paths) < 2 {
+			continue
+		}
+		sort.Strings(paths)
+		groups = append(groups, DuplicateGroup{Hash: hash, Files: paths})
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Files[0] < groups[j].Files[0] })
+
+	fmt.Printf("[Duplicates] Scan complete: %d files (%d from cache), %d duplicate groups\n", processed, cachedCount, len(groups))
+	return groups, nil
+}
@@ -0,0 +1,62 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Enrichment steps that can be skipped because the file already carries the data
+const (
+	EnrichSkipCover  = "cover"
+	EnrichSkipLyrics = "lyrics"
+)
+
+// HasValidEmbeddedCover reports whether the file already holds a decodable cover picture.
+// M4A art can only be detected, not decoded, so its presence counts as valid. The placeholder
+// cover doesn't count, so real art still replaces it.
+func HasValidEmbeddedCover(filePath string) bool {
+	if isMissingCoverTrack(filePath) {
+		return false
+	}
+
+	if strings.ToLower(filepath.Ext(filePath)) == ".m4a" {
+		hasCover, err := M4AHasCover(filePath)
+		return err == nil && hasCover
+	}
+
+	coverPath, err := ExtractCoverArt(filePath)
+	if err != nil || coverPath == "" {
+		return false
+	}
+	defer os.Remove(coverPath)
+
+	width, height, _, err := CheckCoverSquare(coverPath)
+	return err == nil && width > 0 && height > 0
+}
+
+// HasEmbeddedLyrics reports whether the file already holds non-empty lyrics
+func HasEmbeddedLyrics(filePath string) bool {
+	lyrics, err := ExtractLyrics(filePath)
+	return err == nil && strings.TrimSpace(lyrics) != ""
+}
+
+// SkipExistingEnrichment decides which of the requested post-download steps are redundant
+// because the downloaded file already has valid art or lyrics, so that art or lyrics from the
+// source isn't replaced and no fetch is made. Returns the steps still to run and the skipped ones.
+func SkipExistingEnrichment(filePath string, embedCover, embedLyrics bool) (bool, bool, []string) {
+	var skipped []string
+	if embedCover && HasValidEmbeddedCover(filePath) {
+		embedCover = false
+		skipped = append(skipped, EnrichSkipCover)
+	}
+	if embedLyrics && HasEmbeddedLyrics(filePath) {
+		embedLyrics = false
+		skipped = append(skipped, EnrichSkipLyrics)
+	}
+	if len(skipped) > 0 {
+		fmt.Printf("[Post-Process] Already embedded, skipping %s: %s\n", strings.Join(skipped, " and "), filepath.Base(filePath))
+	}
+	return embedCover, embedLyrics, skipped
+}
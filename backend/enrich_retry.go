@@ -0,0 +1,90 @@
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultEnrichRetries = 3
+	enrichRetryBaseDelay = 2 * time.Second
+)
+
+var (
+	enrichRetries     = defaultEnrichRetries
+	enrichRetriesLock sync.RWMutex
+
+	enrichWarnings     = make(map[string][]string)
+	enrichWarningsLock sync.Mutex
+)
+
+// SetEnrichRetries sets how many times a failed cover/lyrics fetch or embed is attempted in total.
+// Values below 1 mean a single attempt.
+func SetEnrichRetries(attempts int) {
+	if attempts < 1 {
+		attempts = 1
+	}
+	enrichRetriesLock.Lock()
+	enrichRetries = attempts
+	enrichRetriesLock.Unlock()
+}
+
+func getEnrichRetries() int {
+	enrichRetriesLock.RLock()
+	defer enrichRetriesLock.RUnlock()
+	return enrichRetries
+}
+
+// isPermanentEnrichError reports failures that retrying cannot fix
+func isPermanentEnrichError(err error) bool {
+	if errors.Is(err, ErrFileReadOnly) {
+		return true
+	}
+	return classifyDownloadError(err) == "not_found"
+}
+
+// retryEnrichStep runs step up to the configured number of attempts, doubling the delay
+// between attempts, and gives up early on permanent failures
+func retryEnrichStep(label string, step func() error) error {
+	attempts := getEnrichRetries()
+	delay := enrichRetryBaseDelay
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = step(); err == nil {
+			return nil
+		}
+		if isPermanentEnrichError(err) || attempt == attempts {
+			break
+		}
+		fmt.Printf("[Post-Process] %s failed (attempt %d/%d): %v; retrying in %s\n", label, attempt, attempts, err, delay)
+		time.Sleep(delay)
+		delay *= 2
+	}
+	return err
+}
+
+// recordEnrichWarning keeps a final enrichment failure for a file so it can be reported, and adds
+// it to the job's queue item. Enrichment runs after the download has returned, so the queue is
+// where the frontend sees it.
+func recordEnrichWarning(job PostProcessJob, warning string) {
+	enrichWarningsLock.Lock()
+	enrichWarnings[job.FilePath] = append(enrichWarnings[job.FilePath], warning)
+	enrichWarningsLock.Unlock()
+
+	if job.ItemID != "" {
+		addItemWarning(job.ItemID, warning)
+	}
+}
+
+// TakeAllEnrichWarnings returns and clears every recorded enrichment failure, keyed by file
+func TakeAllEnrichWarnings() map[string][]string {
+	enrichWarningsLock.Lock()
+	defer enrichWarningsLock.Unlock()
+
+	all := enrichWarnings
+	enrichWarnings = make(map[string][]string)
+	return all
+}
@@ -0,0 +1,140 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const episodeBaseURL = "https://api.spotify.com/v1/episodes/%s?market=US"
+
+// EpisodeMeta is what Spotify reports about a podcast episode. Episodes can't be downloaded,
+// so this is kept apart from the track metadata and download flows.
+type EpisodeMeta struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Show        string `json:"show"`
+	Publisher   string `json:"publisher,omitempty"`
+	Description string `json:"description"`
+	DurationMs  int    `json:"duration_ms"`
+	ReleaseDate string `json:"release_date"`
+	Language    string `json:"language,omitempty"`
+	CoverURL    string `json:"cover_url,omitempty"`
+	SpotifyURL  string `json:"spotify_url,omitempty"`
+}
+
+type episodeResponse struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	DurationMs  int    `json:"duration_ms"`
+	ReleaseDate string `json:"release_date"`
+	Language    string `json:"language"`
+	Images      []struct {
+		URL string `json:"url"`
+	} `json:"images"`
+	ExternalURLs struct {
+		Spotify string `json:"spotify"`
+	} `json:"external_urls"`
+	Show struct {
+		Name      string `json:"name"`
+		Publisher string `json:"publisher"`
+	} `json:"show"`
+}
+
+// episodeIDFromInput accepts a bare episode ID, a spotify:episode: URI or an open.spotify.com episode link
+func episodeIDFromInput(input string) (string, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return "", fmt.Errorf("episode ID is required")
+	}
+	if !strings.Contains(input, ":") && !strings.Contains(input, "/") {
+		return input, nil
+	}
+
+	_, err := parseSpotifyURI(input)
+	var unsupported *UnsupportedSpotifyTypeError
+	if errors.As(err, &unsupported) && unsupported.Type == "episode" {
+		return unsupported.ID, nil
+	}
+	return "", fmt.Errorf("not a Spotify episode link: %s", input)
+}
+
+// GetEpisodeMetadata fetches an episode's show, title, description, duration and publish date
+func GetEpisodeMetadata(ctx context.Context, episode string) (*EpisodeMeta, error) {
+	episodeID, err := episodeIDFromInput(episode)
+	if err != nil {
+		return nil, err
+	}
+
+	client := NewSpotifyMetadataClient()
+	token, err := client.getAccessToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access token: %w", err)
+	}
+
+	var data episodeResponse
+	if err := client.getJSON(ctx, fmt.Sprintf(episodeBaseURL, episodeID), token, &data); err != nil {
+		return nil, fmt.Errorf("failed to fetch episode: %w", err)
+	}
+
+	meta := &EpisodeMeta{
+		ID:          data.ID,
+		Title:       data.Name,
+		Show:        data.Show.Name,
+		Publisher:   data.Show.Publisher,
+		Description: data.Description,
+		DurationMs:  data.DurationMs,
+		ReleaseDate: data.ReleaseDate,
+		Language:    data.Language,
+		SpotifyURL:  data.ExternalURLs.Spotify,
+	}
+	if len(data.Images) > 0 {
+		meta.CoverURL = data.Images[0].URL
+	}
+
+	fmt.Printf("[Episode] %s - %s (%s)\n", meta.Show, meta.Title, meta.ReleaseDate)
+	return meta, nil
+}
+
+// SaveEpisodeDescription writes the episode's metadata and description to
+// "<show> - <title>.txt" in outputDir and returns the file path
+func SaveEpisodeDescription(meta *EpisodeMeta, outputDir string) (string, error) {
+	if meta == nil {
+		return "", fmt.Errorf("no episode metadata")
+	}
+	outputDir = NormalizePath(outputDir)
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Show: %s\n", meta.Show))
+	if meta.Publisher != "" {
+		sb.WriteString(fmt.Sprintf("Publisher: %s\n", meta.Publisher))
+	}
+	sb.WriteString(fmt.Sprintf("Episode: %s\n", meta.Title))
+	sb.WriteString(fmt.Sprintf("Published: %s\n", meta.ReleaseDate))
+	if meta.DurationMs > 0 {
+		totalSeconds := meta.DurationMs / 1000
+		sb.WriteString(fmt.Sprintf("Duration: %d:%02d:%02d\n", totalSeconds/3600, (totalSeconds%3600)/60, totalSeconds%60))
+	}
+	if meta.SpotifyURL != "" {
+		sb.WriteString(fmt.Sprintf("URL: %s\n", meta.SpotifyURL))
+	}
+	sb.WriteString("\n")
+	sb.WriteString(meta.Description)
+	sb.WriteString("\n")
+
+	filename := sanitizeFilename(fmt.Sprintf("%s - %s", meta.Show, meta.Title)) + ".txt"
+	path := filepath.Join(outputDir, filename)
+	if err := os.WriteFile(path, []byte(sb.String()), 0644); err != nil {
+		return "", fmt.Errorf("failed to write episode description: %w", err)
+	}
+
+	fmt.Printf("[Episode] Saved description: %s\n", path)
+	return path, nil
+}
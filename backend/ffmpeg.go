@@ -85,8 +85,58 @@ func GetFFmpegDir() (string, error) {
 	return filepath.Join(homeDir, ".spotiflac"), nil
 }
 
+var (
+	ffmpegOverridePath string
+	ffmpegOverrideLock sync.RWMutex
+)
+
+// SetFFmpegPath overrides the bundled ffmpeg with a user-supplied binary.
+// The binary is validated and test-run before it is accepted. An empty path restores the bundled ffmpeg.
+func SetFFmpegPath(path string) error {
+	if path == "" {
+		ffmpegOverrideLock.Lock()
+		ffmpegOverridePath = ""
+		ffmpegOverrideLock.Unlock()
+		fmt.Println("[FFmpeg] Using bundled ffmpeg")
+		return nil
+	}
+
+	path = filepath.Clean(NormalizePath(path))
+	if err := ValidateExecutable(path); err != nil {
+		return fmt.Errorf("invalid ffmpeg path: %w", err)
+	}
+
+	cmd := exec.Command(path, "-version")
+	setHideWindow(cmd)
+	output, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("ffmpeg failed to run: %w", err)
+	}
+	if !strings.Contains(string(output), "ffmpeg version") {
+		return fmt.Errorf("binary does not appear to be ffmpeg: %s", path)
+	}
+
+	ffmpegOverrideLock.Lock()
+	ffmpegOverridePath = path
+	ffmpegOverrideLock.Unlock()
+
+	fmt.Printf("[FFmpeg] Using custom ffmpeg: %s\n", path)
+	return nil
+}
+
+// getFFmpegOverride returns the user-supplied ffmpeg path, or empty if none is set
+func getFFmpegOverride() string {
+	ffmpegOverrideLock.RLock()
+	defer ffmpegOverrideLock.RUnlock()
+	return ffmpegOverridePath
+}
+
 // GetFFmpegPath returns the full path to the ffmpeg executable
 func GetFFmpegPath() (string, error) {
+	if override := getFFmpegOverride(); override != "" {
+		return override, nil
+	}
+
 	ffmpegDir, err := GetFFmpegDir()
 	if err != nil {
 		return "", err
@@ -112,6 +162,14 @@ func GetFFprobePath() (string, error) {
 		ffprobeName = "ffprobe.exe"
 	}
 
+	// Prefer the ffprobe shipped alongside a custom ffmpeg
+	if override := getFFmpegOverride(); override != "" {
+		siblingPath := filepath.Join(filepath.Dir(override), ffprobeName)
+		if _, err := os.Stat(siblingPath); err == nil {
+			return siblingPath, nil
+		}
+	}
+
 	ffprobePath := filepath.Join(ffmpegDir, ffprobeName)
 	if _, err := os.Stat(ffprobePath); err == nil {
 		return ffprobePath, nil
@@ -585,6 +643,13 @@ func ConvertAudio(req ConvertAudioRequest) ([]ConvertAudioResult, error) {
 				args = append(args, "-map", "0:v?", "-c:v", "copy", "-disposition:v:0", "attached_pic")
 			}
 
+			// DISCTOTAL isn't carried over by -map_metadata, so write it as "n/m" for TPOS / disk
+			if inputExt == ".flac" {
+				if discNumber, totalDiscs := readFlacDiscTags(inputFile); totalDiscs > 0 {
+					args = append(args, "-metadata", "disc="+discTagValue(discNumber, totalDiscs))
+				}
+			}
+
 			args = append(args, outputFile)
 
 			fmt.Printf("[FFmpeg] Converting: %s -> %s\n", inputFile, outputFile)
@@ -592,7 +657,7 @@ func ConvertAudio(req ConvertAudioRequest) ([]ConvertAudioResult, error) {
 			cmd := exec.Command(ffmpegPath, args...)
 			// Hide console window on Windows
 			setHideWindow(cmd)
-			output, err := cmd.CombinedOutput()
+			output, err := ffmpegCombinedOutput(cmd)
 			if err != nil {
 				result.Error = fmt.Sprintf("conversion failed: %s - %s", err.Error(), string(output))
 				result.Success = false
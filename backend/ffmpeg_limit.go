@@ -0,0 +1,78 @@
+package backend
+
+import (
+	"os/exec"
+	"runtime"
+	"sync"
+)
+
+// ffmpegSlots caps how many ffmpeg/ffprobe processes run at once across analysis, integrity
+// checks, tagging and conversion, so several features on a big library can't exhaust CPU or file handles
+var (
+	ffmpegSlotLimit  int // 0 = number of cores
+	ffmpegSlotsInUse int
+	ffmpegSlotLock   sync.Mutex
+	ffmpegSlotFree   = sync.NewCond(&ffmpegSlotLock)
+)
+
+// SetMaxFFmpegProcesses sets how many ffmpeg/ffprobe processes may run at once (0 = number of cores)
+func SetMaxFFmpegProcesses(limit int) {
+	if limit < 0 {
+		limit = 0
+	}
+
+	ffmpegSlotLock.Lock()
+	ffmpegSlotLimit = limit
+	ffmpegSlotLock.Unlock()
+
+	// A raised limit lets waiting callers start right away
+	ffmpegSlotFree.Broadcast()
+}
+
+func effectiveFFmpegLimit() int {
+	if ffmpegSlotLimit > 0 {
+		return ffmpegSlotLimit
+	}
+	return runtime.NumCPU()
+}
+
+// acquireFFmpegSlot blocks until a process slot is free and returns the function that frees it
+func acquireFFmpegSlot() func() {
+	ffmpegSlotLock.Lock()
+	for ffmpegSlotsInUse >= effectiveFFmpegLimit() {
+		ffmpegSlotFree.Wait()
+	}
+	ffmpegSlotsInUse++
+	ffmpegSlotLock.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			ffmpegSlotLock.Lock()
+			ffmpegSlotsInUse--
+			ffmpegSlotLock.Unlock()
+			ffmpegSlotFree.Signal()
+		})
+	}
+}
+
+// runFFmpeg is cmd.Run limited by the shared process slots
+func runFFmpeg(cmd *exec.Cmd) error {
+	release := acquireFFmpegSlot()
+	defer release()
+	return cmd.Run()
+}
+
+// ffmpegOutput is cmd.Output limited by the shared process slots
+func ffmpegOutput(cmd *exec.Cmd) ([]byte, error) {
+	release := acquireFFmpegSlot()
+	defer release()
+	return cmd.Output()
+}
+
+// ffmpegCombinedOutput is cmd.CombinedOutput limited by the shared process slots
+func ffmpegCombinedOutput(cmd *exec.Cmd) ([]byte, error) {
+	release := acquireFFmpegSlot()
+	defer release()
+	return cmd.CombinedOutput()
+}
@@ -56,8 +56,8 @@ func SelectCSVFileDialog(ctx context.Context) (string, error) {
 		Title: "Select CSV Playlist File",
 		Filters: []runtime.FileFilter{
 			{
-				DisplayName: "CSV Files (*.csv)",
-				Pattern:     "*.csv",
+				DisplayName: "Playlist Files (*.csv, *.txt, *.m3u, *.m3u8)",
+				Pattern:     "*.csv;*.txt;*.m3u;*.m3u8",
 			},
 			{
 				DisplayName: "All Files (*.*)",
@@ -77,8 +77,8 @@ func SelectMultipleCSVFiles(ctx context.Context) ([]string, error) {
 		Title: "Select CSV Playlist Files",
 		Filters: []runtime.FileFilter{
 			{
-				DisplayName: "CSV Files (*.csv)",
-				Pattern:     "*.csv",
+				DisplayName: "Playlist Files (*.csv, *.txt, *.m3u, *.m3u8)",
+				Pattern:     "*.csv;*.txt;*.m3u;*.m3u8",
 			},
 			{
 				DisplayName: "All Files (*.*)",
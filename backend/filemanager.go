@@ -32,6 +32,8 @@ type AudioMetadata struct {
 	TrackNumber int    `json:"track_number"`
 	DiscNumber  int    `json:"disc_number"`
 	Year        string `json:"year"`
+	ISRC        string `json:"isrc,omitempty"`
+	SpotifyID   string `json:"spotify_id,omitempty"`
 }
 
 // RenamePreview represents a preview of file rename operation
@@ -169,7 +171,12 @@ func readFlacMetadata(filePath string) (*AudioMetadata, error) {
 				case "TITLE":
 					metadata.Title = value
 				case "ARTIST":
-					metadata.Artist = value
+					// Repeated ARTIST comments are merged back into one joined value
+					if metadata.Artist != "" {
+						metadata.Artist = joinArtistValues([]string{metadata.Artist, value})
+					} else {
+						metadata.Artist = value
+					}
 				case "ALBUM":
 					metadata.Album = value
 				case "ALBUMARTIST":
@@ -184,6 +191,10 @@ func readFlacMetadata(filePath string) (*AudioMetadata, error) {
 					}
 				case "DATE", "YEAR":
 					metadata.Year = value
+				case "ISRC":
+					metadata.ISRC = value
+				case "SPOTIFY_ID":
+					metadata.SpotifyID = value
 				}
 			}
 		}
@@ -234,6 +245,21 @@ func readMp3Metadata(filePath string) (*AudioMetadata, error) {
 		}
 	}
 
+	// Get ISRC (TSRC)
+	if frames := tag.GetFrames("TSRC"); len(frames) > 0 {
+		if textFrame, ok := frames[0].(id3v2.TextFrame); ok {
+			metadata.ISRC = textFrame.Text
+		}
+	}
+
+	// Get Spotify ID (TXXX:SPOTIFY_ID, as ffmpeg writes custom tags)
+	for _, frame := range tag.GetFrames("TXXX") {
+		if udtf, ok := frame.(id3v2.UserDefinedTextFrame); ok && strings.EqualFold(udtf.Description, "SPOTIFY_ID") {
+			metadata.SpotifyID = udtf.Value
+			break
+		}
+	}
+
 	return metadata, nil
 }
 
@@ -260,7 +286,7 @@ func readMetadataWithFFprobe(filePath string) (*AudioMetadata, error) {
 	// Hide console window on Windows
 	setHideWindow(cmd)
 
-	output, err := cmd.Output()
+	output, err := ffmpegOutput(cmd)
 	if err != nil {
 		return nil, err
 	}
@@ -322,6 +348,10 @@ func readMetadataWithFFprobe(filePath string) (*AudioMetadata, error) {
 			if metadata.Year == "" || len(value) > len(metadata.Year) {
 				metadata.Year = value
 			}
+		case "isrc", "tsrc":
+			metadata.ISRC = value
+		case "spotify_id":
+			metadata.SpotifyID = value
 		}
 	}
 
@@ -5,10 +5,40 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 	"unicode"
 	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Track sources decide which number an item is filed under
+const (
+	TrackSourcePlaylist = "playlist"
+	TrackSourceAlbum    = "album"
 )
 
+// ResolveUseAlbumTrackNumber picks the numbering for one item: album downloads use the album
+// track number, playlist downloads use their position, and anything else keeps the global setting
+func ResolveUseAlbumTrackNumber(source string, fallback bool) bool {
+	switch source {
+	case TrackSourceAlbum:
+		return true
+	case TrackSourcePlaylist:
+		return false
+	}
+	return fallback
+}
+
+// FilenameTrackPosition returns the number that goes in front of a filename, matching the
+// choice the downloaders make so existence checks look for the same name
+func FilenameTrackPosition(position, albumTrackNumber int, useAlbumTrackNumber bool) int {
+	if useAlbumTrackNumber && albumTrackNumber > 0 {
+		return albumTrackNumber
+	}
+	return position
+}
+
 // BuildExpectedFilename builds the expected filename based on track metadata and settings
 func BuildExpectedFilename(trackName, artistName, albumName, albumArtist, releaseDate, filenameFormat string, includeTrackNumber bool, position, discNumber int, useAlbumTrackNumber bool) string {
 	// Sanitize track name and artist name
@@ -70,58 +100,86 @@ func BuildExpectedFilename(trackName, artistName, albumName, albumArtist, releas
 	return filename + ".flac"
 }
 
-// sanitizeFilename removes invalid characters from filename
+// Filename charsets decide what survives sanitizing besides the filesystem-illegal characters
+const (
+	FilenameCharsetUnicode = "unicode" // Keep accents, CJK and every other printable character
+	FilenameCharsetASCII   = "ascii"   // Strip accents and drop anything outside ASCII
+)
+
+var (
+	filenameCharset     = FilenameCharsetUnicode
+	filenameCharsetLock sync.RWMutex
+
+	illegalFilenameChars = regexp.MustCompile(`[<>:"\\|?*]`)
+	repeatedWhitespace   = regexp.MustCompile(`\s+`)
+	repeatedUnderscores  = regexp.MustCompile(`_+`)
+)
+
+// SetFilenameCharset sets how file and folder names are sanitized; empty means unicode
+func SetFilenameCharset(mode string) error {
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	if mode == "" {
+		mode = FilenameCharsetUnicode
+	}
+	if mode != FilenameCharsetUnicode && mode != FilenameCharsetASCII {
+		return fmt.Errorf("unknown filename charset: %s", mode)
+	}
+
+	filenameCharsetLock.Lock()
+	filenameCharset = mode
+	filenameCharsetLock.Unlock()
+	return nil
+}
+
+func getFilenameCharset() string {
+	filenameCharsetLock.RLock()
+	defer filenameCharsetLock.RUnlock()
+	return filenameCharset
+}
+
+// sanitizeFilename removes invalid characters from filename. Only characters the filesystem
+// rejects and control characters are removed unless the ASCII charset is selected.
 func sanitizeFilename(name string) string {
+	return sanitizeFilenameWithCharset(name, getFilenameCharset())
+}
+
+func sanitizeFilenameWithCharset(name, charset string) string {
+	// Fix invalid UTF-8 first so every later step works on whole runes
+	sanitized := strings.ToValidUTF8(name, "_")
+
 	// Replace forward slash with space (more natural than underscore)
-	sanitized := strings.ReplaceAll(name, "/", " ")
+	sanitized = strings.ReplaceAll(sanitized, "/", " ")
 
 	// Remove other invalid filesystem characters (replace with space)
-	re := regexp.MustCompile(`[<>:"\\|?*]`)
-	sanitized = re.ReplaceAllString(sanitized, " ")
+	sanitized = illegalFilenameChars.ReplaceAllString(sanitized, " ")
+
+	if charset == FilenameCharsetASCII {
+		sanitized = toASCIIFilename(sanitized)
+	}
 
-	// Remove control characters (0x00-0x1F, 0x7F)
+	// Remove control characters (C0, DEL and C1); tabs and newlines become spaces below
 	var result strings.Builder
 	for _, r := range sanitized {
-		// Keep printable characters and valid Unicode characters
-		// Remove control characters, but keep spaces, tabs, newlines for now
-		if r < 0x20 && r != 0x09 && r != 0x0A && r != 0x0D {
-			continue
-		}
-		if r == 0x7F {
-			continue
-		}
-		// Remove emoji and other symbols that might cause issues
-		// Keep letters, numbers, and common punctuation
-		if unicode.IsControl(r) && r != 0x09 && r != 0x0A && r != 0x0D {
+		if r == '\t' || r == '\n' || r == '\r' {
+			result.WriteRune(' ')
 			continue
 		}
-		// Remove emoji ranges (most emoji are in these ranges)
-		if (r >= 0x1F300 && r <= 0x1F9FF) || // Miscellaneous Symbols and Pictographs, Emoticons
-			(r >= 0x2600 && r <= 0x26FF) || // Miscellaneous Symbols
-			(r >= 0x2700 && r <= 0x27BF) || // Dingbats
-			(r >= 0xFE00 && r <= 0xFE0F) || // Variation Selectors
-			(r >= 0x1F900 && r <= 0x1F9FF) || // Supplemental Symbols and Pictographs
-			(r >= 0x1F600 && r <= 0x1F64F) || // Emoticons
-			(r >= 0x1F680 && r <= 0x1F6FF) || // Transport and Map Symbols
-			(r >= 0x1F1E0 && r <= 0x1F1FF) { // Regional Indicator Symbols (flags)
+		if unicode.IsControl(r) {
 			continue
 		}
 		result.WriteRune(r)
 	}
 
-	sanitized = result.String()
-	sanitized = strings.TrimSpace(sanitized)
+	sanitized = strings.TrimSpace(result.String())
 
 	// Remove leading/trailing dots and spaces (Windows doesn't allow these)
 	sanitized = strings.Trim(sanitized, ". ")
 
 	// Normalize consecutive spaces to single space
-	re = regexp.MustCompile(`\s+`)
-	sanitized = re.ReplaceAllString(sanitized, " ")
+	sanitized = repeatedWhitespace.ReplaceAllString(sanitized, " ")
 
 	// Normalize consecutive underscores to single underscore
-	re = regexp.MustCompile(`_+`)
-	sanitized = re.ReplaceAllString(sanitized, "_")
+	sanitized = repeatedUnderscores.ReplaceAllString(sanitized, "_")
 
 	// Remove leading/trailing underscores and spaces
 	sanitized = strings.Trim(sanitized, "_ ")
@@ -130,15 +188,24 @@ func sanitizeFilename(name string) string {
 		return "Unknown"
 	}
 
-	// Ensure the result is valid UTF-8
-	if !utf8.ValidString(sanitized) {
-		// If invalid UTF-8, try to fix it
-		sanitized = strings.ToValidUTF8(sanitized, "_")
-	}
-
 	return sanitized
 }
 
+// toASCIIFilename strips accents ("Beyoncé" -> "Beyonce"), romanizes kana and drops whatever
+// is still outside printable ASCII
+func toASCIIFilename(name string) string {
+	var result strings.Builder
+	for _, r := range norm.NFD.String(JapaneseToRomaji(name)) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if r < utf8.RuneSelf {
+			result.WriteRune(r)
+		}
+	}
+	return result.String()
+}
+
 // NormalizePath only normalizes path separators without modifying folder names
 // Use this for user-provided paths that already exist on the filesystem
 func NormalizePath(folderPath string) string {
@@ -0,0 +1,95 @@
+package backend
+
+import "testing"
+
+func TestSanitizeFilenameWithCharset(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		charset string
+		want    string
+	}{
+		// Unicode is the default and keeps everything the filesystem accepts
+		{name: "CJK title", input: "夜に駆ける", charset: FilenameCharsetUnicode, want: "夜に駆ける"},
+		{name: "Korean artist", input: "아이유", charset: FilenameCharsetUnicode, want: "아이유"},
+		{name: "Chinese with illegal chars", input: "周杰倫: 晴天?", charset: FilenameCharsetUnicode, want: "周杰倫 晴天"},
+		{name: "precomposed accents", input: "Beyoncé – Café Déjà Vu", charset: FilenameCharsetUnicode, want: "Beyoncé – Café Déjà Vu"},
+		{name: "combining accents kept", input: "Beyonce\u0301", charset: FilenameCharsetUnicode, want: "Beyonce\u0301"},
+		{name: "emoji kept", input: "Love 💖 Song", charset: FilenameCharsetUnicode, want: "Love 💖 Song"},
+
+		// ASCII strips accents, precomposed or combining, and drops what can't be spelled
+		{name: "ascii precomposed accents", input: "Beyoncé – Café", charset: FilenameCharsetASCII, want: "Beyonce Cafe"},
+		{name: "ascii combining accents", input: "Beyonce\u0301 Ro\u0308yksopp", charset: FilenameCharsetASCII, want: "Beyonce Royksopp"},
+		{name: "ascii Hangul only", input: "아이유", charset: FilenameCharsetASCII, want: "Unknown"},
+		{name: "ascii mixed Latin and Hanzi", input: "Jay Chou 周杰倫", charset: FilenameCharsetASCII, want: "Jay Chou"},
+
+		// Edge cases of the sanitizing itself
+		{name: "slash becomes space", input: "AC/DC", charset: FilenameCharsetUnicode, want: "AC DC"},
+		{name: "all illegal characters", input: `a<b>c:d"e\f|g?h*i`, charset: FilenameCharsetUnicode, want: "a b c d e f g h i"},
+		{name: "control characters removed", input: "Track\x00\x07\x1f\u0085One", charset: FilenameCharsetUnicode, want: "TrackOne"},
+		{name: "tabs and newlines become spaces", input: "Line\tOne\r\nTwo", charset: FilenameCharsetUnicode, want: "Line One Two"},
+		{name: "leading and trailing dots trimmed", input: "...Ready For It?..", charset: FilenameCharsetUnicode, want: "Ready For It"},
+		{name: "repeated whitespace collapsed", input: "  Many    spaces  ", charset: FilenameCharsetUnicode, want: "Many spaces"},
+		{name: "repeated underscores collapsed and trimmed", input: "__a___b__", charset: FilenameCharsetUnicode, want: "a_b"},
+		{name: "invalid UTF-8 replaced", input: "bad\xff\xfebytes", charset: FilenameCharsetUnicode, want: "bad_bytes"},
+		{name: "only illegal characters", input: `<>:"?*`, charset: FilenameCharsetUnicode, want: "Unknown"},
+		{name: "empty", input: "", charset: FilenameCharsetUnicode, want: "Unknown"},
+		{name: "only dots", input: "...", charset: FilenameCharsetUnicode, want: "Unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizeFilenameWithCharset(tt.input, tt.charset); got != tt.want {
+				t.Errorf("sanitizeFilenameWithCharset(%q, %q) = %q, want %q", tt.input, tt.charset, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetFilenameCharset(t *testing.T) {
+	t.Cleanup(func() { SetFilenameCharset(FilenameCharsetUnicode) })
+
+	tests := []struct {
+		mode    string
+		want    string
+		wantErr bool
+	}{
+		{mode: "", want: FilenameCharsetUnicode},
+		{mode: " ASCII ", want: FilenameCharsetASCII},
+		{mode: "unicode", want: FilenameCharsetUnicode},
+		{mode: "latin1", want: FilenameCharsetUnicode, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		err := SetFilenameCharset(tt.mode)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("SetFilenameCharset(%q) error = %v, wantErr %v", tt.mode, err, tt.wantErr)
+		}
+		if got := getFilenameCharset(); got != tt.want {
+			t.Errorf("after SetFilenameCharset(%q): charset = %q, want %q", tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestBuildExpectedFilenameKeepsUnicode(t *testing.T) {
+	tests := []struct {
+		name   string
+		title  string
+		artist string
+		format string
+		want   string
+	}{
+		{name: "CJK template", title: "夜に駆ける", artist: "YOASOBI", format: "{track}. {title} - {artist}", want: "01. 夜に駆ける - YOASOBI.flac"},
+		{name: "accented legacy", title: "Déjà Vu", artist: "Beyoncé", format: "artist-title", want: "01. Beyoncé - Déjà Vu.flac"},
+		{name: "illegal characters in both", title: "What?", artist: "AC/DC", format: "title-artist", want: "01. What - AC DC.flac"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BuildExpectedFilename(tt.title, tt.artist, "", "", "", tt.format, true, 1, 0, false)
+			if got != tt.want {
+				t.Errorf("BuildExpectedFilename() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// finalMoveSidecars are the files next to a track that travel with it to the import folder
+var finalMoveSidecars = []string{".lrc", ".txt", ".jpg", ".png"}
+
+var (
+	finalMoveDir     string
+	finalMoveDirLock sync.RWMutex
+
+	finalLocations     = make(map[string]string)
+	finalLocationsLock sync.Mutex
+)
+
+// SetFinalMoveDir sets the folder finished tracks are moved into once every post-processing step
+// and the integrity check are done; empty leaves files where they were downloaded
+func SetFinalMoveDir(dir string) {
+	finalMoveDirLock.Lock()
+	finalMoveDir = strings.TrimSpace(dir)
+	finalMoveDirLock.Unlock()
+}
+
+// GetFinalMoveDir returns the configured import folder, or empty when disabled
+func GetFinalMoveDir() string {
+	finalMoveDirLock.RLock()
+	defer finalMoveDirLock.RUnlock()
+	return finalMoveDir
+}
+
+// GetFinalLocations returns where moved tracks ended up, keyed by their download path
+func GetFinalLocations() map[string]string {
+	finalLocationsLock.Lock()
+	defer finalLocationsLock.Unlock()
+
+	locations := make(map[string]string, len(finalLocations))
+	for from, to := range finalLocations {
+		locations[from] = to
+	}
+	return locations
+}
+
+// ClearFinalLocations forgets the recorded moves
+func ClearFinalLocations() {
+	finalLocationsLock.Lock()
+	finalLocations = make(map[string]string)
+	finalLocationsLock.Unlock()
+}
+
+// FinalMoveTarget returns where a track downloaded below baseDir lands in moveDir
+func FinalMoveTarget(filePath, baseDir, moveDir string) string {
+	rel := filepath.Base(filePath)
+	if baseDir != "" {
+		if r, err := filepath.Rel(baseDir, filePath); err == nil && !strings.HasPrefix(r, "..") {
+			rel = r
+		}
+	}
+	return filepath.Join(NormalizePath(moveDir), rel)
+}
+
+// moveToFinalDir verifies a fully processed track and moves it, with its sidecars, into the
+// import folder. The layout below baseDir is kept so artist/album folders survive the move.
+// A track that fails the integrity check stays where it is.
+func moveToFinalDir(job PostProcessJob) {
+	if broken := checkAudioFile(job.FilePath); broken != nil {
+		fmt.Printf("[Final Move] Not moving %s: %s\n", filepath.Base(job.FilePath), broken.Reason)
+		recordEnrichWarning(job, fmt.Sprintf("not moved to import folder: %s", broken.Reason))
+		return
+	}
+
+	target := FinalMoveTarget(job.FilePath, job.BaseDir, job.FinalMoveDir)
+	if err := moveFileAtomic(job.FilePath, target); err != nil {
+		fmt.Printf("[Final Move] Failed to move %s: %v\n", filepath.Base(job.FilePath), err)
+		recordEnrichWarning(job, fmt.Sprintf("move to import folder failed: %v", err))
+		return
+	}
+
+	base := strings.TrimSuffix(job.FilePath, filepath.Ext(job.FilePath))
+	targetBase := strings.TrimSuffix(target, filepath.Ext(target))
+	for _, ext := range finalMoveSidecars {
+		if fileExists(base + ext) {
+			if err := moveFileAtomic(base+ext, targetBase+ext); err != nil {
+				fmt.Printf("[Final Move] Failed to move sidecar %s: %v\n", filepath.Base(base+ext), err)
+			}
+		}
+	}
+
+	finalLocationsLock.Lock()
+	finalLocations[job.FilePath] = target
+	finalLocationsLock.Unlock()
+
+	if job.ItemID != "" {
+		SetItemFilePath(job.ItemID, target)
+	}
+	updateDownloadHistoryPath(job.FilePath, target)
+	fmt.Printf("[Final Move] %s -> %s\n", filepath.Base(job.FilePath), target)
+}
+
+// moveFileAtomic moves src to dst so dst only ever appears complete. Across volumes the data is
+// copied to a temp name in the destination folder first and renamed into place.
+func moveFileAtomic(src, dst string) error {
+	if fileExists(dst) {
+		return fmt.Errorf("destination already exists: %s", dst)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("failed to create destination folder: %v", err)
+	}
+
+	// Across volumes the copy goes under a name media servers ignore, then is renamed
+	_, err := finalizeFile(src, dst)
+	return err
+}
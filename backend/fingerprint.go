@@ -0,0 +1,203 @@
+package backend
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	acoustIDLookupURL = "https://api.acoustid.org/v2/lookup"
+	// AcoustID allows 3 requests per second per client key
+	acoustIDMinInterval = 350 * time.Millisecond
+	// Matches scored below this are too uncertain to retag a library with
+	acoustIDMinScore = 0.5
+)
+
+// ErrFingerprintUnavailable means fingerprinting was skipped because fpcalc is not installed or
+// no AcoustID key is set. Callers treat it as "no match" rather than a failure.
+var ErrFingerprintUnavailable = errors.New("fingerprinting unavailable")
+
+var (
+	acoustIDKey     string
+	acoustIDKeyLock sync.RWMutex
+
+	acoustIDLastCall time.Time
+	acoustIDCallLock sync.Mutex
+
+	fpcalcMissingOnce sync.Once
+)
+
+// SetAcoustIDKey sets the AcoustID application key used for fingerprint lookups
+func SetAcoustIDKey(key string) {
+	acoustIDKeyLock.Lock()
+	acoustIDKey = strings.TrimSpace(key)
+	acoustIDKeyLock.Unlock()
+}
+
+func getAcoustIDKey() string {
+	acoustIDKeyLock.RLock()
+	defer acoustIDKeyLock.RUnlock()
+	return acoustIDKey
+}
+
+// GetFpcalcPath returns the Chromaprint fpcalc binary, looking in the app directory first and then PATH
+func GetFpcalcPath() (string, error) {
+	fpcalcName := "fpcalc"
+	if runtime.GOOS == "windows" {
+		fpcalcName = "fpcalc.exe"
+	}
+
+	if dir, err := GetFFmpegDir(); err == nil {
+		fpcalcPath := filepath.Join(dir, fpcalcName)
+		if _, err := os.Stat(fpcalcPath); err == nil {
+			return fpcalcPath, nil
+		}
+	}
+	if fpcalcPath, err := exec.LookPath(fpcalcName); err == nil {
+		return fpcalcPath, nil
+	}
+	return "", fmt.Errorf("fpcalc not found in app directory or PATH")
+}
+
+// computeFingerprint runs fpcalc on an audio file and returns its Chromaprint fingerprint and duration
+func computeFingerprint(filePath string) (string, int, error) {
+	fpcalcPath, err := GetFpcalcPath()
+	if err != nil {
+		fpcalcMissingOnce.Do(func() {
+			fmt.Println("[Fingerprint] fpcalc is not installed, skipping fingerprint matching")
+		})
+		return "", 0, ErrFingerprintUnavailable
+	}
+
+	cmd := exec.Command(fpcalcPath, "-json", filePath)
+	setHideWindow(cmd)
+
+	output, err := ffmpegOutput(cmd)
+	if err != nil {
+		return "", 0, fmt.Errorf("fpcalc failed: %w", err)
+	}
+
+	var result struct {
+		Duration    float64 `json:"duration"`
+		Fingerprint string  `json:"fingerprint"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return "", 0, fmt.Errorf("failed to parse fpcalc output: %w", err)
+	}
+	if result.Fingerprint == "" {
+		return "", 0, fmt.Errorf("fpcalc returned no fingerprint")
+	}
+	return result.Fingerprint, int(result.Duration), nil
+}
+
+// acoustIDResponse is the subset of an AcoustID lookup with recordings and release groups
+type acoustIDResponse struct {
+	Status string `json:"status"`
+	Error  struct {
+		Message string `json:"message"`
+	} `json:"error"`
+	Results []struct {
+		Score      float64 `json:"score"`
+		Recordings []struct {
+			ID      string `json:"id"`
+			Title   string `json:"title"`
+			Artists []struct {
+				Name string `json:"name"`
+			} `json:"artists"`
+			ReleaseGroups []struct {
+				Title string `json:"title"`
+				Type  string `json:"type"`
+			} `json:"releasegroups"`
+		} `json:"recordings"`
+	} `json:"results"`
+}
+
+// FingerprintFile identifies an audio file by its Chromaprint fingerprint through AcoustID and
+// returns the matching MusicBrainz title, artist, album and recording ID. It returns
+// ErrFingerprintUnavailable when fpcalc or an AcoustID key is missing.
+func FingerprintFile(path string) (*Metadata, error) {
+	key := getAcoustIDKey()
+	if key == "" {
+		return nil, ErrFingerprintUnavailable
+	}
+
+	fingerprint, duration, err := computeFingerprint(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Space lookups out to stay under AcoustID's rate limit when called from worker pools
+	acoustIDCallLock.Lock()
+	if wait := acoustIDMinInterval - time.Since(acoustIDLastCall); wait > 0 {
+		time.Sleep(wait)
+	}
+	acoustIDLastCall = time.Now()
+	acoustIDCallLock.Unlock()
+
+	form := url.Values{}
+	form.Set("client", key)
+	form.Set("meta", "recordings releasegroups compress")
+	form.Set("duration", strconv.Itoa(duration))
+	form.Set("fingerprint", fingerprint)
+
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.PostForm(acoustIDLookupURL, form)
+	if err != nil {
+		return nil, fmt.Errorf("AcoustID lookup failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var lookup acoustIDResponse
+	if err := json.NewDecoder(resp.Body).Decode(&lookup); err != nil {
+		return nil, fmt.Errorf("failed to decode AcoustID response: %w", err)
+	}
+	if lookup.Status != "ok" {
+		return nil, fmt.Errorf("AcoustID error: %s", lookup.Error.Message)
+	}
+
+	// Results come sorted by score; take the best one that links to a recording
+	for _, result := range lookup.Results {
+		if result.Score < acoustIDMinScore {
+			break
+		}
+		for _, recording := range result.Recordings {
+			if recording.Title == "" {
+				continue
+			}
+
+			artists := make([]string, 0, len(recording.Artists))
+			for _, artist := range recording.Artists {
+				artists = append(artists, artist.Name)
+			}
+			metadata := &Metadata{
+				Title:  recording.Title,
+				Artist: strings.Join(artists, ", "),
+				MBID:   recording.ID,
+			}
+			// Prefer the album release group over singles and compilations
+			for _, group := range recording.ReleaseGroups {
+				if metadata.Album == "" || strings.EqualFold(group.Type, "Album") {
+					metadata.Album = group.Title
+					if strings.EqualFold(group.Type, "Album") {
+						break
+					}
+				}
+			}
+
+			fmt.Printf("[Fingerprint] %s matched %s - %s (score %.2f)\n", filepath.Base(path), metadata.Artist, metadata.Title, result.Score)
+			return metadata, nil
+		}
+	}
+	return nil, fmt.Errorf("no AcoustID match for %s", filepath.Base(path))
+}
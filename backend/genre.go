@@ -0,0 +1,196 @@
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	id3v2 "github.com/bogem/id3v2/v2"
+)
+
+// maxGenres caps how many genres are written; Spotify and MusicBrainz lists get long and noisy
+const maxGenres = 3
+
+var (
+	artistGenreCache     = make(map[string][]string)
+	artistGenreCacheLock sync.RWMutex
+)
+
+// FetchGenresForArtist returns the genres Spotify lists for an artist. Results are cached per
+// artist for the session, so an album only costs one lookup.
+func FetchGenresForArtist(artistID string) ([]string, error) {
+	if artistID == "" {
+		return nil, fmt.Errorf("artist ID is required")
+	}
+
+	cacheKey := "spotify:" + artistID
+	artistGenreCacheLock.RLock()
+	cached, ok := artistGenreCache[cacheKey]
+	artistGenreCacheLock.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client := NewSpotifyMetadataClient()
+	token, err := client.getAccessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	artist, err := client.fetchArtist(ctx, artistID, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch artist: %w", err)
+	}
+
+	genres := limitGenres(artist.Genres)
+	artistGenreCacheLock.Lock()
+	artistGenreCache[cacheKey] = genres
+	artistGenreCacheLock.Unlock()
+	return genres, nil
+}
+
+// fetchMusicBrainzGenres returns the most-voted tags of the best MusicBrainz match for an artist name
+func fetchMusicBrainzGenres(artistName string) ([]string, error) {
+	if artistName == "" {
+		return nil, fmt.Errorf("artist name is required")
+	}
+
+	cacheKey := "musicbrainz:" + strings.ToLower(artistName)
+	artistGenreCacheLock.RLock()
+	cached, ok := artistGenreCache[cacheKey]
+	artistGenreCacheLock.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	query := url.QueryEscape(fmt.Sprintf("artist:\"%s\"", artistName))
+	req, err := http.NewRequest("GET", fmt.Sprintf("https://musicbrainz.org/ws/2/artist/?query=%s&fmt=json&limit=1", query), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	// MusicBrainz requires User-Agent
+	req.Header.Set("User-Agent", "SpotiFLAC/1.0 (https://github.com/spotflac)")
+
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("MusicBrainz API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("MusicBrainz API returned status %d", resp.StatusCode)
+	}
+
+	var searchResp struct {
+		Artists []struct {
+			Score int `json:"score"`
+			Tags  []struct {
+				Count int    `json:"count"`
+				Name  string `json:"name"`
+			} `json:"tags"`
+		} `json:"artists"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&searchResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if len(searchResp.Artists) == 0 || searchResp.Artists[0].Score < 90 {
+		return nil, fmt.Errorf("no MusicBrainz artist found for %s", artistName)
+	}
+
+	tags := searchResp.Artists[0].Tags
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].Count > tags[j].Count })
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag.Count > 0 {
+			names = append(names, tag.Name)
+		}
+	}
+
+	genres := limitGenres(names)
+	artistGenreCacheLock.Lock()
+	artistGenreCache[cacheKey] = genres
+	artistGenreCacheLock.Unlock()
+	return genres, nil
+}
+
+// ResolveGenres looks up genres for a track's artist: Spotify's artist genres first, then
+// MusicBrainz tags for the artist name when Spotify has none
+func ResolveGenres(artistID, artistName string) ([]string, error) {
+	if artistID != "" {
+		genres, err := FetchGenresForArtist(artistID)
+		if err == nil && len(genres) > 0 {
+			return genres, nil
+		}
+		if err != nil {
+			fmt.Printf("[Genre] Spotify lookup failed: %v\n", err)
+		}
+	}
+
+	// Multi-artist strings list the primary artist first
+	primary := strings.TrimSpace(strings.Split(artistName, ",")[0])
+	return fetchMusicBrainzGenres(primary)
+}
+
+// SplitGenres parses a user-supplied genre list separated by semicolons or commas
+func SplitGenres(genre string) []string {
+	fields := strings.FieldsFunc(genre, func(r rune) bool { return r == ';' || r == ',' })
+	genres := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field = strings.TrimSpace(field); field != "" {
+			genres = append(genres, field)
+		}
+	}
+	return genres
+}
+
+func limitGenres(genres []string) []string {
+	if len(genres) > maxGenres {
+		return genres[:maxGenres]
+	}
+	return genres
+}
+
+// EmbedGenres writes genres into a file's tags: one GENRE comment per genre for FLAC, and a
+// single "; "-separated TCON frame or genre atom for MP3 and M4A
+func EmbedGenres(filePath string, genres []string) error {
+	if len(genres) == 0 {
+		return nil
+	}
+
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".flac":
+		return setVorbisFieldValues(filePath, map[string][]string{"GENRE": genres})
+	case ".mp3":
+		restore, err := prepareWritable(filePath)
+		if err != nil {
+			return err
+		}
+		defer restore()
+
+		tag, err := id3v2.Open(filePath, id3v2.Options{Parse: true})
+		if err != nil {
+			return fmt.Errorf("failed to open MP3 file: %w", err)
+		}
+		defer tag.Close()
+
+		tag.SetGenre(strings.Join(genres, "; "))
+		if err := tag.Save(); err != nil {
+			return fmt.Errorf("failed to save MP3 tags: %w", err)
+		}
+		return nil
+	case ".m4a":
+		return setM4ATags(filePath, map[string]string{"genre": strings.Join(genres, "; ")}, false)
+	default:
+		return fmt.Errorf("unsupported file format for genre tag: %s", filepath.Ext(filePath))
+	}
+}
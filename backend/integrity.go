@@ -0,0 +1,361 @@
+package backend
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	mewflac "github.com/mewkiz/flac"
+)
+
+// BrokenFile describes an audio file that failed integrity checks
+type BrokenFile struct {
+	FilePath         string  `json:"file_path"`
+	FileName         string  `json:"file_name"`
+	Size             int64   `json:"size"`
+	Reason           string  `json:"reason"`
+	ExpectedDuration float64 `json:"expected_duration,omitempty"`
+	ActualDuration   float64 `json:"actual_duration,omitempty"`
+}
+
+// minPlausibleDuration is the shortest decoded duration (in seconds) considered a real track
+const minPlausibleDuration = 1.0
+
+var (
+	verifyBeforeSkip     bool
+	verifyBeforeSkipLock sync.RWMutex
+)
+
+// SetVerifyBeforeSkip sets whether a file matched by ISRC is fully decoded before the download is skipped
+func SetVerifyBeforeSkip(enabled bool) {
+	verifyBeforeSkipLock.Lock()
+	verifyBeforeSkip = enabled
+	verifyBeforeSkipLock.Unlock()
+}
+
+func isVerifyBeforeSkipEnabled() bool {
+	verifyBeforeSkipLock.RLock()
+	defer verifyBeforeSkipLock.RUnlock()
+	return verifyBeforeSkip
+}
+
+// VerifyFLACIntegrity decodes every frame of a FLAC file and checks the decoded length
+// against the sample count declared in STREAMINFO. It returns the expected and decoded
+// durations in seconds.
+func VerifyFLACIntegrity(filePath string) (float64, float64, error) {
+	stream, err := mewflac.ParseFile(filePath)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse FLAC: %w", err)
+	}
+	defer stream.Close()
+
+	sampleRate := float64(stream.Info.SampleRate)
+	if sampleRate == 0 {
+		return 0, 0, fmt.Errorf("invalid sample rate in STREAMINFO")
+	}
+	expected := float64(stream.Info.NSamples) / sampleRate
+
+	var decodedSamples uint64
+	for {
+		frame, err := stream.ParseNext()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return expected, float64(decodedSamples) / sampleRate, fmt.Errorf("decode error after %.1fs: %w", float64(decodedSamples)/sampleRate, err)
+		}
+		decodedSamples += uint64(frame.BlockSize)
+	}
+
+	actual := float64(decodedSamples) / sampleRate
+
+	// NSamples == 0 means the encoder didn't know the length; only the decoded duration counts
+	if stream.Info.NSamples > 0 && decodedSamples < stream.Info.NSamples*98/100 {
+		return expected, actual, fmt.Errorf("truncated: decoded %.1fs of %.1fs", actual, expected)
+	}
+
+	return expected, actual, nil
+}
+
+// probeAudioDuration returns the container duration reported by ffprobe
+func probeAudioDuration(filePath string) (float64, error) {
+	ffprobePath, err := GetFFprobePath()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := ValidateExecutable(ffprobePath); err != nil {
+		return 0, fmt.Errorf("invalid ffprobe executable: %w", err)
+	}
+
+	cmd := exec.Command(ffprobePath,
+		"-v", "error",
+		"-print_format", "json",
+		"-show_format",
+		filePath,
+	)
+
+	// Hide console window on Windows
+	setHideWindow(cmd)
+
+	output, err := ffmpegOutput(cmd)
+	if err != nil {
+		return 0, fmt.Errorf("ffprobe failed: %w", err)
+	}
+
+	var result struct {
+		Format struct {
+			Duration string `json:"duration"`
+		} `json:"format"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return 0, fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+
+	duration, err := strconv.ParseFloat(result.Format.Duration, 64)
+	if err != nil {
+		return 0, fmt.Errorf("no duration reported")
+	}
+	return duration, nil
+}
+
+// decodeAudioDuration decodes the first audio stream with ffmpeg and returns how many seconds
+// of it actually decoded, with an error if ffmpeg reported damage along the way
+func decodeAudioDuration(filePath string) (float64, error) {
+	ffmpegPath, err := GetFFmpegPath()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := ValidateExecutable(ffmpegPath); err != nil {
+		return 0, fmt.Errorf("invalid ffmpeg executable: %w", err)
+	}
+
+	cmd := exec.Command(ffmpegPath,
+		"-v", "error",
+		"-nostats",
+		"-i", filePath,
+		"-map", "0:a:0",
+		"-f", "null",
+		"-progress", "pipe:1",
+		"-",
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	// Hide console window on Windows
+	setHideWindow(cmd)
+
+	output, err := ffmpegOutput(cmd)
+	if err != nil {
+		return 0, fmt.Errorf("ffmpeg failed: %w", err)
+	}
+
+	// -progress repeats out_time_us; the last value is the decoded length
+	var decoded float64
+	for _, line := range strings.Split(string(output), "\n") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(line), "out_time_us="); ok {
+			if us, err := strconv.ParseInt(value, 10, 64); err == nil && us > 0 {
+				decoded = float64(us) / 1e6
+			}
+		}
+	}
+
+	if msg := strings.TrimSpace(stderr.String()); msg != "" {
+		first, _, _ := strings.Cut(msg, "\n")
+		return decoded, fmt.Errorf("decode error after %.1fs: %s", decoded, first)
+	}
+	return decoded, nil
+}
+
+// VerifyAudioIntegrity decodes an MP3 or M4A file and checks the decoded length against
+// the duration its container declares. It returns the expected and decoded durations in seconds.
+func VerifyAudioIntegrity(filePath string) (float64, float64, error) {
+	expected, err := probeAudioDuration(filePath)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	actual, err := decodeAudioDuration(filePath)
+	if err != nil {
+		return expected, actual, err
+	}
+
+	if expected > 0 && actual < expected*0.98 {
+		return expected, actual, fmt.Errorf("truncated: decoded %.1fs of %.1fs", actual, expected)
+	}
+
+	return expected, actual, nil
+}
+
+// checkAudioFile returns a BrokenFile if the file fails to decode, or nil if it looks healthy
+func checkAudioFile(filePath string) *BrokenFile {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return &BrokenFile{FilePath: filePath, FileName: filepath.Base(filePath), Reason: fmt.Sprintf("cannot stat file: %v", err)}
+	}
+
+	broken := &BrokenFile{
+		FilePath: filePath,
+		FileName: filepath.Base(filePath),
+		Size:     info.Size(),
+	}
+
+	if info.Size() == 0 {
+		broken.Reason = "zero-size file"
+		return broken
+	}
+
+	var actual float64
+	if strings.ToLower(filepath.Ext(filePath)) == ".flac" {
+		expected, decoded, err := VerifyFLACIntegrity(filePath)
+		broken.ExpectedDuration = expected
+		broken.ActualDuration = decoded
+		if err != nil {
+			broken.Reason = err.Error()
+			return broken
+		}
+		actual = decoded
+	} else {
+		if installed, _ := IsFFprobeInstalled(); !installed {
+			return nil
+		}
+		if installed, _ := IsFFmpegInstalled(); !installed {
+			duration, err := probeAudioDuration(filePath)
+			if err != nil {
+				broken.Reason = err.Error()
+				return broken
+			}
+			broken.ActualDuration = duration
+			actual = duration
+		} else {
+			expected, decoded, err := VerifyAudioIntegrity(filePath)
+			broken.ExpectedDuration = expected
+			broken.ActualDuration = decoded
+			if err != nil {
+				broken.Reason = err.Error()
+				return broken
+			}
+			actual = decoded
+		}
+	}
+
+	if actual < minPlausibleDuration {
+		broken.Reason = fmt.Sprintf("implausibly short duration: %.2fs", actual)
+		return broken
+	}
+
+	return nil
+}
+
+// isIntegrityCheckedFile reports whether path has one of the extensions the integrity scan covers
+func isIntegrityCheckedFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".flac" || ext == ".mp3" || ext == ".m4a"
+}
+
+// FindBrokenAudioFiles scans a directory recursively and returns audio files that are
+// empty, fail to decode, or are shorter than their declared length
+func FindBrokenAudioFiles(dirPath string) ([]BrokenFile, error) {
+	dirPath = NormalizePath(dirPath)
+	fmt.Printf("\n[Integrity] Scanning for broken audio files in: %s\n", dirPath)
+
+	if _, err := os.Stat(dirPath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("directory does not exist: %s", dirPath)
+	}
+
+	files := make([]string, 0)
+	err := filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			return nil
+		}
+		if isIntegrityCheckedFile(path) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan directory: %v", err)
+	}
+
+	fmt.Printf("[Integrity] Checking %d audio files...\n", len(files))
+
+	const maxWorkers = 10
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	broken := make([]BrokenFile, 0)
+
+	fileChan := make(chan string, len(files))
+	for _, f := range files {
+		fileChan <- f
+	}
+	close(fileChan)
+
+	for w := 0; w < maxWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range fileChan {
+				if result := checkAudioFile(path); result != nil {
+					fmt.Printf("[Integrity] ✗ %s: %s\n", result.FileName, result.Reason)
+					mu.Lock()
+					broken = append(broken, *result)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	sort.Slice(broken, func(i, j int) bool {
+		return broken[i].FilePath < broken[j].FilePath
+	})
+
+	fmt.Printf("[Integrity] Scan complete: %d/%d files broken\n", len(broken), len(files))
+	return broken, nil
+}
+
+// DeleteBrokenAudioFiles removes the given files so they can be re-downloaded. Each file is
+// checked again first and kept if it is no longer broken.
+func DeleteBrokenAudioFiles(filePaths []string) (int, error) {
+	deleted, failed := 0, 0
+	var lastErr error
+	for _, path := range filePaths {
+		// Only ever delete what the scan could have reported
+		if !isIntegrityCheckedFile(path) {
+			fmt.Printf("[Integrity] Refusing to delete %s: not an audio file\n", path)
+			lastErr = fmt.Errorf("not an audio file: %s", path)
+			failed++
+			continue
+		}
+		// The file may have been replaced or repaired since the scan
+		if checkAudioFile(path) == nil {
+			fmt.Printf("[Integrity] Keeping %s: it now passes the check\n", path)
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			fmt.Printf("[Integrity] Failed to delete %s: %v\n", path, err)
+			lastErr = err
+			failed++
+			continue
+		}
+		fmt.Printf("[Integrity] Deleted broken file: %s\n", path)
+		deleted++
+	}
+	if lastErr != nil {
+		return deleted, fmt.Errorf("failed to delete %d file(s): %v", failed, lastErr)
+	}
+	return deleted, nil
+}
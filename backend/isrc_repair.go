@@ -0,0 +1,153 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// SearchSpotifyForISRC searches Spotify for a track by title and artist and returns its ISRC
+func SearchSpotifyForISRC(title, artist string) (string, error) {
+	ctx := context.Background()
+	client := NewSpotifyMetadataClient()
+
+	query := fmt.Sprintf("track:%s artist:%s", title, artist)
+	results, err := client.SearchByType(ctx, query, "track", 5, 0)
+	if err != nil {
+		return "", fmt.Errorf("Spotify search failed: %w", err)
+	}
+
+	wantTitle := normalizeMatchKey(title)
+	for _, result := range results {
+		if result.ISRC != "" && normalizeMatchKey(result.Name) == wantTitle {
+			return result.ISRC, nil
+		}
+	}
+
+	return "", fmt.Errorf("no ISRC found for: %s - %s", title, artist)
+}
+
+// resolveMissingISRC looks up the ISRC of a file from its SPOTIFY_ID (or song.link SOURCE_URL) tag
+// via the database, then by track and artist in the database, then by Spotify search
+func resolveMissingISRC(filePath, databasePath string, metadata *Metadata) (string, string) {
+	if databasePath != "" {
+		spotifyID, _ := ReadSpotifyIDFromFile(filePath)
+		if spotifyID == "" {
+			sourceURL, _ := ReadSourceURLFromFile(filePath)
+			spotifyID = spotifyIDFromSourceURL(sourceURL)
+		}
+		if spotifyID != "" {
+			if isrc, err := GetISRCFromDatabase(databasePath, spotifyID); err == nil && isrc != "" {
+				return isrc, "database (spotify id)"
+			}
+		}
+
+		if metadata.Title != "" && metadata.Artist != "" {
+			if isrc, err := GetISRCByTrackFromDatabase(databasePath, metadata.Title, metadata.Artist); err == nil && isrc != "" {
+				return isrc, "database (track search)"
+			}
+		}
+	}
+
+	if metadata.Title != "" && metadata.Artist != "" {
+		if isrc, err := SearchSpotifyForISRC(metadata.Title, metadata.Artist); err == nil && isrc != "" {
+			return isrc, "spotify search"
+		}
+	}
+
+	return "", ""
+}
+
+// RepairISRCTags finds FLAC files without an ISRC tag, resolves the ISRC and writes it
+// into the file so ISRC-based dedup works on libraries downloaded by older versions
+func RepairISRCTags(dirPath, databasePath string) ([]TrackVerificationResult, error) {
+	dirPath = NormalizePath(dirPath)
+	fmt.Printf("\n[ISRC Repair] Scanning: %s\n", dirPath)
+
+	if _, err := os.Stat(dirPath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("directory does not exist: %s", dirPath)
+	}
+
+	missing := make([]string, 0)
+	err := filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || strings.ToLower(filepath.Ext(path)) != ".flac" {
+			return nil
+		}
+		if isrc, err := ReadISRCFromFile(path); err == nil && isrc == "" {
+			missing = append(missing, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan directory: %v", err)
+	}
+
+	fmt.Printf("[ISRC Repair] %d files without ISRC\n", len(missing))
+
+	results := make([]TrackVerificationResult, len(missing))
+
+	// Spotify search is rate limited, so keep the pool small
+	const maxWorkers = 4
+	var wg sync.WaitGroup
+	repaired := int32(0)
+
+	indexChan := make(chan int, len(missing))
+	for i := range missing {
+		indexChan <- i
+	}
+	close(indexChan)
+
+	for w := 0; w < maxWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexChan {
+				path := missing[i]
+				result := TrackVerificationResult{
+					FilePath:  path,
+					TrackName: strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+				}
+
+				metadata, err := ExtractMetadataFromFile(path)
+				if err != nil {
+					result.Error = fmt.Sprintf("Failed to extract metadata: %v", err)
+					results[i] = result
+					continue
+				}
+				if metadata.Title != "" {
+					result.TrackName = metadata.Title
+				}
+
+				isrc, source := resolveMissingISRC(path, databasePath, metadata)
+				if isrc == "" {
+					result.Error = "Could not resolve ISRC"
+					fmt.Printf("[ISRC Repair] ✗ %s: not found\n", result.TrackName)
+					results[i] = result
+					continue
+				}
+
+				if err := setVorbisFields(path, map[string]string{"ISRC": strings.ToUpper(isrc)}); err != nil {
+					result.ReadOnlySkipped = errors.Is(err, ErrFileReadOnly)
+					result.Error = fmt.Sprintf("Failed to write ISRC: %v", err)
+					results[i] = result
+					continue
+				}
+
+				result.ISRC = strings.ToUpper(isrc)
+				result.ISRCRepaired = true
+				atomic.AddInt32(&repaired, 1)
+				fmt.Printf("[ISRC Repair] ✓ %s: %s (via %s)\n", result.TrackName, result.ISRC, source)
+				results[i] = result
+			}
+		}()
+	}
+
+	wg.Wait()
+	fmt.Printf("[ISRC Repair] Repaired %d/%d files\n", repaired, len(missing))
+	return results, nil
+}
@@ -0,0 +1,65 @@
+package backend
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+var (
+	enforceSpotifyISRC     = true
+	enforceSpotifyISRCLock sync.RWMutex
+)
+
+// SetEnforceSpotifyISRC toggles rewriting a downloaded file's ISRC tag to the Spotify ISRC
+func SetEnforceSpotifyISRC(enabled bool) {
+	enforceSpotifyISRCLock.Lock()
+	enforceSpotifyISRC = enabled
+	enforceSpotifyISRCLock.Unlock()
+}
+
+func isEnforceSpotifyISRCEnabled() bool {
+	enforceSpotifyISRCLock.RLock()
+	defer enforceSpotifyISRCLock.RUnlock()
+	return enforceSpotifyISRC
+}
+
+// EnsureISRCTag makes sure a downloaded FLAC carries the Spotify ISRC, whichever path it was
+// downloaded by, and reads the tag back to confirm. Returns true if the tag had to be corrected.
+func EnsureISRCTag(filePath, isrc string) (bool, error) {
+	if !isEnforceSpotifyISRCEnabled() || isrc == "" {
+		return false, nil
+	}
+	if strings.ToLower(filepath.Ext(filePath)) != ".flac" {
+		return false, nil
+	}
+
+	current, err := ReadISRCFromFile(filePath)
+	if err != nil {
+		return false, err
+	}
+	if normalizeISRC(current) == normalizeISRC(isrc) {
+		return false, nil
+	}
+
+	wanted := strings.ToUpper(strings.TrimSpace(isrc))
+	if current == "" {
+		fmt.Printf("[ISRC] No ISRC tag in %s, writing %s\n", filepath.Base(filePath), wanted)
+	} else {
+		fmt.Printf("[ISRC] Replacing service ISRC %s with Spotify ISRC %s in %s\n", current, wanted, filepath.Base(filePath))
+	}
+
+	if err := setVorbisFields(filePath, map[string]string{"ISRC": wanted}); err != nil {
+		return false, fmt.Errorf("failed to write ISRC tag: %v", err)
+	}
+
+	written, err := ReadISRCFromFile(filePath)
+	if err != nil {
+		return true, fmt.Errorf("failed to read back ISRC tag: %v", err)
+	}
+	if normalizeISRC(written) != normalizeISRC(wanted) {
+		return true, fmt.Errorf("ISRC read-back mismatch: wrote %s, read %s", wanted, written)
+	}
+	return true, nil
+}
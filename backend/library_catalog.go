@@ -0,0 +1,192 @@
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CatalogTrack is one file of an exported library catalog
+type CatalogTrack struct {
+	Path        string  `json:"path"`
+	Size        int64   `json:"size"`
+	Modified    string  `json:"modified"`
+	Format      string  `json:"format"`
+	Quality     string  `json:"quality"`
+	Duration    float64 `json:"duration"` // Seconds
+	Title       string  `json:"title,omitempty"`
+	Artist      string  `json:"artist,omitempty"`
+	Album       string  `json:"album,omitempty"`
+	AlbumArtist string  `json:"album_artist,omitempty"`
+	Date        string  `json:"date,omitempty"`
+	TrackNumber int     `json:"track_number,omitempty"`
+	DiscNumber  int     `json:"disc_number,omitempty"`
+	ISRC        string  `json:"isrc,omitempty"`
+	SpotifyID   string  `json:"spotify_id,omitempty"`
+	SourceURL   string  `json:"source_url,omitempty"`
+	HasCover    bool    `json:"has_cover"`
+	HasLyrics   bool    `json:"has_lyrics"`
+	Error       string  `json:"error,omitempty"` // Tags couldn't be read
+}
+
+// LibraryCatalog is the JSON document written by ExportCatalog
+type LibraryCatalog struct {
+	Root       string         `json:"root"`
+	ExportedAt string         `json:"exported_at"`
+	TrackCount int            `json:"track_count"`
+	Tracks     []CatalogTrack `json:"tracks"`
+}
+
+var (
+	catalogRelativePaths     bool
+	catalogRelativePathsLock sync.RWMutex
+)
+
+// SetCatalogRelativePaths toggles writing catalog paths relative to the library root, so the
+// catalog stays valid when the library is moved or mounted elsewhere
+func SetCatalogRelativePaths(enabled bool) {
+	catalogRelativePathsLock.Lock()
+	catalogRelativePaths = enabled
+	catalogRelativePathsLock.Unlock()
+}
+
+func isCatalogRelativePathsEnabled() bool {
+	catalogRelativePathsLock.RLock()
+	defer catalogRelativePathsLock.RUnlock()
+	return catalogRelativePaths
+}
+
+// catalogTrack reads tags, quality and cover/lyrics presence for one file
+func catalogTrack(filePath string, info os.FileInfo) CatalogTrack {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	track := CatalogTrack{
+		Path:     filePath,
+		Size:     info.Size(),
+		Modified: info.ModTime().UTC().Format(time.RFC3339),
+		Format:   strings.TrimPrefix(ext, "."),
+		Quality:  strings.TrimPrefix(ext, "."),
+	}
+
+	if ext == ".flac" {
+		if quality, duration, err := flacQualityLabel(filePath); err == nil {
+			track.Quality = quality
+			track.Duration = duration
+		}
+		track.SpotifyID, _ = ReadSpotifyIDFromFile(filePath)
+	} else if duration, err := readAudioDuration(filePath); err == nil {
+		track.Duration = duration
+	}
+
+	if metadata, err := ExtractMetadataFromFile(filePath); err == nil {
+		track.Title = metadata.Title
+		track.Artist = metadata.Artist
+		track.Album = metadata.Album
+		track.AlbumArtist = metadata.AlbumArtist
+		track.Date = metadata.Date
+		track.TrackNumber = metadata.TrackNumber
+		track.DiscNumber = metadata.DiscNumber
+		track.ISRC = metadata.ISRC
+	} else {
+		track.Error = err.Error()
+	}
+	track.SourceURL, _ = ReadSourceURLFromFile(filePath)
+
+	basePath := strings.TrimSuffix(filePath, filepath.Ext(filePath))
+	track.HasCover = fileExists(basePath+".jpg") || fileExists(basePath+".png") || HasValidEmbeddedCover(filePath)
+	track.HasLyrics = fileExists(basePath+".lrc") || HasEmbeddedLyrics(filePath)
+	return track
+}
+
+// ExportCatalog walks dirPath with a worker pool and writes every audio file's path, tags,
+// ISRC, quality and cover/lyrics presence to outPath as a JSON catalog, sorted by path
+func ExportCatalog(dirPath, outPath string) (*LibraryCatalog, error) {
+	dirPath = NormalizePath(dirPath)
+	fmt.Printf("\n[Catalog] Scanning: %s\n", dirPath)
+
+	if _, err := os.Stat(dirPath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("directory does not exist: %s", dirPath)
+	}
+
+	type candidate struct {
+		path string
+		info os.FileInfo
+	}
+	files := make([]candidate, 0)
+	err := filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		if ext == ".flac" || ext == ".mp3" || ext == ".m4a" {
+			files = append(files, candidate{path: path, info: info})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan directory: %v", err)
+	}
+
+	tracks := make([]CatalogTrack, len(files))
+	maxWorkers := runtime.NumCPU()
+	if maxWorkers > 8 {
+		maxWorkers = 8
+	}
+
+	var wg sync.WaitGroup
+	jobs := make(chan int, len(files))
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+
+	for w := 0; w < maxWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				tracks[i] = catalogTrack(files[i].path, files[i].info)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if isCatalogRelativePathsEnabled() {
+		for i := range tracks {
+			if rel, err := filepath.Rel(dirPath, tracks[i].Path); err == nil {
+				tracks[i].Path = filepath.ToSlash(rel)
+			}
+		}
+	}
+	sort.Slice(tracks, func(i, j int) bool { return tracks[i].Path < tracks[j].Path })
+
+	catalog := &LibraryCatalog{
+		Root:       dirPath,
+		ExportedAt: time.Now().UTC().Format(time.RFC3339),
+		TrackCount: len(tracks),
+		Tracks:     tracks,
+	}
+
+	data, err := json.MarshalIndent(catalog, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode catalog: %v", err)
+	}
+
+	// Write beside the target and rename, so an interrupted export never leaves a truncated catalog
+	tmpPath := outPath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write catalog: %v", err)
+	}
+	if err := os.Rename(tmpPath, outPath); err != nil {
+		os.Remove(tmpPath)
+		return nil, fmt.Errorf("failed to write catalog: %v", err)
+	}
+
+	fmt.Printf("[Catalog] Wrote %d tracks to %s\n", len(tracks), outPath)
+	return catalog, nil
+}
@@ -0,0 +1,240 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	mewflac "github.com/mewkiz/flac"
+)
+
+// libraryStatsTopN is how many artists and albums the top lists hold
+const libraryStatsTopN = 10
+
+// LibraryCount is a name with its track count, for the top artist and album lists
+type LibraryCount struct {
+	Name   string `json:"name"`
+	Tracks int    `json:"tracks"`
+}
+
+// LibraryStats summarizes a music folder for the dashboard
+type LibraryStats struct {
+	TotalTracks   int            `json:"total_tracks"`
+	TotalSize     int64          `json:"total_size"`
+	TotalDuration float64        `json:"total_duration"` // Seconds
+	ByFormat      map[string]int `json:"by_format"`      // "flac", "mp3", "m4a"
+	ByQuality     map[string]int `json:"by_quality"`     // "24-bit/96kHz", "16-bit/44.1kHz", "mp3", ...
+	MissingCover  int            `json:"missing_cover"`
+	MissingLyrics int            `json:"missing_lyrics"`
+	MissingISRC   int            `json:"missing_isrc"`
+	Unreadable    int            `json:"unreadable"`
+	TopArtists    []LibraryCount `json:"top_artists"`
+	TopAlbums     []LibraryCount `json:"top_albums"`
+	CachedFiles   int            `json:"cached_files"` // Files whose stats came from the cache
+	ScannedFiles  int            `json:"scanned_files"`
+}
+
+// trackStats is what one file contributes to LibraryStats
+type trackStats struct {
+	size      int64
+	modTime   int64
+	format    string
+	quality   string
+	duration  float64
+	artist    string
+	album     string
+	hasCover  bool
+	hasLyrics bool
+	hasISRC   bool
+	readable  bool
+}
+
+var (
+	trackStatsCache     = make(map[string]trackStats)
+	trackStatsCacheLock sync.Mutex
+)
+
+// flacQualityLabel reads bit depth, sample rate and duration from STREAMINFO without decoding audio
+func flacQualityLabel(filePath string) (string, float64, error) {
+	stream, err := mewflac.ParseFile(filePath)
+	if err != nil {
+		return "", 0, err
+	}
+	defer stream.Close()
+
+	info := stream.Info
+	if info.SampleRate == 0 {
+		return "", 0, fmt.Errorf("invalid sample rate in STREAMINFO")
+	}
+	rate := strings.TrimSuffix(fmt.Sprintf("%.1f", float64(info.SampleRate)/1000), ".0")
+	return fmt.Sprintf("%d-bit/%skHz", info.BitsPerSample, rate), float64(info.NSamples) / float64(info.SampleRate), nil
+}
+
+// collectTrackStats reads one file's contribution, reusing the cached one while size and modification time match
+func collectTrackStats(filePath string, info os.FileInfo) (trackStats, bool) {
+	size, modTime := info.Size(), info.ModTime().UnixNano()
+
+	trackStatsCacheLock.Lock()
+	cached, ok := trackStatsCache[filePath]
+	trackStatsCacheLock.Unlock()
+	if ok && cached.size == size && cached.modTime == modTime {
+		return cached, true
+	}
+
+	ext := strings.ToLower(filepath.Ext(filePath))
+	stats := trackStats{size: size, modTime: modTime, format: strings.TrimPrefix(ext, "."), quality: strings.TrimPrefix(ext, ".")}
+
+	if ext == ".flac" {
+		quality, duration, err := flacQualityLabel(filePath)
+		if err == nil {
+			stats.quality = quality
+			stats.duration = duration
+		}
+	} else if duration, err := readAudioDuration(filePath); err == nil {
+		stats.duration = duration
+	}
+
+	if metadata, err := ExtractMetadataFromFile(filePath); err == nil {
+		stats.readable = true
+		stats.artist = metadata.AlbumArtist
+		if stats.artist == "" {
+			stats.artist = metadata.Artist
+		}
+		stats.album = metadata.Album
+		stats.hasISRC = strings.TrimSpace(metadata.ISRC) != ""
+	}
+
+	basePath := strings.TrimSuffix(filePath, filepath.Ext(filePath))
+	stats.hasCover = fileExists(basePath+".jpg") || fileExists(basePath+".png") || HasValidEmbeddedCover(filePath)
+	stats.hasLyrics = fileExists(basePath+".lrc") || HasEmbeddedLyrics(filePath)
+
+	trackStatsCacheLock.Lock()
+	trackStatsCache[filePath] = stats
+	trackStatsCacheLock.Unlock()
+	return stats, false
+}
+
+// topCounts returns the n names with the most tracks, ties broken alphabetically
+func topCounts(counts map[string]int, n int) []LibraryCount {
+	list := make([]LibraryCount, 0, len(counts))
+	for name, tracks := range counts {
+		list = append(list, LibraryCount{Name: name, Tracks: tracks})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Tracks != list[j].Tracks {
+			return list[i].Tracks > list[j].Tracks
+		}
+		return list[i].Name < list[j].Name
+	})
+	if len(list) > n {
+		list = list[:n]
+	}
+	return list
+}
+
+// GetLibraryStats scans dirPath with a worker pool and summarizes formats, size, duration, quality,
+// missing cover/lyrics/ISRC and the biggest artists and albums. Per-file results are cached by
+// path, size and modification time, so rescanning an unchanged library only stats the files.
+func GetLibraryStats(dirPath string) (*LibraryStats, error) {
+	dirPath = NormalizePath(dirPath)
+	fmt.Printf("\n[Library Stats] Scanning: %s\n", dirPath)
+
+	if _, err := os.Stat(dirPath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("directory does not exist: %s", dirPath)
+	}
+
+	type candidate struct {
+		path string
+		info os.FileInfo
+	}
+	files := make([]candidate, 0)
+	err := filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		if ext == ".flac" || ext == ".mp3" || ext == ".m4a" {
+			files = append(files, candidate{path: path, info: info})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan directory: %v", err)
+	}
+
+	stats := &LibraryStats{
+		ByFormat:  make(map[string]int),
+		ByQuality: make(map[string]int),
+	}
+	artistCounts := make(map[string]int)
+	albumCounts := make(map[string]int)
+
+	maxWorkers := runtime.NumCPU()
+	if maxWorkers > 8 {
+		maxWorkers = 8
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	fileChan := make(chan candidate, len(files))
+	for _, f := range files {
+		fileChan <- f
+	}
+	close(fileChan)
+
+	for w := 0; w < maxWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for f := range fileChan {
+				track, cached := collectTrackStats(f.path, f.info)
+
+				mu.Lock()
+				stats.TotalTracks++
+				stats.TotalSize += track.size
+				stats.TotalDuration += track.duration
+				stats.ByFormat[track.format]++
+				stats.ByQuality[track.quality]++
+				if cached {
+					stats.CachedFiles++
+				} else {
+					stats.ScannedFiles++
+				}
+				if !track.hasCover {
+					stats.MissingCover++
+				}
+				if !track.hasLyrics {
+					stats.MissingLyrics++
+				}
+				if !track.readable {
+					stats.Unreadable++
+				} else {
+					if !track.hasISRC {
+						stats.MissingISRC++
+					}
+					if track.artist != "" {
+						artistCounts[track.artist]++
+					}
+					// Same-named albums by different artists ("Greatest Hits") are counted apart
+					if track.album != "" && track.artist != "" {
+						albumCounts[track.album+" - "+track.artist]++
+					} else if track.album != "" {
+						albumCounts[track.album]++
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats.TopArtists = topCounts(artistCounts, libraryStatsTopN)
+	stats.TopAlbums = topCounts(albumCounts, libraryStatsTopN)
+
+	fmt.Printf("[Library Stats] %d tracks, %.2f GB, %d from cache\n", stats.TotalTracks, float64(stats.TotalSize)/(1024*1024*1024), stats.CachedFiles)
+	return stats, nil
+}
@@ -2,6 +2,7 @@ package backend
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -22,35 +23,54 @@ type LibraryVerificationRequest struct {
 	CheckLyrics     bool   `json:"check_lyrics"`
 	DownloadMissing bool   `json:"download_missing"`
 	DatabasePath    string `json:"database_path"`
+	MinCoverWidth   int    `json:"min_cover_width,omitempty"` // Covers narrower than this count as needing an upgrade
+	CheckAlbums     bool   `json:"check_albums,omitempty"`    // Group files by album and flag albums missing tracks
+	UseFingerprint  bool   `json:"use_fingerprint,omitempty"` // Identify untagged, badly named files through AcoustID
 }
 
 // TrackVerificationResult represents the verification result for a single track
 type TrackVerificationResult struct {
-	FilePath         string `json:"file_path"`
-	TrackName        string `json:"track_name"`
-	HasCover         bool   `json:"has_cover"`
-	HasLyrics        bool   `json:"has_lyrics"`
-	CoverPath        string `json:"cover_path,omitempty"`
-	LyricsPath       string `json:"lyrics_path,omitempty"`
-	MissingCover     bool   `json:"missing_cover"`
-	MissingLyrics    bool   `json:"missing_lyrics"`
-	CoverDownloaded  bool   `json:"cover_downloaded"`
-	LyricsDownloaded bool   `json:"lyrics_downloaded"`
-	Error            string `json:"error,omitempty"`
+	FilePath          string `json:"file_path"`
+	TrackName         string `json:"track_name"`
+	HasCover          bool   `json:"has_cover"`
+	HasLyrics         bool   `json:"has_lyrics"`
+	CoverPath         string `json:"cover_path,omitempty"`
+	LyricsPath        string `json:"lyrics_path,omitempty"`
+	MissingCover      bool   `json:"missing_cover"`
+	MissingLyrics     bool   `json:"missing_lyrics"`
+	CoverDownloaded   bool   `json:"cover_downloaded"`
+	CoverWidth        int    `json:"cover_width,omitempty"`
+	LowResCover       bool   `json:"low_res_cover,omitempty"`
+	CoverUpgraded     bool   `json:"cover_upgraded,omitempty"`
+	UsedDefaultCover  bool   `json:"used_default_cover,omitempty"`
+	CoverSource       string `json:"cover_source,omitempty"`
+	CoverQueryVariant string `json:"cover_query_variant,omitempty"`
+	ISRC              string `json:"isrc,omitempty"`
+	ISRCRepaired      bool   `json:"isrc_repaired,omitempty"`
+	SourceURL         string `json:"source_url,omitempty"`
+	ReadOnlySkipped   bool   `json:"read_only_skipped,omitempty"`
+	Fingerprinted     bool   `json:"fingerprinted,omitempty"` // Title and artist came from an AcoustID match
+	MBID              string `json:"mbid,omitempty"`
+	LyricsDownloaded  bool   `json:"lyrics_downloaded"`
+	Error             string `json:"error,omitempty"`
 }
 
 // LibraryVerificationResponse represents the response from library verification
 type LibraryVerificationResponse struct {
-	Success          bool                      `json:"success"`
-	TotalTracks      int                       `json:"total_tracks"`
-	TracksWithCover  int                       `json:"tracks_with_cover"`
-	TracksWithLyrics int                       `json:"tracks_with_lyrics"`
-	MissingCovers    int                       `json:"missing_covers"`
-	MissingLyrics    int                       `json:"missing_lyrics"`
-	CoversDownloaded int                       `json:"covers_downloaded"`
-	LyricsDownloaded int                       `json:"lyrics_downloaded"`
-	Tracks           []TrackVerificationResult `json:"tracks"`
-	Error            string                    `json:"error,omitempty"`
+	Success            bool                      `json:"success"`
+	TotalTracks        int                       `json:"total_tracks"`
+	TracksWithCover    int                       `json:"tracks_with_cover"`
+	TracksWithLyrics   int                       `json:"tracks_with_lyrics"`
+	MissingCovers      int                       `json:"missing_covers"`
+	LowResCovers       int                       `json:"low_res_covers"`
+	MissingLyrics      int                       `json:"missing_lyrics"`
+	CoversDownloaded   int                       `json:"covers_downloaded"`
+	LyricsDownloaded   int                       `json:"lyrics_downloaded"`
+	MissingCoverTracks []string                  `json:"missing_cover_tracks,omitempty"`
+	ThrottledSources   []CoverSourceThrottle     `json:"throttled_sources,omitempty"`
+	IncompleteAlbums   []AlbumStatus             `json:"incomplete_albums,omitempty"`
+	Tracks             []TrackVerificationResult `json:"tracks"`
+	Error              string                    `json:"error,omitempty"`
 }
 
 // VerifyLibrary scans a directory and verifies that all tracks have covers and/or lyrics
@@ -110,6 +130,8 @@ func VerifyLibrary(req LibraryVerificationRequest) (*LibraryVerificationResponse
 			FilePath:  audioPath,
 			TrackName: filepath.Base(audioPath),
 		}
+		// A tagged source URL lets repairs re-resolve the file without searching again
+		result.SourceURL, _ = ReadSourceURLFromFile(audioPath)
 
 		// Check for cover image (same filename but .jpg or .png)
 		if req.CheckCovers {
@@ -126,7 +148,21 @@ func VerifyLibrary(req LibraryVerificationRequest) (*LibraryVerificationResponse
 			if coverPath != "" {
 				result.HasCover = true
 				result.CoverPath = coverPath
-				response.TracksWithCover++
+				result.CoverSource = CoverOriginSidecar
+
+				// A cover below the minimum width needs an upgrade rather than counting as present
+				if req.MinCoverWidth > 0 {
+					if width, _, _, err := CheckCoverSquare(coverPath); err == nil {
+						result.CoverWidth = width
+						if width < req.MinCoverWidth {
+							result.LowResCover = true
+							response.LowResCovers++
+						}
+					}
+				}
+				if !result.LowResCover {
+					response.TracksWithCover++
+				}
 			} else {
 				result.MissingCover = true
 				response.MissingCovers++
@@ -163,16 +199,27 @@ func VerifyLibrary(req LibraryVerificationRequest) (*LibraryVerificationResponse
 	if req.CheckCovers {
 		fmt.Printf("  Tracks with cover: %d\n", response.TracksWithCover)
 		fmt.Printf("  Missing covers: %d\n", response.MissingCovers)
+		if req.MinCoverWidth > 0 {
+			fmt.Printf("  Covers below %dpx: %d\n", req.MinCoverWidth, response.LowResCovers)
+		}
 	}
 	if req.CheckLyrics {
 		fmt.Printf("  Tracks with lyrics: %d\n", response.TracksWithLyrics)
 		fmt.Printf("  Missing lyrics: %d\n", response.MissingLyrics)
 	}
 
+	// Per-file checks can't see tracks that were never downloaded, so compare against album totals
+	if req.CheckAlbums {
+		response.IncompleteAlbums = FindIncompleteAlbums(audioFiles)
+		fmt.Printf("  Incomplete albums: %d\n", len(response.IncompleteAlbums))
+	}
+
 	// Download missing covers if requested
-	if req.DownloadMissing && response.MissingCovers > 0 {
+	coversToFetch := response.MissingCovers + response.LowResCovers
+	if req.DownloadMissing && coversToFetch > 0 {
 		fmt.Printf("\n[Library Verifier] Starting to download missing covers...\n")
 		coverClient := NewCoverClient()
+		ResetCoverSourceThrottles()
 
 		// Parallel download with worker pool
 		const maxWorkers = 10
@@ -181,11 +228,11 @@ func VerifyLibrary(req LibraryVerificationRequest) (*LibraryVerificationResponse
 		downloadedCount := int32(0)
 
 		// Create a channel for tracks to download
-		trackChan := make(chan *TrackVerificationResult, response.MissingCovers)
+		trackChan := make(chan *TrackVerificationResult, coversToFetch)
 
 		// Send tracks to channel
 		for i := range response.Tracks {
-			if response.Tracks[i].MissingCover {
+			if response.Tracks[i].MissingCover || response.Tracks[i].LowResCover {
 				trackChan <- &response.Tracks[i]
 			}
 		}
@@ -200,96 +247,76 @@ func VerifyLibrary(req LibraryVerificationRequest) (*LibraryVerificationResponse
 				for track := range trackChan {
 					current := atomic.AddInt32(&downloadedCount, 1)
 					fmt.Printf("[Library Verifier] Worker %d processing %d/%d: %s\n",
-						workerID, current, response.MissingCovers, track.TrackName)
+						workerID, current, coversToFetch, track.TrackName)
 
 					// Extract metadata from audio file
-					metadata, err := ExtractMetadataFromFile(track.FilePath)
+					metadata, fingerprinted, err := lookupTrackMetadata(track.FilePath, req.UseFingerprint)
 					if err != nil {
 						track.Error = fmt.Sprintf("Failed to extract metadata: %v", err)
 						fmt.Printf("[Library Verifier] ✗ Failed to extract metadata: %v\n", err)
 						continue
 					}
-
-					// Fallback: parse filename if metadata is empty
-					if metadata.Title == "" || metadata.Artist == "" {
-						filename := filepath.Base(track.FilePath)
-						filename = strings.TrimSuffix(filename, filepath.Ext(filename))
-
-						if strings.Contains(filename, " - ") {
-							parts := strings.SplitN(filename, " - ", 2)
-							if len(parts) == 2 {
-								if metadata.Title == "" {
-									metadata.Title = strings.TrimSpace(parts[0])
-								}
-								if metadata.Artist == "" {
-									metadata.Artist = strings.TrimSpace(parts[1])
-								}
-							}
-						}
-
-						if metadata.Title == "" {
-							metadata.Title = filename
-						}
-					}
-
-					// Try to get cover from database first (much faster)
-					var coverURL string
-					if req.DatabasePath != "" && metadata.Album != "" {
-						coverURL, err = GetAlbumCoverFromDatabase(req.DatabasePath, metadata.Album)
-						if err == nil && coverURL != "" {
-							fmt.Printf("[Library Verifier] ✓ Found cover in database by album\n")
-						}
-					}
-
-					// If not found by album, try searching by track name and artist
-					if coverURL == "" && req.DatabasePath != "" && metadata.Title != "" && metadata.Artist != "" {
-						coverURL, err = GetCoverByTrackFromDatabase(req.DatabasePath, metadata.Title, metadata.Artist)
-						if err == nil && coverURL != "" {
-							fmt.Printf("[Library Verifier] ✓ Found cover in database by track\n")
-						}
-					}
-
-					// If still not found in database, try external APIs
-					if coverURL == "" {
-						coverURL, err = SearchITunesForCover(metadata.Title, metadata.Artist)
-						if err == nil && coverURL != "" {
-							fmt.Printf("[Library Verifier] ✓ Found via iTunes\n")
-						}
+					if fingerprinted {
+						mu.Lock()
+						track.Fingerprinted = true
+						track.MBID = metadata.MBID
+						mu.Unlock()
 					}
 
-					if coverURL == "" {
-						coverURL, err = SearchDeezerForCover(metadata.Title, metadata.Artist)
-						if err == nil && coverURL != "" {
-							fmt.Printf("[Library Verifier] ✓ Found via Deezer\n")
-						}
+					// Walk the configured priority chain (embedded, sidecar, database, online)
+					resolution, _ := ResolveCover(CoverLookup{
+						AudioPath:    track.FilePath,
+						Title:        metadata.Title,
+						Artist:       metadata.Artist,
+						Album:        metadata.Album,
+						DatabasePath: req.DatabasePath,
+						SkipLocal:    track.LowResCover,
+					})
+
+					if track.LowResCover {
+						upgradeLowResCover(track, resolution, coverClient, req.MinCoverWidth, &mu, response)
+						continue
 					}
 
-					if coverURL == "" {
-						searchQuery := fmt.Sprintf("track:%s artist:%s", metadata.Title, metadata.Artist)
-						coverURL, err = SearchSpotifyForCover(searchQuery, metadata.Title, metadata.Artist)
-						if err == nil && coverURL != "" {
-							fmt.Printf("[Library Verifier] ✓ Found via Spotify\n")
-						}
-					}
+					if resolution == nil {
+						fmt.Printf("[Library Verifier] ✗ Cover not found from any source\n")
 
-					if coverURL == "" {
-						coverURL, err = SearchMusicBrainzForCover(metadata.Title, metadata.Artist)
-						if err == nil && coverURL != "" {
-							fmt.Printf("[Library Verifier] ✓ Found via MusicBrainz\n")
+						// Fall back to the user-supplied placeholder so the library stays consistent
+						if GetDefaultCover() != "" {
+							basePath := strings.TrimSuffix(track.FilePath, filepath.Ext(track.FilePath))
+							coverPath := basePath + strings.ToLower(filepath.Ext(GetDefaultCover()))
+							if err := copyDefaultCoverTo(coverPath); err == nil {
+								mu.Lock()
+								track.UsedDefaultCover = true
+								track.CoverPath = coverPath
+								response.MissingCoverTracks = append(response.MissingCoverTracks, track.FilePath)
+								mu.Unlock()
+								fmt.Printf("[Library Verifier] Using default cover for: %s\n", track.TrackName)
+								continue
+							}
 						}
-					}
 
-					if coverURL == "" {
+						mu.Lock()
 						track.Error = "Failed to find cover from any source"
-						fmt.Printf("[Library Verifier] ✗ Cover not found from any source\n")
+						response.MissingCoverTracks = append(response.MissingCoverTracks, track.FilePath)
+						mu.Unlock()
 						continue
 					}
 
-					// Download cover to same location as audio file
+					fmt.Printf("[Library Verifier] ✓ Found cover via %s\n", resolution.Origin)
+
+					// Save cover to same location as audio file
 					basePath := strings.TrimSuffix(track.FilePath, filepath.Ext(track.FilePath))
 					coverPath := basePath + ".jpg"
 
-					err = coverClient.DownloadCoverToPath(coverURL, coverPath, false)
+					if resolution.LocalPath != "" {
+						err = copyFile(resolution.LocalPath, coverPath)
+						if resolution.IsTemp {
+							os.Remove(resolution.LocalPath)
+						}
+					} else {
+						err = coverClient.DownloadCoverToPath(resolution.URL, coverPath, false)
+					}
 					if err != nil {
 						track.Error = fmt.Sprintf("Failed to download cover: %v", err)
 						fmt.Printf("[Library Verifier] ✗ Failed to download: %v\n", err)
@@ -299,6 +326,8 @@ func VerifyLibrary(req LibraryVerificationRequest) (*LibraryVerificationResponse
 					mu.Lock()
 					track.CoverDownloaded = true
 					track.CoverPath = coverPath
+					track.CoverSource = resolution.Origin
+					track.CoverQueryVariant = resolution.Variant
 					response.CoversDownloaded++
 					mu.Unlock()
 
@@ -309,6 +338,11 @@ func VerifyLibrary(req LibraryVerificationRequest) (*LibraryVerificationResponse
 
 		wg.Wait()
 		fmt.Printf("[Library Verifier] Cover download complete: %d covers downloaded\n", response.CoversDownloaded)
+
+		response.ThrottledSources = GetCoverSourceThrottles()
+		for _, throttle := range response.ThrottledSources {
+			fmt.Printf("[Library Verifier] %s rate limited %d times (%d pauses)\n", throttle.Source, throttle.RateLimited, throttle.Pauses)
+		}
 	}
 
 	// Download missing lyrics if requested
@@ -345,33 +379,17 @@ func VerifyLibrary(req LibraryVerificationRequest) (*LibraryVerificationResponse
 						workerID, current, response.MissingLyrics, track.TrackName)
 
 					// Extract metadata from audio file
-					metadata, err := ExtractMetadataFromFile(track.FilePath)
+					metadata, fingerprinted, err := lookupTrackMetadata(track.FilePath, req.UseFingerprint)
 					if err != nil {
 						track.Error = fmt.Sprintf("Failed to extract metadata: %v", err)
 						fmt.Printf("[Library Verifier] ✗ Failed to extract metadata: %v\n", err)
 						continue
 					}
-
-					// Fallback: parse filename if metadata is empty
-					if metadata.Title == "" || metadata.Artist == "" {
-						filename := filepath.Base(track.FilePath)
-						filename = strings.TrimSuffix(filename, filepath.Ext(filename))
-
-						if strings.Contains(filename, " - ") {
-							parts := strings.SplitN(filename, " - ", 2)
-							if len(parts) == 2 {
-								if metadata.Title == "" {
-									metadata.Title = strings.TrimSpace(parts[0])
-								}
-								if metadata.Artist == "" {
-									metadata.Artist = strings.TrimSpace(parts[1])
-								}
-							}
-						}
-
-						if metadata.Title == "" {
-							metadata.Title = filename
-						}
+					if fingerprinted {
+						mu.Lock()
+						track.Fingerprinted = true
+						track.MBID = metadata.MBID
+						mu.Unlock()
 					}
 
 					// Skip if we don't have at least track name
@@ -388,7 +406,11 @@ func VerifyLibrary(req LibraryVerificationRequest) (*LibraryVerificationResponse
 					}
 
 					// Convert to LRC format
-					lrcContent := lyricsClient.ConvertToLRC(lyricsResp, metadata.Title, metadata.Artist)
+					header := LRCHeader{Title: metadata.Title, Artist: metadata.Artist, Album: metadata.Album}
+					if duration, err := readAudioDuration(track.FilePath); err == nil {
+						header.DurationMs = int64(duration * 1000)
+					}
+					lrcContent := lyricsClient.ConvertToLRCWithHeader(lyricsResp, header)
 					if lrcContent == "" {
 						fmt.Printf("[Library Verifier] ✗ Failed to convert lyrics to LRC format\n")
 						continue
@@ -423,6 +445,57 @@ func VerifyLibrary(req LibraryVerificationRequest) (*LibraryVerificationResponse
 	return response, nil
 }
 
+// lookupTrackMetadata reads a file's tags and fills a missing title or artist from an
+// "Artist - Title" filename. When that yields no artist and useFingerprint is set, the file is
+// identified through AcoustID instead; the second result reports whether that match was used.
+func lookupTrackMetadata(filePath string, useFingerprint bool) (*Metadata, bool, error) {
+	metadata, err := ExtractMetadataFromFile(filePath)
+	if err != nil {
+		return nil, false, err
+	}
+	if metadata.Title != "" && metadata.Artist != "" {
+		return metadata, false, nil
+	}
+
+	// Fallback: parse filename if metadata is empty
+	filename := filepath.Base(filePath)
+	filename = strings.TrimSuffix(filename, filepath.Ext(filename))
+
+	if strings.Contains(filename, " - ") {
+		parts := strings.SplitN(filename, " - ", 2)
+		if len(parts) == 2 {
+			if metadata.Title == "" {
+				metadata.Title = strings.TrimSpace(parts[0])
+			}
+			if metadata.Artist == "" {
+				metadata.Artist = strings.TrimSpace(parts[1])
+			}
+		}
+	}
+
+	// Filename parsing found nothing usable: try the audio itself
+	if metadata.Artist == "" && useFingerprint {
+		matched, err := FingerprintFile(filePath)
+		if err == nil {
+			if metadata.Album == "" {
+				metadata.Album = matched.Album
+			}
+			metadata.Title = matched.Title
+			metadata.Artist = matched.Artist
+			metadata.MBID = matched.MBID
+			return metadata, true, nil
+		}
+		if !errors.Is(err, ErrFingerprintUnavailable) {
+			fmt.Printf("[Library Verifier] Fingerprint lookup failed: %v\n", err)
+		}
+	}
+
+	if metadata.Title == "" {
+		metadata.Title = filename
+	}
+	return metadata, false, nil
+}
+
 // ExtractMetadataFromFile extracts basic metadata from an audio file
 func ExtractMetadataFromFile(filePath string) (*Metadata, error) {
 	ext := strings.ToLower(filepath.Ext(filePath))
@@ -487,7 +560,7 @@ func extractMetadataFromFLAC(filePath string) (*Metadata, error) {
 				metadata.Title = vals[0]
 			}
 			if vals, err := cmt.Get(flacvorbis.FIELD_ARTIST); err == nil && len(vals) > 0 {
-				metadata.Artist = vals[0]
+				metadata.Artist = joinArtistValues(vals)
 			}
 			if vals, err := cmt.Get(flacvorbis.FIELD_ALBUM); err == nil && len(vals) > 0 {
 				metadata.Album = vals[0]
@@ -495,6 +568,15 @@ func extractMetadataFromFLAC(filePath string) (*Metadata, error) {
 			if vals, err := cmt.Get("ALBUMARTIST"); err == nil && len(vals) > 0 {
 				metadata.AlbumArtist = vals[0]
 			}
+			if vals, err := cmt.Get(flacvorbis.FIELD_TRACKNUMBER); err == nil && len(vals) > 0 {
+				metadata.TrackNumber, metadata.TotalTracks = parseTagNumber(vals[0])
+			}
+			for _, field := range []string{"TOTALTRACKS", "TRACKTOTAL"} {
+				if vals, err := cmt.Get(field); err == nil && len(vals) > 0 && metadata.TotalTracks == 0 {
+					metadata.TotalTracks, _ = strconv.Atoi(strings.TrimSpace(vals[0]))
+				}
+			}
+			metadata.DiscNumber, metadata.TotalDiscs = readFlacDiscTags(filePath)
 			break
 		}
 	}
@@ -524,10 +606,10 @@ func extractMetadataFromMP3(filePath string) (*Metadata, error) {
 	// Try to get track number
 	if trackStr := tag.GetTextFrame(tag.CommonID("Track number/Position in set")).Text; trackStr != "" {
 		// Handle "1/12" format
-		parts := strings.Split(trackStr, "/")
-		if trackNum, err := strconv.Atoi(parts[0]); err == nil {
-			metadata.TrackNumber = trackNum
-		}
+		metadata.TrackNumber, metadata.TotalTracks = parseTagNumber(trackStr)
+	}
+	if discStr := tag.GetTextFrame("TPOS").Text; discStr != "" {
+		metadata.DiscNumber, metadata.TotalDiscs = parseTagNumber(discStr)
 	}
 
 	return metadata, nil
@@ -553,3 +635,71 @@ func extractMetadataFromM4A(filePath string) (*Metadata, error) {
 		Title: nameWithoutExt,
 	}, nil
 }
+
+// upgradeLowResCover replaces a track's sidecar cover with a higher-resolution one. The new
+// art is fetched at max quality to a temp file and only kept if it is actually wider.
+func upgradeLowResCover(track *TrackVerificationResult, resolution *CoverResolution, coverClient *CoverClient, minWidth int, mu *sync.Mutex, response *LibraryVerificationResponse) {
+	if resolution == nil {
+		mu.Lock()
+		track.Error = "No higher-resolution cover found"
+		mu.Unlock()
+		fmt.Printf("[Library Verifier] ✗ No higher-resolution cover for: %s\n", track.TrackName)
+		return
+	}
+
+	basePath := strings.TrimSuffix(track.FilePath, filepath.Ext(track.FilePath))
+	tmpPath := basePath + ".upgrade.jpg"
+
+	var err error
+	if resolution.LocalPath != "" {
+		err = copyFile(resolution.LocalPath, tmpPath)
+		if resolution.IsTemp {
+			os.Remove(resolution.LocalPath)
+		}
+	} else {
+		err = coverClient.DownloadCoverToPath(resolution.URL, tmpPath, true)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		mu.Lock()
+		track.Error = fmt.Sprintf("Failed to download cover: %v", err)
+		mu.Unlock()
+		fmt.Printf("[Library Verifier] ✗ Failed to download: %v\n", err)
+		return
+	}
+
+	width, _, _, err := CheckCoverSquare(tmpPath)
+	if err != nil || width <= track.CoverWidth {
+		os.Remove(tmpPath)
+		mu.Lock()
+		track.Error = "No higher-resolution cover found"
+		mu.Unlock()
+		fmt.Printf("[Library Verifier] ✗ Fetched cover is not larger than %dpx: %s\n", track.CoverWidth, track.TrackName)
+		return
+	}
+
+	coverPath := basePath + ".jpg"
+	if err := os.Rename(tmpPath, coverPath); err != nil {
+		os.Remove(tmpPath)
+		mu.Lock()
+		track.Error = fmt.Sprintf("Failed to replace cover: %v", err)
+		mu.Unlock()
+		return
+	}
+	if track.CoverPath != coverPath {
+		os.Remove(track.CoverPath)
+	}
+
+	mu.Lock()
+	track.CoverUpgraded = true
+	track.CoverDownloaded = true
+	track.CoverPath = coverPath
+	track.CoverWidth = width
+	track.CoverSource = resolution.Origin
+	track.CoverQueryVariant = resolution.Variant
+	track.LowResCover = width < minWidth
+	response.CoversDownloaded++
+	mu.Unlock()
+
+	fmt.Printf("[Library Verifier] ✓ Cover upgraded to %dpx via %s\n", width, resolution.Origin)
+}
@@ -1,6 +1,7 @@
 package backend
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -11,7 +12,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
-	"time"
+	"sync"
 )
 
 // LRCLibResponse represents the LRCLIB API response
@@ -55,13 +56,23 @@ type LyricsDownloadRequest struct {
 	Position            int    `json:"position"`
 	UseAlbumTrackNumber bool   `json:"use_album_track_number"`
 	DiscNumber          int    `json:"disc_number"`
+	LyricsFormat        string `json:"lyrics_format,omitempty"` // "lrc" (default), "srt" or "both"
+	Duration            int    `json:"duration,omitempty"`      // Track duration in seconds, for the LRC [length:] tag
 }
 
+// Lyrics sidecar formats
+const (
+	LyricsFormatLRC  = "lrc"
+	LyricsFormatSRT  = "srt"
+	LyricsFormatBoth = "both"
+)
+
 // LyricsDownloadResponse represents the response from lyrics download
 type LyricsDownloadResponse struct {
 	Success       bool   `json:"success"`
 	Message       string `json:"message"`
 	File          string `json:"file,omitempty"`
+	SRTFile       string `json:"srt_file,omitempty"`
 	Error         string `json:"error,omitempty"`
 	AlreadyExists bool   `json:"already_exists,omitempty"`
 }
@@ -73,13 +84,28 @@ type LyricsClient struct {
 
 // NewLyricsClient creates a new lyrics client
 func NewLyricsClient() *LyricsClient {
+	sourceTimeout, _, _ := getLyricsFetchLimits()
 	return &LyricsClient{
-		httpClient: &http.Client{Timeout: 15 * time.Second},
+		httpClient: &http.Client{Timeout: sourceTimeout},
+	}
+}
+
+// get issues a GET that is abandoned when ctx is done
+func (c *LyricsClient) get(ctx context.Context, apiURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return nil, err
 	}
+	return c.httpClient.Do(req)
 }
 
 // FetchLyricsWithMetadata fetches lyrics using track name and artist from LRCLIB
 func (c *LyricsClient) FetchLyricsWithMetadata(trackName, artistName string) (*LyricsResponse, error) {
+	return c.FetchLyricsWithMetadataContext(context.Background(), trackName, artistName)
+}
+
+// FetchLyricsWithMetadataContext is FetchLyricsWithMetadata bounded by ctx
+func (c *LyricsClient) FetchLyricsWithMetadataContext(ctx context.Context, trackName, artistName string) (*LyricsResponse, error) {
 	// Try LRCLIB API
 	apiBase, _ := base64.StdEncoding.DecodeString("aHR0cHM6Ly9scmNsaWIubmV0L2FwaS9nZXQ/YXJ0aXN0X25hbWU9")
 	apiURL := fmt.Sprintf("%s%s&track_name=%s",
@@ -87,7 +113,7 @@ func (c *LyricsClient) FetchLyricsWithMetadata(trackName, artistName string) (*L
 		url.QueryEscape(artistName),
 		url.QueryEscape(trackName))
 
-	resp, err := c.httpClient.Get(apiURL)
+	resp, err := c.get(ctx, apiURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch from LRCLIB: %v", err)
 	}
@@ -179,11 +205,16 @@ func lrcTimestampToMs(timestamp string) int64 {
 
 // FetchLyricsFromLRCLibSearch fetches lyrics using LRCLIB search API
 func (c *LyricsClient) FetchLyricsFromLRCLibSearch(trackName, artistName string) (*LyricsResponse, error) {
+	return c.FetchLyricsFromLRCLibSearchContext(context.Background(), trackName, artistName)
+}
+
+// FetchLyricsFromLRCLibSearchContext is FetchLyricsFromLRCLibSearch bounded by ctx
+func (c *LyricsClient) FetchLyricsFromLRCLibSearchContext(ctx context.Context, trackName, artistName string) (*LyricsResponse, error) {
 	query := fmt.Sprintf("%s %s", artistName, trackName)
 	apiBase, _ := base64.StdEncoding.DecodeString("aHR0cHM6Ly9scmNsaWIubmV0L2FwaS9zZWFyY2g/cT0=")
 	apiURL := fmt.Sprintf("%s%s", string(apiBase), url.QueryEscape(query))
 
-	resp, err := c.httpClient.Get(apiURL)
+	resp, err := c.get(ctx, apiURL)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %v", err)
 	}
@@ -239,17 +270,26 @@ func simplifyTrackName(name string) string {
 	return name
 }
 
-// FetchLyricsAllSources tries all LRCLIB sources to get lyrics
+// FetchLyricsAllSources tries all LRCLIB sources to get lyrics, then Genius. Each source gets its own timeout
+// and transient-error retries, and the whole lookup is bounded by the overall lyrics deadline.
 func (c *LyricsClient) FetchLyricsAllSources(spotifyID, trackName, artistName string) (*LyricsResponse, string, error) {
+	_, totalTimeout, _ := getLyricsFetchLimits()
+	ctx, cancel := context.WithTimeout(context.Background(), totalTimeout)
+	defer cancel()
+
 	// 1. Try LRCLIB exact match
-	resp, err := c.FetchLyricsWithMetadata(trackName, artistName)
+	resp, err := fetchLyricsSource(ctx, func(ctx context.Context) (*LyricsResponse, error) {
+		return c.FetchLyricsWithMetadataContext(ctx, trackName, artistName)
+	})
 	if err == nil && resp != nil && !resp.Error && len(resp.Lines) > 0 {
 		return resp, "LRCLIB", nil
 	}
 	fmt.Printf("   LRCLIB exact: %v\n", err)
 
 	// 2. Try LRCLIB search
-	resp, err = c.FetchLyricsFromLRCLibSearch(trackName, artistName)
+	resp, err = fetchLyricsSource(ctx, func(ctx context.Context) (*LyricsResponse, error) {
+		return c.FetchLyricsFromLRCLibSearchContext(ctx, trackName, artistName)
+	})
 	if err == nil && resp != nil && !resp.Error && len(resp.Lines) > 0 {
 		return resp, "LRCLIB Search", nil
 	}
@@ -257,38 +297,114 @@ func (c *LyricsClient) FetchLyricsAllSources(spotifyID, trackName, artistName st
 
 	// 3. Try with simplified track name (remove parentheses, subtitles)
 	simplifiedTrack := simplifyTrackName(trackName)
-	if simplifiedTrack != trackName {
+	if simplifiedTrack != trackName && ctx.Err() == nil {
 		fmt.Printf("   Trying simplified name: %s\n", simplifiedTrack)
 
-		resp, err = c.FetchLyricsWithMetadata(simplifiedTrack, artistName)
+		resp, err = fetchLyricsSource(ctx, func(ctx context.Context) (*LyricsResponse, error) {
+			return c.FetchLyricsWithMetadataContext(ctx, simplifiedTrack, artistName)
+		})
 		if err == nil && resp != nil && !resp.Error && len(resp.Lines) > 0 {
 			return resp, "LRCLIB (simplified)", nil
 		}
 
-		resp, err = c.FetchLyricsFromLRCLibSearch(simplifiedTrack, artistName)
+		resp, err = fetchLyricsSource(ctx, func(ctx context.Context) (*LyricsResponse, error) {
+			return c.FetchLyricsFromLRCLibSearchContext(ctx, simplifiedTrack, artistName)
+		})
 		if err == nil && resp != nil && !resp.Error && len(resp.Lines) > 0 {
 			return resp, "LRCLIB Search (simplified)", nil
 		}
 	}
 
+	// 4. Genius last: it often has obscure tracks, but only as plain lyrics
+	if ctx.Err() == nil {
+		resp, err = fetchLyricsSource(ctx, func(ctx context.Context) (*LyricsResponse, error) {
+			return c.FetchLyricsFromGeniusContext(ctx, trackName, artistName)
+		})
+		if err == nil && resp != nil && !resp.Error && len(resp.Lines) > 0 {
+			return resp, "Genius", nil
+		}
+		if err == nil {
+			err = fmt.Errorf("no lyrics returned")
+		}
+		fmt.Printf("   Genius: %v\n", err)
+	}
+
+	if ctx.Err() != nil {
+		return nil, "", fmt.Errorf("lyrics lookup timed out after %s", totalTimeout)
+	}
 	return nil, "", fmt.Errorf("lyrics not found in any source")
 }
 
+// LRCHeader is the track metadata written as ID tags at the top of an LRC file
+type LRCHeader struct {
+	Title      string
+	Artist     string
+	Album      string
+	DurationMs int64
+}
+
+var (
+	lrcMetadataTags     = true
+	lrcMetadataTagsLock sync.RWMutex
+)
+
+// SetLRCMetadataTags sets whether LRC output starts with [ti:], [ar:], [al:], [by:] and [length:] tags
+func SetLRCMetadataTags(enabled bool) {
+	lrcMetadataTagsLock.Lock()
+	lrcMetadataTags = enabled
+	lrcMetadataTagsLock.Unlock()
+}
+
+func isLRCMetadataTagsEnabled() bool {
+	lrcMetadataTagsLock.RLock()
+	defer lrcMetadataTagsLock.RUnlock()
+	return lrcMetadataTags
+}
+
+// lrcTagValue keeps a tag value on one line and free of the bracket that would end the tag
+func lrcTagValue(value string) string {
+	value = strings.Join(strings.Fields(value), " ")
+	return strings.ReplaceAll(value, "]", ")")
+}
+
 // ConvertToLRC converts lyrics response to LRC format
 func (c *LyricsClient) ConvertToLRC(lyrics *LyricsResponse, trackName, artistName string) string {
+	return c.ConvertToLRCWithHeader(lyrics, LRCHeader{Title: trackName, Artist: artistName})
+}
+
+// ConvertToLRCWithHeader converts lyrics response to LRC format, starting with ID tags for
+// whichever header fields are known unless LRC metadata tags are turned off
+func (c *LyricsClient) ConvertToLRCWithHeader(lyrics *LyricsResponse, header LRCHeader) string {
 	var sb strings.Builder
 
 	// Add metadata
-	sb.WriteString(fmt.Sprintf("[ti:%s]\n", trackName))
-	sb.WriteString(fmt.Sprintf("[ar:%s]\n", artistName))
-	sb.WriteString("[by:SpotiFlac]\n")
-	sb.WriteString("\n")
+	if isLRCMetadataTagsEnabled() {
+		if header.Title != "" {
+			sb.WriteString(fmt.Sprintf("[ti:%s]\n", lrcTagValue(header.Title)))
+		}
+		if header.Artist != "" {
+			sb.WriteString(fmt.Sprintf("[ar:%s]\n", lrcTagValue(header.Artist)))
+		}
+		if header.Album != "" {
+			sb.WriteString(fmt.Sprintf("[al:%s]\n", lrcTagValue(header.Album)))
+		}
+		sb.WriteString("[by:SpotiFLAC]\n")
+		if header.DurationMs > 0 {
+			totalSeconds := header.DurationMs / 1000
+			sb.WriteString(fmt.Sprintf("[length:%02d:%02d]\n", totalSeconds/60, totalSeconds%60))
+		}
+		sb.WriteString("\n")
+	}
 
-	// Add lyrics lines
+	// Add lyrics lines; unsynced lyrics are written as plain lines without timestamps
 	for _, line := range lyrics.Lines {
 		if line.Words == "" {
 			continue
 		}
+		if lyrics.SyncType == "UNSYNCED" {
+			sb.WriteString(line.Words + "\n")
+			continue
+		}
 
 		// Convert milliseconds to LRC timestamp format [mm:ss.xx]
 		timestamp := msToLRCTimestamp(line.StartTimeMs)
@@ -298,6 +414,59 @@ func (c *LyricsClient) ConvertToLRC(lyrics *LyricsResponse, trackName, artistNam
 	return sb.String()
 }
 
+// ConvertToSRT converts synced lyrics to SRT subtitles. Each line lasts until the next one
+// starts. Returns empty for unsynced lyrics since they have no timing.
+func ConvertToSRT(lyrics *LyricsResponse) string {
+	if lyrics == nil || lyrics.SyncType != "LINE_SYNCED" {
+		return ""
+	}
+
+	type timedLine struct {
+		start int64
+		end   int64
+		words string
+	}
+
+	lines := make([]timedLine, 0, len(lyrics.Lines))
+	for _, line := range lyrics.Lines {
+		var start, end int64
+		fmt.Sscanf(line.StartTimeMs, "%d", &start)
+		fmt.Sscanf(line.EndTimeMs, "%d", &end)
+		lines = append(lines, timedLine{start: start, end: end, words: line.Words})
+	}
+
+	var sb strings.Builder
+	index := 1
+	for i, line := range lines {
+		if line.words == "" {
+			continue
+		}
+
+		end := line.end
+		if end <= line.start {
+			if i+1 < len(lines) && lines[i+1].start > line.start {
+				end = lines[i+1].start
+			} else {
+				end = line.start + 4000
+			}
+		}
+
+		sb.WriteString(fmt.Sprintf("%d\n%s --> %s\n%s\n\n", index, msToSRTTimestamp(line.start), msToSRTTimestamp(end), line.words))
+		index++
+	}
+
+	return sb.String()
+}
+
+// msToSRTTimestamp converts milliseconds to SRT timestamp format hh:mm:ss,mmm
+func msToSRTTimestamp(ms int64) string {
+	hours := ms / 3600000
+	minutes := (ms % 3600000) / 60000
+	seconds := (ms % 60000) / 1000
+	millis := ms % 1000
+	return fmt.Sprintf("%02d:%02d:%02d,%03d", hours, minutes, seconds, millis)
+}
+
 // msToLRCTimestamp converts milliseconds string to LRC timestamp format [mm:ss.xx]
 func msToLRCTimestamp(msStr string) string {
 	var ms int64
@@ -402,15 +571,40 @@ func (c *LyricsClient) DownloadLyrics(req LyricsDownloadRequest) (*LyricsDownloa
 	}
 	filename := buildLyricsFilename(req.TrackName, req.ArtistName, req.AlbumName, req.AlbumArtist, req.ReleaseDate, filenameFormat, req.TrackNumber, req.Position, req.DiscNumber)
 	filePath := filepath.Join(outputDir, filename)
+	srtPath := strings.TrimSuffix(filePath, ".lrc") + ".srt"
+
+	lyricsFormat := strings.ToLower(req.LyricsFormat)
+	if lyricsFormat != LyricsFormatSRT && lyricsFormat != LyricsFormatBoth {
+		lyricsFormat = LyricsFormatLRC
+	}
+	wantLRC := lyricsFormat == LyricsFormatLRC || lyricsFormat == LyricsFormatBoth
+	wantSRT := lyricsFormat == LyricsFormatSRT || lyricsFormat == LyricsFormatBoth
 
 	// Check if file already exists
+	lrcExists := false
 	if fileInfo, err := os.Stat(filePath); err == nil && fileInfo.Size() > 0 {
-		return &LyricsDownloadResponse{
+		lrcExists = true
+	}
+	srtExists := false
+	if fileInfo, err := os.Stat(srtPath); err == nil && fileInfo.Size() > 0 {
+		srtExists = true
+	}
+	if (!wantLRC || lrcExists) && (!wantSRT || srtExists) {
+		resp := &LyricsDownloadResponse{
 			Success:       true,
 			Message:       "Lyrics file already exists",
-			File:          filePath,
 			AlreadyExists: true,
-		}, nil
+		}
+		if wantLRC {
+			resp.File = filePath
+		}
+		if wantSRT {
+			resp.SRTFile = srtPath
+			if resp.File == "" {
+				resp.File = srtPath
+			}
+		}
+		return resp, nil
 	}
 
 	// Fetch lyrics from LRCLIB
@@ -422,20 +616,54 @@ func (c *LyricsClient) DownloadLyrics(req LyricsDownloadRequest) (*LyricsDownloa
 		}, err
 	}
 
-	// Convert to LRC format
-	lrcContent := c.ConvertToLRC(lyrics, req.TrackName, req.ArtistName)
+	resp := &LyricsDownloadResponse{
+		Success: true,
+		Message: "Lyrics downloaded successfully",
+	}
 
-	// Write LRC file
-	if err := os.WriteFile(filePath, []byte(lrcContent), 0644); err != nil {
-		return &LyricsDownloadResponse{
-			Success: false,
-			Error:   fmt.Sprintf("failed to write LRC file: %v", err),
-		}, err
+	// SRT needs timing, so unsynced lyrics fall back to LRC
+	var srtContent string
+	if wantSRT {
+		srtContent = ConvertToSRT(lyrics)
+		if srtContent == "" {
+			fmt.Println("Lyrics are not synced, writing LRC instead of SRT")
+			wantLRC = true
+		}
 	}
 
-	return &LyricsDownloadResponse{
-		Success: true,
-		Message: "Lyrics downloaded successfully",
-		File:    filePath,
-	}, nil
+	if wantLRC && !lrcExists {
+		// Convert to LRC format
+		lrcContent := c.ConvertToLRCWithHeader(lyrics, LRCHeader{
+			Title:      req.TrackName,
+			Artist:     req.ArtistName,
+			Album:      req.AlbumName,
+			DurationMs: int64(req.Duration) * 1000,
+		})
+
+		// Write LRC file
+		if err := os.WriteFile(filePath, []byte(lrcContent), 0644); err != nil {
+			return &LyricsDownloadResponse{
+				Success: false,
+				Error:   fmt.Sprintf("failed to write LRC file: %v", err),
+			}, err
+		}
+	}
+	if wantLRC {
+		resp.File = filePath
+	}
+
+	if srtContent != "" {
+		if err := os.WriteFile(srtPath, []byte(srtContent), 0644); err != nil {
+			return &LyricsDownloadResponse{
+				Success: false,
+				Error:   fmt.Sprintf("failed to write SRT file: %v", err),
+			}, err
+		}
+		resp.SRTFile = srtPath
+		if resp.File == "" {
+			resp.File = srtPath
+		}
+	}
+
+	return resp, nil
 }
@@ -0,0 +1,212 @@
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	geniusSearchURL = "https://genius.com/api/search/song?per_page=5&q="
+	// Genius has no published limit for its web API; spacing requests out avoids 429s during batches
+	geniusMinInterval = time.Second
+)
+
+var (
+	geniusLastCall time.Time
+	geniusCallLock sync.Mutex
+
+	// geniusContainerStart marks each block of lyrics on a song page
+	geniusContainerStart = regexp.MustCompile(`<div[^>]*data-lyrics-container="true"[^>]*>`)
+	geniusLineBreak      = regexp.MustCompile(`(?i)<br\s*/?>`)
+	geniusTag            = regexp.MustCompile(`<[^>]+>`)
+	// geniusSectionHeader matches "[Chorus]" style headers, which aren't sung lines
+	geniusSectionHeader = regexp.MustCompile(`^\[[^\]]*\]$`)
+)
+
+type geniusSearchResponse struct {
+	Response struct {
+		Sections []struct {
+			Hits []struct {
+				Result struct {
+					Title         string `json:"title"`
+					URL           string `json:"url"`
+					Instrumental  bool   `json:"instrumental"`
+					PrimaryArtist struct {
+						Name string `json:"name"`
+					} `json:"primary_artist"`
+				} `json:"result"`
+			} `json:"hits"`
+		} `json:"sections"`
+	} `json:"response"`
+}
+
+// geniusGet issues a rate-limited GET to Genius, backing off and retrying once on a 429
+func (c *LyricsClient) geniusGet(ctx context.Context, pageURL string) (*http.Response, error) {
+	resp, err := c.geniusRequest(ctx, pageURL)
+	if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+		return resp, err
+	}
+
+	wait := parseRetryAfter(resp.Header.Get("Retry-After"))
+	resp.Body.Close()
+	fmt.Printf("[Genius] Rate limited, retrying in %s\n", wait)
+	if err := sleepWithContext(ctx, wait); err != nil {
+		return nil, err
+	}
+	return c.geniusRequest(ctx, pageURL)
+}
+
+// geniusRequest sends one GET, spaced at least geniusMinInterval after the previous one
+func (c *LyricsClient) geniusRequest(ctx context.Context, pageURL string) (*http.Response, error) {
+	geniusCallLock.Lock()
+	if wait := geniusMinInterval - time.Since(geniusLastCall); wait > 0 {
+		select {
+		case <-ctx.Done():
+			geniusCallLock.Unlock()
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+	geniusLastCall = time.Now()
+	geniusCallLock.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+	return c.httpClient.Do(req)
+}
+
+// searchGeniusSong returns the song page URL of the first hit whose artist and title match
+func (c *LyricsClient) searchGeniusSong(ctx context.Context, trackName, artistName string) (string, error) {
+	// Without an artist any song of the same title would match
+	artist := firstArtist(artistName)
+	wantArtist := normalizeMatchKey(artist)
+	if wantArtist == "" {
+		return "", fmt.Errorf("artist is required for a Genius search")
+	}
+
+	resp, err := c.geniusGet(ctx, geniusSearchURL+url.QueryEscape(artist+" "+trackName))
+	if err != nil {
+		return "", fmt.Errorf("Genius search failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("Genius search returned status %d", resp.StatusCode)
+	}
+
+	var search geniusSearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&search); err != nil {
+		return "", fmt.Errorf("failed to parse Genius search: %v", err)
+	}
+
+	// Genius titles often drop "(Remastered)" style suffixes, so compare simplified names too
+	wantTitles := []string{normalizeMatchKey(trackName), normalizeMatchKey(simplifyTrackName(trackName))}
+	for _, section := range search.Response.Sections {
+		for _, hit := range section.Hits {
+			result := hit.Result
+			if result.URL == "" || result.Instrumental {
+				continue
+			}
+			gotArtist := normalizeMatchKey(result.PrimaryArtist.Name)
+			if gotArtist == "" || (!strings.Contains(gotArtist, wantArtist) && !strings.Contains(wantArtist, gotArtist)) {
+				continue
+			}
+			gotTitle := normalizeMatchKey(simplifyTrackName(result.Title))
+			for _, want := range wantTitles {
+				if want != "" && (gotTitle == want || normalizeMatchKey(result.Title) == want) {
+					return result.URL, nil
+				}
+			}
+		}
+	}
+	return "", fmt.Errorf("no results found on Genius")
+}
+
+// extractGeniusLyrics pulls the lyrics text out of the data-lyrics-container blocks of a song page
+func extractGeniusLyrics(page string) string {
+	var lines []string
+	for _, loc := range geniusContainerStart.FindAllStringIndex(page, -1) {
+		block := geniusContainerBlock(page[loc[1]:])
+		block = geniusLineBreak.ReplaceAllString(block, "\n")
+		block = html.UnescapeString(geniusTag.ReplaceAllString(block, ""))
+		for _, line := range strings.Split(block, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || geniusSectionHeader.MatchString(line) {
+				continue
+			}
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// geniusContainerBlock returns the inner HTML up to the </div> closing the container, counting
+// nested divs so annotation wrappers inside the lyrics don't cut it short
+func geniusContainerBlock(rest string) string {
+	depth := 1
+	for i := 0; i < len(rest); {
+		next := strings.Index(rest[i:], "<")
+		if next < 0 {
+			break
+		}
+		i += next
+		switch {
+		case strings.HasPrefix(rest[i:], "</div"):
+			depth--
+			if depth == 0 {
+				return rest[:i]
+			}
+		case strings.HasPrefix(rest[i:], "<div"):
+			depth++
+		}
+		i++
+	}
+	return rest
+}
+
+// FetchLyricsFromGeniusContext searches Genius for the track and scrapes the song page.
+// Genius only has plain lyrics, so the result is always UNSYNCED.
+func (c *LyricsClient) FetchLyricsFromGeniusContext(ctx context.Context, trackName, artistName string) (*LyricsResponse, error) {
+	songURL, err := c.searchGeniusSong(ctx, trackName, artistName)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.geniusGet(ctx, songURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch Genius page: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("Genius page returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Genius page: %v", err)
+	}
+
+	text := extractGeniusLyrics(string(body))
+	if text == "" {
+		return nil, fmt.Errorf("no lyrics found on Genius page")
+	}
+
+	result := &LyricsResponse{SyncType: "UNSYNCED"}
+	for _, line := range strings.Split(text, "\n") {
+		result.Lines = append(result.Lines, LyricsLine{StartTimeMs: "0", Words: line})
+	}
+	return result, nil
+}
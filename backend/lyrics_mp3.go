@@ -0,0 +1,276 @@
+package backend
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	id3v2 "github.com/bogem/id3v2/v2"
+)
+
+// ID3v2 text encodings used in SYLT frames
+const (
+	syltEncodingISO88591 = 0
+	syltEncodingUTF16    = 1
+	syltEncodingUTF16BE  = 2
+	syltEncodingUTF8     = 3
+)
+
+const (
+	syltTimestampMs     = 2 // Absolute time in milliseconds
+	syltContentLyrics   = 1
+	syltFrameID         = "SYLT"
+	usltCommonFrameName = "Unsynchronised lyrics/text transcription"
+)
+
+// EmbedSyncedLyricsMP3 writes line-synced lyrics as an ID3v2 SYLT frame, plus a plain USLT
+// frame for players that only read unsynced lyrics. Unsynced lyrics are written as USLT only.
+func EmbedSyncedLyricsMP3(filePath string, resp *LyricsResponse) error {
+	if resp == nil || len(resp.Lines) == 0 {
+		return fmt.Errorf("no lyrics to embed")
+	}
+
+	plain := make([]string, 0, len(resp.Lines))
+	for _, line := range resp.Lines {
+		plain = append(plain, line.Words)
+	}
+	plainText := strings.Join(plain, "\n")
+
+	if resp.SyncType != "LINE_SYNCED" {
+		return EmbedLyricsOnlyMP3(filePath, plainText)
+	}
+
+	tag, err := id3v2.Open(filePath, id3v2.Options{Parse: true})
+	if err != nil {
+		return fmt.Errorf("failed to open MP3 file: %w", err)
+	}
+	defer tag.Close()
+
+	// UTF-8 is only valid in ID3v2.4; older tags get UTF-16 with BOM
+	encoding := byte(syltEncodingUTF8)
+	if tag.Version() < 4 {
+		encoding = syltEncodingUTF16
+	}
+
+	body, err := buildSYLTBody(resp.Lines, encoding)
+	if err != nil {
+		return err
+	}
+
+	tag.DeleteFrames(syltFrameID)
+	tag.AddFrame(syltFrameID, id3v2.UnknownFrame{Body: body})
+
+	tag.DeleteFrames(tag.CommonID(usltCommonFrameName))
+	tag.AddUnsynchronisedLyricsFrame(id3v2.UnsynchronisedLyricsFrame{
+		Encoding:          id3v2.EncodingUTF8,
+		Language:          "eng",
+		ContentDescriptor: "",
+		Lyrics:            plainText,
+	})
+
+	if err := tag.Save(); err != nil {
+		return fmt.Errorf("failed to save MP3 tags: %w", err)
+	}
+
+	fmt.Printf("[Lyrics] Embedded %d synced lines into: %s\n", len(resp.Lines), filePath)
+	return nil
+}
+
+// ReadSyncedLyricsMP3 reads the SYLT frame from an MP3 file back into a LyricsResponse
+func ReadSyncedLyricsMP3(filePath string) (*LyricsResponse, error) {
+	tag, err := id3v2.Open(filePath, id3v2.Options{Parse: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open MP3 file: %w", err)
+	}
+	defer tag.Close()
+
+	for _, frame := range tag.GetFrames(syltFrameID) {
+		unknown, ok := frame.(id3v2.UnknownFrame)
+		if !ok {
+			continue
+		}
+		lines, err := parseSYLTBody(unknown.Body)
+		if err != nil {
+			return nil, err
+		}
+		if len(lines) > 0 {
+			return &LyricsResponse{SyncType: "LINE_SYNCED", Lines: lines}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no synced lyrics found")
+}
+
+// buildSYLTBody encodes lines as: encoding, language, timestamp format, content type,
+// empty descriptor, then (text, terminator, 4-byte timestamp) per line
+func buildSYLTBody(lines []LyricsLine, encoding byte) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte(encoding)
+	buf.WriteString("eng")
+	buf.WriteByte(syltTimestampMs)
+	buf.WriteByte(syltContentLyrics)
+	buf.Write(encodeSYLTText("", encoding))
+
+	written := 0
+	for _, line := range lines {
+		ms, err := strconv.ParseInt(line.StartTimeMs, 10, 64)
+		if err != nil || ms < 0 {
+			continue
+		}
+		buf.Write(encodeSYLTText(line.Words, encoding))
+		var ts [4]byte
+		binary.BigEndian.PutUint32(ts[:], uint32(ms))
+		buf.Write(ts[:])
+		written++
+	}
+
+	if written == 0 {
+		return nil, fmt.Errorf("no timestamped lines to embed")
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeSYLTText encodes a string with its terminator in the given ID3v2 text encoding
+func encodeSYLTText(s string, encoding byte) []byte {
+	switch encoding {
+	case syltEncodingUTF16:
+		units := utf16.Encode([]rune(s))
+		out := make([]byte, 0, 2+len(units)*2+2)
+		out = append(out, 0xFF, 0xFE) // Little-endian BOM
+		for _, u := range units {
+			out = append(out, byte(u), byte(u>>8))
+		}
+		return append(out, 0, 0)
+	default:
+		return append([]byte(s), 0)
+	}
+}
+
+// parseSYLTBody decodes a SYLT frame body into lyrics lines
+func parseSYLTBody(body []byte) ([]LyricsLine, error) {
+	if len(body) < 6 {
+		return nil, fmt.Errorf("SYLT frame too short")
+	}
+
+	encoding := body[0]
+	if body[4] != syltTimestampMs {
+		return nil, fmt.Errorf("unsupported SYLT timestamp format: %d", body[4])
+	}
+
+	rest := body[6:]
+	// Skip content descriptor
+	_, rest, ok := readSYLTText(rest, encoding)
+	if !ok {
+		return nil, fmt.Errorf("malformed SYLT descriptor")
+	}
+
+	var lines []LyricsLine
+	for len(rest) > 0 {
+		text, remaining, ok := readSYLTText(rest, encoding)
+		if !ok || len(remaining) < 4 {
+			break
+		}
+		ms := binary.BigEndian.Uint32(remaining[:4])
+		rest = remaining[4:]
+
+		lines = append(lines, LyricsLine{
+			StartTimeMs: strconv.FormatUint(uint64(ms), 10),
+			Words:       strings.TrimPrefix(text, "\n"),
+		})
+	}
+
+	// Fill end times from the next line's start
+	for i := 0; i < len(lines)-1; i++ {
+		lines[i].EndTimeMs = lines[i+1].StartTimeMs
+	}
+	return lines, nil
+}
+
+// readSYLTText reads one terminated string and returns it with the remaining bytes
+func readSYLTText(data []byte, encoding byte) (string, []byte, bool) {
+	switch encoding {
+	case syltEncodingUTF16, syltEncodingUTF16BE:
+		for i := 0; i+1 < len(data); i += 2 {
+			if data[i] == 0 && data[i+1] == 0 {
+				return decodeUTF16Text(data[:i], encoding == syltEncodingUTF16BE), data[i+2:], true
+			}
+		}
+		return "", nil, false
+	default:
+		idx := bytes.IndexByte(data, 0)
+		if idx < 0 {
+			return "", nil, false
+		}
+		text := data[:idx]
+		if encoding == syltEncodingISO88591 {
+			runes := make([]rune, len(text))
+			for i, b := range text {
+				runes[i] = rune(b)
+			}
+			return string(runes), data[idx+1:], true
+		}
+		return string(text), data[idx+1:], true
+	}
+}
+
+// decodeUTF16Text decodes UTF-16 honoring a leading BOM
+func decodeUTF16Text(data []byte, bigEndian bool) string {
+	if len(data) >= 2 {
+		if data[0] == 0xFF && data[1] == 0xFE {
+			bigEndian = false
+			data = data[2:]
+		} else if data[0] == 0xFE && data[1] == 0xFF {
+			bigEndian = true
+			data = data[2:]
+		}
+	}
+
+	units := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		if bigEndian {
+			units = append(units, uint16(data[i])<<8|uint16(data[i+1]))
+		} else {
+			units = append(units, uint16(data[i+1])<<8|uint16(data[i]))
+		}
+	}
+	return string(utf16.Decode(units))
+}
+
+// lrcLinePattern matches a timestamped LRC line such as "[01:23.45]words"
+var lrcLinePattern = regexp.MustCompile(`^\[(\d+:\d+(?:\.\d+)?)\](.*)$`)
+
+// parseLRCText turns LRC text back into a LyricsResponse. Text without any timestamps
+// comes back as unsynced lines.
+func parseLRCText(lrc string) *LyricsResponse {
+	resp := &LyricsResponse{SyncType: "LINE_SYNCED"}
+	synced := false
+
+	for _, raw := range strings.Split(strings.ReplaceAll(lrc, "\r\n", "\n"), "\n") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		if match := lrcLinePattern.FindStringSubmatch(raw); match != nil {
+			synced = true
+			resp.Lines = append(resp.Lines, LyricsLine{
+				StartTimeMs: strconv.FormatInt(lrcTimestampToMs(match[1]), 10),
+				Words:       strings.TrimSpace(match[2]),
+			})
+			continue
+		}
+		// Skip ID tags like [ar:...] / [ti:...]
+		if strings.HasPrefix(raw, "[") && strings.HasSuffix(raw, "]") {
+			continue
+		}
+		resp.Lines = append(resp.Lines, LyricsLine{StartTimeMs: "0", Words: raw})
+	}
+
+	if !synced {
+		resp.SyncType = "UNSYNCED"
+	}
+	return resp
+}
@@ -0,0 +1,77 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Lyrics embed decisions reported for each enriched track
+const (
+	LyricsDecisionEmbeddedSynced = "embedded_synced"
+	LyricsDecisionEmbeddedPlain  = "embedded_plain"
+	LyricsDecisionSkippedPlain   = "skipped_plain"         // synced-only mode, only plain lyrics found
+	LyricsDecisionPlainSidecar   = "skipped_plain_sidecar" // as above, plain text written to a .txt sidecar
+)
+
+// LyricsDecision records what happened to a track's lyrics during enrichment
+type LyricsDecision struct {
+	FilePath string `json:"file_path"`
+	Decision string `json:"decision"`
+	Sidecar  string `json:"sidecar,omitempty"`
+}
+
+var (
+	lyricsDecisions     []LyricsDecision
+	lyricsDecisionsLock sync.Mutex
+)
+
+// isSyncedLyrics reports whether lyrics carry line timings
+func isSyncedLyrics(resp *LyricsResponse) bool {
+	return resp != nil && resp.SyncType == "LINE_SYNCED"
+}
+
+// plainLyricsText joins the lyric lines without any timing
+func plainLyricsText(resp *LyricsResponse) string {
+	var lines []string
+	for _, line := range resp.Lines {
+		if line.Words != "" {
+			lines = append(lines, line.Words)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// writePlainLyricsSidecar writes unsynced lyrics next to the audio file as .txt
+func writePlainLyricsSidecar(audioPath, text string) (string, error) {
+	sidecarPath := strings.TrimSuffix(audioPath, filepath.Ext(audioPath)) + ".txt"
+	if err := os.WriteFile(sidecarPath, []byte(text+"\n"), 0644); err != nil {
+		return "", fmt.Errorf("failed to write lyrics sidecar: %v", err)
+	}
+	return sidecarPath, nil
+}
+
+func recordLyricsDecision(decision LyricsDecision) {
+	lyricsDecisionsLock.Lock()
+	lyricsDecisions = append(lyricsDecisions, decision)
+	lyricsDecisionsLock.Unlock()
+}
+
+// GetLyricsDecisions returns what was done with each enriched track's lyrics
+func GetLyricsDecisions() []LyricsDecision {
+	lyricsDecisionsLock.Lock()
+	defer lyricsDecisionsLock.Unlock()
+
+	decisions := make([]LyricsDecision, len(lyricsDecisions))
+	copy(decisions, lyricsDecisions)
+	return decisions
+}
+
+// ClearLyricsDecisions resets the recorded lyrics decisions
+func ClearLyricsDecisions() {
+	lyricsDecisionsLock.Lock()
+	lyricsDecisions = nil
+	lyricsDecisionsLock.Unlock()
+}
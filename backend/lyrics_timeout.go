@@ -0,0 +1,89 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultLyricsSourceTimeout = 15 * time.Second
+	defaultLyricsTotalTimeout  = 60 * time.Second
+	defaultLyricsFetchRetries  = 2
+	lyricsRetryDelay           = time.Second
+)
+
+var (
+	lyricsSourceTimeout = defaultLyricsSourceTimeout
+	lyricsTotalTimeout  = defaultLyricsTotalTimeout
+	lyricsFetchRetries  = defaultLyricsFetchRetries
+	lyricsLimitsLock    sync.RWMutex
+)
+
+// SetLyricsFetchLimits sets the per-source timeout, the deadline for the whole multi-source
+// lookup and how many attempts each source gets on transient errors. Zero keeps the default.
+func SetLyricsFetchLimits(sourceTimeoutSeconds, totalTimeoutSeconds, retries int) {
+	lyricsLimitsLock.Lock()
+	defer lyricsLimitsLock.Unlock()
+
+	lyricsSourceTimeout = defaultLyricsSourceTimeout
+	if sourceTimeoutSeconds > 0 {
+		lyricsSourceTimeout = time.Duration(sourceTimeoutSeconds) * time.Second
+	}
+	lyricsTotalTimeout = defaultLyricsTotalTimeout
+	if totalTimeoutSeconds > 0 {
+		lyricsTotalTimeout = time.Duration(totalTimeoutSeconds) * time.Second
+	}
+	lyricsFetchRetries = defaultLyricsFetchRetries
+	if retries > 0 {
+		lyricsFetchRetries = retries
+	}
+}
+
+func getLyricsFetchLimits() (time.Duration, time.Duration, int) {
+	lyricsLimitsLock.RLock()
+	defer lyricsLimitsLock.RUnlock()
+	return lyricsSourceTimeout, lyricsTotalTimeout, lyricsFetchRetries
+}
+
+// isTransientLyricsError reports failures worth retrying: timeouts, network errors, rate limits and 5xx
+func isTransientLyricsError(err error) bool {
+	switch code := classifyDownloadError(err); {
+	case code == "timeout", code == "network", code == "http_429":
+		return true
+	case strings.HasPrefix(code, "http_5"):
+		return true
+	}
+	return false
+}
+
+// fetchLyricsSource runs one lyrics source with its own timeout, retrying transient errors
+// while the overall deadline in ctx allows
+func fetchLyricsSource(ctx context.Context, fetch func(context.Context) (*LyricsResponse, error)) (*LyricsResponse, error) {
+	sourceTimeout, _, attempts := getLyricsFetchLimits()
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("lyrics deadline exceeded: %w", ctx.Err())
+		}
+
+		sourceCtx, cancel := context.WithTimeout(ctx, sourceTimeout)
+		var resp *LyricsResponse
+		resp, err = fetch(sourceCtx)
+		cancel()
+
+		if err == nil || !isTransientLyricsError(err) || attempt == attempts {
+			return resp, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("lyrics deadline exceeded: %w", ctx.Err())
+		case <-time.After(lyricsRetryDelay * time.Duration(attempt)):
+		}
+	}
+	return nil, err
+}
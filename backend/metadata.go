@@ -1,6 +1,7 @@
 package backend
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -8,6 +9,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	id3v2 "github.com/bogem/id3v2/v2"
 	"github.com/go-flac/flacpicture"
@@ -25,9 +27,11 @@ type Metadata struct {
 	TrackNumber int
 	TotalTracks int // Total tracks in album
 	DiscNumber  int
+	TotalDiscs  int // Total discs in album
 	ISRC        string
 	Lyrics      string
 	Description string
+	MBID        string // MusicBrainz recording ID, set by fingerprint matching
 }
 
 func EmbedMetadata(filepath string, metadata Metadata, coverPath string) error {
@@ -50,7 +54,7 @@ func EmbedMetadata(filepath string, metadata Metadata, coverPath string) error {
 		_ = cmt.Add(flacvorbis.FIELD_TITLE, metadata.Title)
 	}
 	if metadata.Artist != "" {
-		_ = cmt.Add(flacvorbis.FIELD_ARTIST, metadata.Artist)
+		addArtistComments(cmt, metadata.Artist)
 	}
 	if metadata.Album != "" {
 		_ = cmt.Add(flacvorbis.FIELD_ALBUM, metadata.Album)
@@ -70,6 +74,9 @@ func EmbedMetadata(filepath string, metadata Metadata, coverPath string) error {
 	if metadata.DiscNumber > 0 {
 		_ = cmt.Add("DISCNUMBER", strconv.Itoa(metadata.DiscNumber))
 	}
+	if metadata.TotalDiscs > 0 {
+		_ = cmt.Add("DISCTOTAL", strconv.Itoa(metadata.TotalDiscs))
+	}
 	if metadata.ISRC != "" {
 		_ = cmt.Add(flacvorbis.FIELD_ISRC, metadata.ISRC)
 	}
@@ -88,13 +95,15 @@ func EmbedMetadata(filepath string, metadata Metadata, coverPath string) error {
 		f.Meta[cmtIdx] = &cmtBlock
 	}
 
-	if coverPath != "" && fileExists(coverPath) {
-		if err := embedCoverArt(f, coverPath); err != nil {
+	if coverPath = resolveEmbedCover(filepath, metadata.ISRC, coverPath); coverPath != "" {
+		squarePath, cleanup := prepareCoverForEmbed(filepath, coverPath)
+		defer cleanup()
+		if err := embedCoverArt(f, squarePath); err != nil {
 			fmt.Printf("Warning: Failed to embed cover art: %v\n", err)
 		}
 	}
 
-	if err := f.Save(filepath); err != nil {
+	if err := saveFLACFile(f, filepath); err != nil {
 		return fmt.Errorf("failed to save FLAC file: %w", err)
 	}
 
@@ -107,11 +116,16 @@ func embedCoverArt(f *flac.File, coverPath string) error {
 		return fmt.Errorf("failed to read cover image: %w", err)
 	}
 
+	mimeType := "image/jpeg"
+	if strings.HasSuffix(strings.ToLower(coverPath), ".png") {
+		mimeType = "image/png"
+	}
+
 	picture, err := flacpicture.NewFromImageData(
 		flacpicture.PictureTypeFrontCover,
 		"Cover",
 		imgData,
-		"image/jpeg",
+		mimeType,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create picture block: %w", err)
@@ -130,6 +144,24 @@ func embedCoverArt(f *flac.File, coverPath string) error {
 	return nil
 }
 
+// saveFLACFile writes the FLAC to a temp file next to path and renames it into place, so other
+// workers never read a half-written file and a failed write leaves the original intact
+func saveFLACFile(f *flac.File, path string) error {
+	tmpPath := path + ".tagging"
+	if err := f.Save(tmpPath); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if info, err := os.Stat(path); err == nil {
+		os.Chmod(tmpPath, info.Mode())
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
 func fileExists(path string) bool {
 	_, err := os.Stat(path)
 	return err == nil
@@ -153,6 +185,12 @@ func EmbedLyricsOnly(filepath string, lyrics string) error {
 	if lyrics == "" {
 		return nil
 	}
+	restore, err := prepareWritable(filepath)
+	if err != nil {
+		return err
+	}
+	defer restore()
+
 	f, err := flac.ParseFile(filepath)
 	if err != nil {
 		return fmt.Errorf("failed to parse FLAC file: %w", err)
@@ -197,7 +235,7 @@ func EmbedLyricsOnly(filepath string, lyrics string) error {
 		f.Meta[cmtIdx] = &cmtBlock
 	}
 
-	if err := f.Save(filepath); err != nil {
+	if err := saveFLACFile(f, filepath); err != nil {
 		return fmt.Errorf("failed to save FLAC file: %w", err)
 	}
 
@@ -234,7 +272,8 @@ func ReadISRCFromFile(filepath string) (string, error) {
 	return "", nil // No ISRC found
 }
 
-// CheckISRCExists checks if a file with the given ISRC already exists in the directory
+// CheckISRCExists checks if a file with the given ISRC already exists in the directory. A copy
+// that fails verification counts as missing and is left in place for the re-download to replace.
 func CheckISRCExists(outputDir string, targetISRC string) (string, bool) {
 	if targetISRC == "" {
 		return "", false
@@ -259,19 +298,25 @@ func CheckISRCExists(outputDir string, targetISRC string) (string, bool) {
 
 		filepath := fmt.Sprintf("%s/%s", outputDir, filename)
 
-		// Read ISRC from file (this will fail for corrupted files)
+		// Read ISRC from file (this will fail for corrupted files). The check runs while other
+		// workers download into the same folder, so it never deletes; a corrupted copy is
+		// replaced by the download that needs its name.
 		isrc, err := ReadISRCFromFile(filepath)
 		if err != nil {
-			// File is corrupted or unreadable, delete it
-			fmt.Printf("Removing corrupted/unreadable file: %s (error: %v)\n", filepath, err)
-			if removeErr := os.Remove(filepath); removeErr != nil {
-				fmt.Printf("Warning: Failed to remove corrupted file %s: %v\n", filepath, removeErr)
-			}
+			fmt.Printf("Skipping unreadable file: %s (error: %v)\n", filepath, err)
 			continue
 		}
 
 		// Compare ISRC (case-insensitive)
 		if isrc != "" && strings.EqualFold(isrc, targetISRC) {
+			// An intact tag doesn't mean intact audio; a file that fails to decode is downloaded again
+			if isVerifyBeforeSkipEnabled() {
+				if _, _, err := VerifyFLACIntegrity(filepath); err != nil {
+					fmt.Printf("Existing file with ISRC %s failed verification, needs re-download: %s (error: %v)\n", targetISRC, filepath, err)
+					recordBrokenISRCFile(outputDir, targetISRC, filepath)
+					continue
+				}
+			}
 			return filepath, true
 		}
 	}
@@ -451,21 +496,145 @@ func EmbedCoverArtOnly(filePath string, coverPath string) error {
 		return nil
 	}
 
+	restore, err := prepareWritable(filePath)
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	coverPath, cleanup := prepareCoverForEmbed(filePath, coverPath)
+	defer cleanup()
+
 	ext := strings.ToLower(pathfilepath.Ext(filePath))
 
 	switch ext {
 	case ".mp3":
 		return embedCoverToMp3(filePath, coverPath)
 	case ".m4a":
-		// M4A cover art should be handled by ffmpeg during conversion
-		// If not, we can try to embed using atomicparsley or similar tool
-		// For now, return nil as ffmpeg should handle it
+		return EmbedCoverM4A(filePath, coverPath)
+	case ".flac":
+		f, err := flac.ParseFile(filePath)
+		if err != nil {
+			return fmt.Errorf("failed to parse FLAC file: %w", err)
+		}
+		if err := embedCoverArt(f, coverPath); err != nil {
+			return err
+		}
+		if err := saveFLACFile(f, filePath); err != nil {
+			return fmt.Errorf("failed to save FLAC file: %w", err)
+		}
 		return nil
 	default:
 		return fmt.Errorf("unsupported file format: %s", ext)
 	}
 }
 
+// EmbedCoverM4A attaches a cover image to an M4A file as the covr atom using ffmpeg,
+// replacing any existing cover, and verifies the cover can be read back
+func EmbedCoverM4A(filePath, imagePath string) error {
+	ffmpegPath, err := GetFFmpegPath()
+	if err != nil {
+		return fmt.Errorf("ffmpeg not found: %w", err)
+	}
+
+	if err := ValidateExecutable(ffmpegPath); err != nil {
+		return fmt.Errorf("invalid ffmpeg executable: %w", err)
+	}
+
+	tmpOutputFile := strings.TrimSuffix(filePath, pathfilepath.Ext(filePath)) + ".tmp" + pathfilepath.Ext(filePath)
+	defer func() {
+		if _, err := os.Stat(tmpOutputFile); err == nil {
+			os.Remove(tmpOutputFile)
+		}
+	}()
+
+	// Only map audio from the original so an existing cover is replaced, not duplicated
+	cmd := exec.Command(ffmpegPath,
+		"-i", filePath,
+		"-i", imagePath,
+		"-map", "0:a",
+		"-map", "1:v",
+		"-map_metadata", "0",
+		"-codec", "copy",
+		"-disposition:v:0", "attached_pic",
+		"-f", "ipod",
+		"-y",
+		tmpOutputFile,
+	)
+
+	// Hide console window on Windows
+	setHideWindow(cmd)
+
+	output, err := ffmpegCombinedOutput(cmd)
+	if err != nil {
+		fmt.Printf("[FFmpeg] Error embedding cover to M4A: %s\n", string(output))
+		return fmt.Errorf("ffmpeg failed to embed cover: %s - %w", string(output), err)
+	}
+
+	if err := os.Rename(tmpOutputFile, filePath); err != nil {
+		return fmt.Errorf("failed to replace original file: %w", err)
+	}
+
+	if installed, _ := IsFFprobeInstalled(); installed {
+		hasCover, err := M4AHasCover(filePath)
+		if err != nil {
+			return fmt.Errorf("failed to verify embedded cover: %w", err)
+		}
+		if !hasCover {
+			return fmt.Errorf("cover not found in M4A after embedding")
+		}
+	}
+
+	fmt.Printf("[FFmpeg] Cover embedded to M4A successfully\n")
+	return nil
+}
+
+// M4AHasCover reports whether an M4A file contains an attached cover picture
+func M4AHasCover(filePath string) (bool, error) {
+	ffprobePath, err := GetFFprobePath()
+	if err != nil {
+		return false, err
+	}
+
+	if err := ValidateExecutable(ffprobePath); err != nil {
+		return false, fmt.Errorf("invalid ffprobe executable: %w", err)
+	}
+
+	cmd := exec.Command(ffprobePath,
+		"-v", "quiet",
+		"-print_format", "json",
+		"-show_streams",
+		filePath,
+	)
+
+	// Hide console window on Windows
+	setHideWindow(cmd)
+
+	output, err := ffmpegOutput(cmd)
+	if err != nil {
+		return false, err
+	}
+
+	var result struct {
+		Streams []struct {
+			CodecType   string `json:"codec_type"`
+			Disposition struct {
+				AttachedPic int `json:"attached_pic"`
+			} `json:"disposition"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return false, err
+	}
+
+	for _, stream := range result.Streams {
+		if stream.CodecType == "video" && stream.Disposition.AttachedPic == 1 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // embedCoverToMp3 embeds cover art into MP3 file
 func embedCoverToMp3(filePath string, coverPath string) error {
 	tag, err := id3v2.Open(filePath, id3v2.Options{Parse: true})
@@ -572,7 +741,7 @@ func embedLyricsToM4A(filepath string, lyrics string) error {
 	// Hide console window on Windows
 	setHideWindow(cmd)
 
-	output, err := cmd.CombinedOutput()
+	output, err := ffmpegCombinedOutput(cmd)
 	if err != nil {
 		fmt.Printf("[FFmpeg] Error embedding lyrics to M4A: %s\n", string(output))
 		return fmt.Errorf("ffmpeg failed to embed lyrics: %s - %w", string(output), err)
@@ -592,10 +761,19 @@ func EmbedLyricsOnlyUniversal(filepath string, lyrics string) error {
 	if lyrics == "" {
 		return nil
 	}
+	restore, err := prepareWritable(filepath)
+	if err != nil {
+		return err
+	}
+	defer restore()
 
 	ext := strings.ToLower(pathfilepath.Ext(filepath))
 	switch ext {
 	case ".mp3":
+		// Timestamped LRC goes into a SYLT frame so synced lyrics survive conversion
+		if parsed := parseLRCText(lyrics); parsed.SyncType == "LINE_SYNCED" {
+			return EmbedSyncedLyricsMP3(filepath, parsed)
+		}
 		return EmbedLyricsOnlyMP3(filepath, lyrics)
 	case ".flac":
 		return EmbedLyricsOnly(filepath, lyrics)
@@ -606,6 +784,149 @@ func EmbedLyricsOnlyUniversal(filepath string, lyrics string) error {
 	}
 }
 
+// setVorbisFields replaces the given Vorbis comment fields in a FLAC file, preserving all other comments
+func setVorbisFields(filepath string, fields map[string]string) error {
+	values := make(map[string][]string, len(fields))
+	for name, value := range fields {
+		values[name] = []string{value}
+	}
+	return setVorbisFieldValues(filepath, values)
+}
+
+// setVorbisFieldValues is setVorbisFields for fields that repeat, such as several GENRE entries
+func setVorbisFieldValues(filepath string, fields map[string][]string) error {
+	restore, err := prepareWritable(filepath)
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	f, err := flac.ParseFile(filepath)
+	if err != nil {
+		return fmt.Errorf("failed to parse FLAC file: %w", err)
+	}
+
+	var cmtIdx = -1
+	var existingCmt *flacvorbis.MetaDataBlockVorbisComment
+	for idx, block := range f.Meta {
+		if block.Type == flac.VorbisComment {
+			cmtIdx = idx
+			existingCmt, err = flacvorbis.ParseFromMetaDataBlock(*block)
+			if err != nil {
+				existingCmt = nil
+			}
+			break
+		}
+	}
+
+	cmt := flacvorbis.New()
+	if existingCmt != nil {
+		cmt.Vendor = existingCmt.Vendor
+		for _, comment := range existingCmt.Comments {
+			parts := strings.SplitN(comment, "=", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			if _, replaced := fields[strings.ToUpper(parts[0])]; replaced {
+				continue
+			}
+			_ = cmt.Add(parts[0], parts[1])
+		}
+	}
+
+	for name, values := range fields {
+		for _, value := range values {
+			if value != "" {
+				_ = cmt.Add(name, value)
+			}
+		}
+	}
+
+	cmtBlock := cmt.Marshal()
+	if cmtIdx < 0 {
+		f.Meta = append(f.Meta, &cmtBlock)
+	} else {
+		f.Meta[cmtIdx] = &cmtBlock
+	}
+
+	if err := saveFLACFile(f, filepath); err != nil {
+		return fmt.Errorf("failed to save FLAC file: %w", err)
+	}
+
+	return nil
+}
+
+// readVorbisField returns the first value of a Vorbis comment field in a FLAC file
+func readVorbisField(filepath string, field string) (string, error) {
+	f, err := flac.ParseFile(filepath)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse FLAC file: %w", err)
+	}
+
+	for _, block := range f.Meta {
+		if block.Type == flac.VorbisComment {
+			cmt, err := flacvorbis.ParseFromMetaDataBlock(*block)
+			if err != nil {
+				continue
+			}
+			values, err := cmt.Get(field)
+			if err == nil && len(values) > 0 {
+				return values[0], nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// readVorbisComments returns every Vorbis comment in a FLAC file keyed by upper-cased field name
+func readVorbisComments(filepath string) (map[string]string, error) {
+	f, err := flac.ParseFile(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse FLAC file: %w", err)
+	}
+
+	fields := make(map[string]string)
+	for _, block := range f.Meta {
+		if block.Type != flac.VorbisComment {
+			continue
+		}
+		cmt, err := flacvorbis.ParseFromMetaDataBlock(*block)
+		if err != nil {
+			continue
+		}
+		for _, comment := range cmt.Comments {
+			parts := strings.SplitN(comment, "=", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			key := strings.ToUpper(parts[0])
+			if _, exists := fields[key]; !exists {
+				fields[key] = parts[1]
+			}
+		}
+	}
+
+	return fields, nil
+}
+
+// EmbedProvenanceTags records where a file came from as SPOTIFY_ID, SOURCE_SERVICE and DOWNLOAD_DATE
+func EmbedProvenanceTags(filepath, spotifyID, sourceService string) error {
+	return setVorbisFields(filepath, map[string]string{
+		"SPOTIFY_ID":     spotifyID,
+		"SOURCE_SERVICE": sourceService,
+		"DOWNLOAD_DATE":  time.Now().Format("2006-01-02"),
+	})
+}
+
+// ReadSpotifyIDFromFile reads the SPOTIFY_ID provenance tag from a FLAC file
+func ReadSpotifyIDFromFile(filepath string) (string, error) {
+	if !fileExists(filepath) {
+		return "", fmt.Errorf("file does not exist")
+	}
+	return readVorbisField(filepath, "SPOTIFY_ID")
+}
+
 // FileExistenceResult represents the result of checking if a file exists
 type FileExistenceResult struct {
 	ISRC       string `json:"isrc"`
@@ -0,0 +1,101 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// MissingCheckTrack is a track of an album/playlist to check against the output directory
+type MissingCheckTrack struct {
+	SpotifyID   string `json:"spotify_id"`
+	ISRC        string `json:"isrc"`
+	TrackName   string `json:"track_name"`
+	ArtistName  string `json:"artist_name"`
+	AlbumName   string `json:"album_name"`
+	AlbumArtist string `json:"album_artist"`
+	ReleaseDate string `json:"release_date"`
+	Position    int    `json:"position"`
+	DiscNumber  int    `json:"disc_number"`
+	TrackNumber int    `json:"track_number,omitempty"` // Album track number
+	Source      string `json:"source,omitempty"`       // "playlist" or "album"
+}
+
+// MissingOnlyRequest represents a request to find which tracks still need downloading
+type MissingOnlyRequest struct {
+	OutputDir           string              `json:"output_dir"`
+	FilenameFormat      string              `json:"filename_format"`
+	TrackNumber         bool                `json:"track_number"`
+	UseAlbumTrackNumber bool                `json:"use_album_track_number"`
+	Tracks              []MissingCheckTrack `json:"tracks"`
+}
+
+// MissingOnlyResult lists the tracks that are not on disk yet
+type MissingOnlyResult struct {
+	TotalTracks   int                 `json:"total_tracks"`
+	SkippedTracks int                 `json:"skipped_tracks"`
+	MissingCount  int                 `json:"missing_count"`
+	Missing       []MissingCheckTrack `json:"missing"`
+	ExistingFiles []string            `json:"existing_files"`
+}
+
+// FilterMissingTracks checks an album/playlist against the output directory using only
+// local data (ISRC/SPOTIFY_ID tags and expected filenames), so present tracks can be
+// skipped before any availability or metadata requests are made
+func FilterMissingTracks(req MissingOnlyRequest) (*MissingOnlyResult, error) {
+	outputDir := NormalizePath(req.OutputDir)
+
+	result := &MissingOnlyResult{
+		TotalTracks:   len(req.Tracks),
+		Missing:       make([]MissingCheckTrack, 0, len(req.Tracks)),
+		ExistingFiles: make([]string, 0),
+	}
+
+	// Nothing downloaded yet - everything is missing
+	if _, err := os.Stat(outputDir); os.IsNotExist(err) {
+		result.Missing = append(result.Missing, req.Tracks...)
+		result.MissingCount = len(result.Missing)
+		return result, nil
+	}
+
+	index, err := buildFolderIndex(outputDir, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	filenameFormat := req.FilenameFormat
+	if filenameFormat == "" {
+		filenameFormat = "title-artist"
+	}
+
+	for _, track := range req.Tracks {
+		existing := ""
+
+		if track.ISRC != "" {
+			existing = index.byISRC[normalizeISRC(track.ISRC)]
+		}
+		if existing == "" && track.SpotifyID != "" {
+			existing = index.bySpotifyID[track.SpotifyID]
+		}
+		if existing == "" && track.TrackName != "" && track.ArtistName != "" {
+			useAlbumTrackNumber := ResolveUseAlbumTrackNumber(track.Source, req.UseAlbumTrackNumber)
+			filenamePosition := FilenameTrackPosition(track.Position, track.TrackNumber, useAlbumTrackNumber)
+			expectedFilename := BuildExpectedFilename(track.TrackName, track.ArtistName, track.AlbumName, track.AlbumArtist, track.ReleaseDate, filenameFormat, req.TrackNumber, filenamePosition, track.DiscNumber, useAlbumTrackNumber)
+			expectedPath := filepath.Join(outputDir, expectedFilename)
+			if fileInfo, err := os.Stat(expectedPath); err == nil && fileInfo.Size() > 100*1024 {
+				existing = expectedPath
+			}
+		}
+
+		if existing != "" {
+			result.ExistingFiles = append(result.ExistingFiles, existing)
+			continue
+		}
+		result.Missing = append(result.Missing, track)
+	}
+
+	result.MissingCount = len(result.Missing)
+	result.SkippedTracks = result.TotalTracks - result.MissingCount
+	fmt.Printf("[Missing Only] %d/%d tracks already present, %d to download\n", result.SkippedTracks, result.TotalTracks, result.MissingCount)
+	return result, nil
+}
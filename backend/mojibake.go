@@ -0,0 +1,258 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bogem/id3v2"
+	"github.com/go-flac/flacvorbis"
+	"github.com/go-flac/go-flac"
+)
+
+// MojibakeFix is one tag value that decodes to different text when its characters are read
+// back as the bytes a Latin-1/Windows-1252 decoder produced them from
+type MojibakeFix struct {
+	Field    string `json:"field"`
+	Original string `json:"original"`
+	Fixed    string `json:"fixed"`
+}
+
+// MojibakeFileResult lists the repairs found (and applied, unless previewing) for one file
+type MojibakeFileResult struct {
+	FilePath string        `json:"file_path"`
+	Fixes    []MojibakeFix `json:"fixes"`
+	Fixed    bool          `json:"fixed"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// MojibakeRepairResult summarizes a batch repair over a folder
+type MojibakeRepairResult struct {
+	Success       bool                 `json:"success"`
+	DryRun        bool                 `json:"dry_run"`
+	ScannedFiles  int                  `json:"scanned_files"`
+	AffectedFiles int                  `json:"affected_files"`
+	Files         []MojibakeFileResult `json:"files"`
+	Error         string               `json:"error,omitempty"`
+}
+
+var mojibakeVorbisFields = map[string]bool{"TITLE": true, "ARTIST": true, "ALBUM": true, "ALBUMARTIST": true}
+
+var mojibakeID3Frames = map[string]string{"TIT2": "TITLE", "TPE1": "ARTIST", "TALB": "ALBUM", "TPE2": "ALBUMARTIST"}
+
+// cp1252Bytes maps the characters Windows-1252 puts in 0x80-0x9F back to their byte, since
+// most mojibake comes from that codepage rather than strict Latin-1
+var cp1252Bytes = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, 'ˆ': 0x88,
+	'‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91, '’': 0x92, '“': 0x93,
+	'”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B,
+	'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// fixMojibake undoes UTF-8 text that was decoded as Latin-1/Windows-1252 (e.g. "BeyoncÃ©"),
+// repeating for text that went through the round trip twice. Values that don't map back to
+// valid multi-byte UTF-8 are left alone, so correctly encoded accents are never touched.
+func fixMojibake(value string) (string, bool) {
+	fixed := value
+	for round := 0; round < 3; round++ {
+		decoded, ok := decodeLatin1AsUTF8(fixed)
+		if !ok {
+			break
+		}
+		fixed = decoded
+	}
+	return fixed, fixed != value
+}
+
+func decodeLatin1AsUTF8(value string) (string, bool) {
+	raw := make([]byte, 0, len(value))
+	multiByte := false
+	for _, r := range value {
+		switch b, ok := cp1252Bytes[r]; {
+		case ok:
+			raw = append(raw, b)
+		case r <= 0xFF:
+			raw = append(raw, byte(r))
+		default:
+			return "", false
+		}
+		if r >= 0x80 {
+			multiByte = true
+		}
+	}
+	if !multiByte || !utf8.Valid(raw) {
+		return "", false
+	}
+	return string(raw), true
+}
+
+// FixMojibakeTags repairs double-encoded title/artist/album tags in a FLAC or MP3 file and
+// reports whether anything changed
+func FixMojibakeTags(filePath string) (bool, error) {
+	result := repairMojibakeFile(filePath, false)
+	if result.Error != "" {
+		return false, fmt.Errorf("%s", result.Error)
+	}
+	return result.Fixed, nil
+}
+
+// PreviewMojibakeTags returns the repairs FixMojibakeTags would make without writing the file
+func PreviewMojibakeTags(filePath string) ([]MojibakeFix, error) {
+	result := repairMojibakeFile(filePath, true)
+	if result.Error != "" {
+		return nil, fmt.Errorf("%s", result.Error)
+	}
+	return result.Fixes, nil
+}
+
+// FixMojibakeInFolder runs the repair over every FLAC and MP3 file below dirPath. With dryRun
+// set the affected files and their fixes are listed but nothing is written.
+func FixMojibakeInFolder(dirPath string, dryRun bool) (*MojibakeRepairResult, error) {
+	dirPath = NormalizePath(dirPath)
+	result := &MojibakeRepairResult{Success: true, DryRun: dryRun, Files: make([]MojibakeFileResult, 0)}
+
+	if _, err := os.Stat(dirPath); os.IsNotExist(err) {
+		result.Success = false
+		result.Error = fmt.Sprintf("Directory does not exist: %s", dirPath)
+		return result, fmt.Errorf("directory does not exist: %s", dirPath)
+	}
+
+	err := filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		if ext != ".flac" && ext != ".mp3" {
+			return nil
+		}
+
+		result.ScannedFiles++
+		fileResult := repairMojibakeFile(path, dryRun)
+		if len(fileResult.Fixes) > 0 || fileResult.Error != "" {
+			result.Files = append(result.Files, fileResult)
+		}
+		if len(fileResult.Fixes) > 0 {
+			result.AffectedFiles++
+		}
+		return nil
+	})
+	if err != nil {
+		result.Success = false
+		result.Error = fmt.Sprintf("Failed to scan directory: %v", err)
+		return result, err
+	}
+
+	fmt.Printf("[Mojibake] %d/%d files with double-encoded tags (dry run: %v)\n", result.AffectedFiles, result.ScannedFiles, dryRun)
+	return result, nil
+}
+
+func repairMojibakeFile(filePath string, dryRun bool) MojibakeFileResult {
+	result := MojibakeFileResult{FilePath: filePath, Fixes: make([]MojibakeFix, 0)}
+
+	var err error
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".flac":
+		err = repairMojibakeFLAC(filePath, dryRun, &result)
+	case ".mp3":
+		err = repairMojibakeMP3(filePath, dryRun, &result)
+	default:
+		err = fmt.Errorf("unsupported file format for tag repair: %s", filepath.Ext(filePath))
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	for _, fix := range result.Fixes {
+		fmt.Printf("[Mojibake] %s: %s %q -> %q\n", filepath.Base(filePath), fix.Field, fix.Original, fix.Fixed)
+	}
+	return result
+}
+
+// repairMojibakeFLAC fixes comments in place so multi-valued fields like ARTIST keep every value
+func repairMojibakeFLAC(filePath string, dryRun bool, result *MojibakeFileResult) error {
+	f, err := flac.ParseFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to parse FLAC file: %w", err)
+	}
+
+	cmtIdx := -1
+	var cmt *flacvorbis.MetaDataBlockVorbisComment
+	for idx, block := range f.Meta {
+		if block.Type == flac.VorbisComment {
+			if cmt, err = flacvorbis.ParseFromMetaDataBlock(*block); err == nil {
+				cmtIdx = idx
+			}
+			break
+		}
+	}
+	if cmtIdx < 0 {
+		return nil
+	}
+
+	for i, comment := range cmt.Comments {
+		parts := strings.SplitN(comment, "=", 2)
+		if len(parts) != 2 || !mojibakeVorbisFields[strings.ToUpper(parts[0])] {
+			continue
+		}
+		if fixed, changed := fixMojibake(parts[1]); changed {
+			result.Fixes = append(result.Fixes, MojibakeFix{Field: strings.ToUpper(parts[0]), Original: parts[1], Fixed: fixed})
+			cmt.Comments[i] = parts[0] + "=" + fixed
+		}
+	}
+	if dryRun || len(result.Fixes) == 0 {
+		return nil
+	}
+
+	restore, err := prepareWritable(filePath)
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	cmtBlock := cmt.Marshal()
+	f.Meta[cmtIdx] = &cmtBlock
+	if err := saveFLACFile(f, filePath); err != nil {
+		return fmt.Errorf("failed to save FLAC file: %w", err)
+	}
+	result.Fixed = true
+	return nil
+}
+
+func repairMojibakeMP3(filePath string, dryRun bool, result *MojibakeFileResult) error {
+	if !dryRun {
+		restore, err := prepareWritable(filePath)
+		if err != nil {
+			return err
+		}
+		defer restore()
+	}
+
+	tag, err := id3v2.Open(filePath, id3v2.Options{Parse: true})
+	if err != nil {
+		return fmt.Errorf("failed to open MP3 file: %w", err)
+	}
+	defer tag.Close()
+
+	for _, frameID := range []string{"TIT2", "TPE1", "TALB", "TPE2"} {
+		value := tag.GetTextFrame(frameID).Text
+		fixed, changed := fixMojibake(value)
+		if !changed {
+			continue
+		}
+		result.Fixes = append(result.Fixes, MojibakeFix{Field: mojibakeID3Frames[frameID], Original: value, Fixed: fixed})
+		tag.DeleteFrames(frameID)
+		tag.AddTextFrame(frameID, id3v2.EncodingUTF8, fixed)
+	}
+	if dryRun || len(result.Fixes) == 0 {
+		return nil
+	}
+
+	if err := tag.Save(); err != nil {
+		return fmt.Errorf("failed to save MP3 tags: %w", err)
+	}
+	result.Fixed = true
+	return nil
+}
@@ -0,0 +1,170 @@
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultFinalizeRetries = 3
+	finalizeRetryDelay     = 500 * time.Millisecond
+
+	// windowsNotSameDevice is ERROR_NOT_SAME_DEVICE, what MoveFileEx returns across volumes
+	windowsNotSameDevice = syscall.Errno(17)
+)
+
+var (
+	finalizeRetries     = defaultFinalizeRetries
+	finalizeRetriesLock sync.RWMutex
+
+	copyFinalizedFiles     []string
+	copyFinalizedFilesLock sync.Mutex
+)
+
+// SetFinalizeRetries sets how often a move into place is retried after a transient error, as
+// network and cloud-synced folders fail intermittently (negative = default)
+func SetFinalizeRetries(retries int) {
+	if retries < 0 {
+		retries = defaultFinalizeRetries
+	}
+
+	finalizeRetriesLock.Lock()
+	finalizeRetries = retries
+	finalizeRetriesLock.Unlock()
+}
+
+func getFinalizeRetries() int {
+	finalizeRetriesLock.RLock()
+	defer finalizeRetriesLock.RUnlock()
+	return finalizeRetries
+}
+
+// isCrossDeviceError reports whether a rename failed because source and destination are on
+// different filesystems, where only copy + delete works
+func isCrossDeviceError(err error) bool {
+	var errno syscall.Errno
+	if !errors.As(err, &errno) {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return errno == windowsNotSameDevice
+	}
+	return errno == syscall.EXDEV
+}
+
+// isTransientFSError reports whether a filesystem error is the kind a network share recovers from
+func isTransientFSError(err error) bool {
+	var errno syscall.Errno
+	if !errors.As(err, &errno) {
+		return false
+	}
+	switch errno {
+	case syscall.EIO, syscall.EAGAIN, syscall.EBUSY, syscall.ETIMEDOUT, syscall.ESTALE, syscall.EINTR:
+		return true
+	}
+	return false
+}
+
+// withFinalizeRetries runs op, retrying transient errors with a growing delay
+func withFinalizeRetries(label string, op func() error) error {
+	retries := getFinalizeRetries()
+	var err error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			fmt.Printf("[Finalize] %s failed (%v), retry %d/%d\n", label, err, attempt, retries)
+			time.Sleep(time.Duration(attempt) * finalizeRetryDelay)
+		}
+		if err = op(); err == nil || !isTransientFSError(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// copyFileSynced streams src into dst and flushes it to disk, so a network share has the whole file
+// before it gets its final name
+func copyFileSynced(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// finalizeFile moves src to dst. A plain rename is tried first; across filesystems the file is
+// copied to a temp name beside dst, renamed into place and the original removed. Transient
+// errors are retried on both paths. Returns whether the slower copy path was used.
+func finalizeFile(src, dst string) (bool, error) {
+	renameErr := withFinalizeRetries("rename", func() error { return os.Rename(src, dst) })
+	if renameErr == nil {
+		return false, nil
+	}
+	if !isCrossDeviceError(renameErr) {
+		return false, renameErr
+	}
+
+	fmt.Printf("[Finalize] %s is on another filesystem, copying instead of renaming\n", dst)
+	tmp := dst + ".partial"
+	err := withFinalizeRetries("copy", func() error {
+		os.Remove(tmp)
+		return copyFileSynced(src, tmp)
+	})
+	if err != nil {
+		os.Remove(tmp)
+		return true, fmt.Errorf("failed to copy file: %v", err)
+	}
+	if err := withFinalizeRetries("rename", func() error { return os.Rename(tmp, dst) }); err != nil {
+		os.Remove(tmp)
+		return true, fmt.Errorf("failed to finalize file: %v", err)
+	}
+	if err := withFinalizeRetries("remove", func() error { return os.Remove(src) }); err != nil {
+		return true, fmt.Errorf("moved, but failed to remove original: %v", err)
+	}
+
+	recordCopyFinalized(dst)
+	return true, nil
+}
+
+// recordCopyFinalized flags a file that reached its destination through the copy path
+func recordCopyFinalized(path string) {
+	copyFinalizedFilesLock.Lock()
+	defer copyFinalizedFilesLock.Unlock()
+	copyFinalizedFiles = append(copyFinalizedFiles, path)
+}
+
+// GetCopyFinalizedFiles returns the files that had to be copied across filesystems instead of renamed
+func GetCopyFinalizedFiles() []string {
+	copyFinalizedFilesLock.Lock()
+	defer copyFinalizedFilesLock.Unlock()
+
+	files := make([]string, len(copyFinalizedFiles))
+	copy(files, copyFinalizedFiles)
+	return files
+}
+
+// ClearCopyFinalizedFiles resets the list of copied files
+func ClearCopyFinalizedFiles() {
+	copyFinalizedFilesLock.Lock()
+	defer copyFinalizedFilesLock.Unlock()
+	copyFinalizedFiles = nil
+}
@@ -0,0 +1,260 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultCompareConcurrency is used when the caller doesn't specify a worker count
+const defaultCompareConcurrency = 8
+
+// CSVCompareRequest represents a request to compare a CSV playlist against a local folder
+type CSVCompareRequest struct {
+	CSVFilePath string `json:"csv_file_path"`
+	FolderPath  string `json:"folder_path"`
+	Concurrency int    `json:"concurrency,omitempty"`
+}
+
+// CSVCompareTrack represents a playlist track and whether it was found locally
+type CSVCompareTrack struct {
+	Track     CSVTrack `json:"track"`
+	Found     bool     `json:"found"`
+	FilePath  string   `json:"file_path,omitempty"`
+	MatchedBy string   `json:"matched_by,omitempty"`
+}
+
+// CSVCompareResult represents the result of comparing a CSV playlist against a folder
+type CSVCompareResult struct {
+	Success       bool              `json:"success"`
+	TotalTracks   int               `json:"total_tracks"`
+	FoundTracks   int               `json:"found_tracks"`
+	MissingTracks int               `json:"missing_tracks"`
+	Missing       []CSVTrack        `json:"missing"`
+	Tracks        []CSVCompareTrack `json:"tracks"`
+	Error         string            `json:"error,omitempty"`
+}
+
+// folderIndex is a single cached listing of a folder, keyed by normalized ISRC and by
+// normalized "artist title" so each track lookup is a map hit instead of a disk scan
+type folderIndex struct {
+	byISRC      map[string]string
+	bySpotifyID map[string]string
+	byName      map[string]string
+}
+
+// cachedFolderIndex is an index together with the folder state it was built from
+type cachedFolderIndex struct {
+	modTime time.Time
+	files   int
+	index   *folderIndex
+}
+
+var (
+	// Built indexes by folder path; compare, audit and missing-track checks of an unchanged
+	// folder reuse them instead of reading every tag again
+	folderIndexCache     = make(map[string]cachedFolderIndex)
+	folderIndexCacheLock sync.Mutex
+)
+
+// normalizeISRC uppercases an ISRC and strips separators so "us-abc-12-34567" matches "USABC1234567"
+func normalizeISRC(isrc string) string {
+	isrc = strings.ToUpper(strings.TrimSpace(isrc))
+	return strings.NewReplacer("-", "", " ", "", ".", "").Replace(isrc)
+}
+
+// normalizeMatchKey lowercases and strips punctuation for fuzzy name matching
+func normalizeMatchKey(parts ...string) string {
+	var b strings.Builder
+	for _, part := range parts {
+		for _, r := range strings.ToLower(part) {
+			if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r > 127 {
+				b.WriteRune(r)
+			}
+		}
+	}
+	return b.String()
+}
+
+// find looks a track up by ISRC, then Spotify ID, then normalized "artist title".
+// Returns the matched path and what it matched by, or empty strings.
+func (index *folderIndex) find(track CSVTrack) (string, string) {
+	if track.ISRC != "" {
+		if path, ok := index.byISRC[normalizeISRC(track.ISRC)]; ok {
+			return path, "isrc"
+		}
+	}
+
+	if track.SpotifyID != "" {
+		if path, ok := index.bySpotifyID[track.SpotifyID]; ok {
+			return path, "spotify_id"
+		}
+	}
+
+	for _, key := range []string{
+		normalizeMatchKey(track.ArtistName, track.TrackName),
+		normalizeMatchKey(firstArtist(track.ArtistName), track.TrackName),
+	} {
+		if path, ok := index.byName[key]; ok {
+			return path, "name"
+		}
+	}
+	return "", ""
+}
+
+// buildFolderIndex lists the folder once and reads tags with a bounded worker pool. The index
+// is cached by folder path and reused while no file or subfolder in it has changed since.
+func buildFolderIndex(folderPath string, concurrency int) (*folderIndex, error) {
+	if concurrency <= 0 {
+		concurrency = defaultCompareConcurrency
+	}
+
+	// The newest modification time covers files added, removed (their folder changes) or retagged
+	var modTime time.Time
+	files := make([]string, 0)
+	err := filepath.Walk(folderPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if info.ModTime().After(modTime) {
+				modTime = info.ModTime()
+			}
+			return nil
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		if ext == ".flac" || ext == ".mp3" || ext == ".m4a" {
+			files = append(files, path)
+			if info.ModTime().After(modTime) {
+				modTime = info.ModTime()
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan folder: %v", err)
+	}
+
+	cacheKey := filepath.Clean(folderPath)
+	folderIndexCacheLock.Lock()
+	cached, ok := folderIndexCache[cacheKey]
+	folderIndexCacheLock.Unlock()
+	if ok && cached.modTime.Equal(modTime) && cached.files == len(files) {
+		fmt.Printf("[CSV Compare] Reusing index of %d files (%d with ISRC)\n", cached.files, len(cached.index.byISRC))
+		return cached.index, nil
+	}
+
+	index := &folderIndex{
+		byISRC:      make(map[string]string),
+		bySpotifyID: make(map[string]string),
+		byName:      make(map[string]string),
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	fileChan := make(chan string, len(files))
+	for _, f := range files {
+		fileChan <- f
+	}
+	close(fileChan)
+
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range fileChan {
+				// One tag read per file gives ISRC (ISRC, TSRC) and Spotify ID (SPOTIFY_ID,
+				// TXXX:SPOTIFY_ID) for FLAC, MP3 and M4A alike
+				var isrc, spotifyID, nameKey string
+				if metadata, err := ReadAudioMetadata(path); err == nil {
+					isrc = strings.TrimSpace(metadata.ISRC)
+					spotifyID = strings.TrimSpace(metadata.SpotifyID)
+					if metadata.Title != "" {
+						nameKey = normalizeMatchKey(metadata.Artist, metadata.Title)
+					}
+				}
+
+				mu.Lock()
+				if isrc != "" {
+					index.byISRC[normalizeISRC(isrc)] = path
+				}
+				if spotifyID != "" {
+					index.bySpotifyID[spotifyID] = path
+				}
+				if nameKey != "" {
+					index.byName[nameKey] = path
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+	fmt.Printf("[CSV Compare] Indexed %d files (%d with ISRC)\n", len(files), len(index.byISRC))
+
+	folderIndexCacheLock.Lock()
+	folderIndexCache[cacheKey] = cachedFolderIndex{modTime: modTime, files: len(files), index: index}
+	folderIndexCacheLock.Unlock()
+	return index, nil
+}
+
+// firstArtist returns the first artist from a comma separated artist list
+func firstArtist(artists string) string {
+	if idx := strings.Index(artists, ","); idx != -1 {
+		return strings.TrimSpace(artists[:idx])
+	}
+	return strings.TrimSpace(artists)
+}
+
+// CompareCSVToFolder reports which tracks of a CSV playlist are already present in a folder
+func CompareCSVToFolder(req CSVCompareRequest) (*CSVCompareResult, error) {
+	fmt.Printf("\n[CSV Compare] Comparing %s against %s\n", req.CSVFilePath, req.FolderPath)
+
+	tracks, err := ParseCSVPlaylist(req.CSVFilePath)
+	if err != nil {
+		return &CSVCompareResult{Success: false, Error: err.Error()}, err
+	}
+
+	folderPath := NormalizePath(req.FolderPath)
+	if _, err := os.Stat(folderPath); os.IsNotExist(err) {
+		return &CSVCompareResult{
+			Success: false,
+			Error:   fmt.Sprintf("Directory does not exist: %s", folderPath),
+		}, fmt.Errorf("directory does not exist: %s", folderPath)
+	}
+
+	index, err := buildFolderIndex(folderPath, req.Concurrency)
+	if err != nil {
+		return &CSVCompareResult{Success: false, Error: err.Error()}, err
+	}
+
+	result := &CSVCompareResult{
+		Success:     true,
+		TotalTracks: len(tracks),
+		Missing:     make([]CSVTrack, 0),
+		Tracks:      make([]CSVCompareTrack, len(tracks)),
+	}
+
+	for i, track := range tracks {
+		compared := CSVCompareTrack{Track: track}
+		if path, matchedBy := index.find(track); path != "" {
+			compared.Found = true
+			compared.FilePath = path
+			compared.MatchedBy = matchedBy
+		}
+
+		if compared.Found {
+			result.FoundTracks++
+		} else {
+			result.Missing = append(result.Missing, track)
+		}
+		result.Tracks[i] = compared
+	}
+
+	result.MissingTracks = len(result.Missing)
+	fmt.Printf("[CSV Compare] %d/%d tracks found, %d missing\n", result.FoundTracks, result.TotalTracks, result.MissingTracks)
+	return result, nil
+}
@@ -0,0 +1,139 @@
+package backend
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// spotifyTrackLink finds a Spotify track URL or URI anywhere in a line
+var spotifyTrackLink = regexp.MustCompile(`(?:spotify:track:[0-9A-Za-z]{22}|(?:https?://)?(?:open|play)\.spotify\.com/\S+)`)
+
+// ParsePlaylistFile parses a CSV, plain text or M3U playlist, keeping the original order
+func ParsePlaylistFile(filePath string) ([]CSVTrack, error) {
+	tracks, _, err := ParsePlaylistFileWithLocal(filePath, CSVSortOriginal)
+	if err == nil && len(tracks) == 0 {
+		return nil, fmt.Errorf("no valid tracks found in playlist file")
+	}
+	return tracks, err
+}
+
+// ParsePlaylistFileWithLocal detects the playlist format from the extension. CSV files go to
+// the CSV parser; .txt, .m3u and .m3u8 files are read line by line for Spotify track links.
+func ParsePlaylistFileWithLocal(filePath string, sortBy string) ([]CSVTrack, []CSVTrack, error) {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".txt", ".m3u", ".m3u8":
+		return parseLinePlaylist(filePath, sortBy)
+	default:
+		return ParseCSVPlaylistWithLocal(filePath, sortBy)
+	}
+}
+
+// parseLinePlaylist reads one entry per line. For M3U, "#EXTINF:<seconds>,<artist> - <title>"
+// applies to the next entry; entries without a Spotify link become local tracks when they have
+// a title, so they can still be matched by name.
+func parseLinePlaylist(filePath string, sortBy string) ([]CSVTrack, []CSVTrack, error) {
+	fmt.Printf("\n[Playlist Parser] Opening file: %s\n", filePath)
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		fmt.Printf("[Playlist Parser] ERROR opening file: %v\n", err)
+		return nil, nil, fmt.Errorf("failed to open playlist file: %v", err)
+	}
+	defer file.Close()
+
+	var tracks, localTracks []CSVTrack
+	var pending *CSVTrack
+	lineCount := 0
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lineCount++
+		line := strings.TrimSpace(scanner.Text())
+		if lineCount == 1 {
+			line = strings.TrimPrefix(line, "\uFEFF")
+		}
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "#") {
+			if info, ok := strings.CutPrefix(line, "#EXTINF:"); ok {
+				pending = parseEXTINF(info)
+			}
+			continue
+		}
+
+		track := CSVTrack{}
+		if pending != nil {
+			track = *pending
+			pending = nil
+		}
+
+		if link := spotifyTrackLink.FindString(line); link != "" {
+			track.SpotifyID = spotifyTrackIDFromCSV(link)
+		} else {
+			track.SpotifyID = spotifyTrackIDFromCSV(line)
+		}
+
+		if track.SpotifyID == "" {
+			if track.TrackName == "" {
+				fmt.Printf("[Playlist Parser] Line %d: Skipping - no Spotify track link\n", lineCount)
+				continue
+			}
+			fmt.Printf("[Playlist Parser] Line %d: Local/unavailable track: %s - %s\n", lineCount, track.TrackName, track.ArtistName)
+			track.TrackURI = line
+			track.IsLocal = true
+			track.Position = len(localTracks) + 1
+			localTracks = append(localTracks, track)
+			continue
+		}
+
+		track.TrackURI = "spotify:track:" + track.SpotifyID
+		track.Position = len(tracks) + 1
+		tracks = append(tracks, track)
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Printf("[Playlist Parser] ERROR reading file: %v\n", err)
+		return nil, nil, fmt.Errorf("failed to read playlist file: %v", err)
+	}
+
+	fmt.Printf("[Playlist Parser] Processed %d lines, found %d valid tracks, %d local/unavailable\n", lineCount, len(tracks), len(localTracks))
+
+	if len(tracks) == 0 && len(localTracks) == 0 {
+		fmt.Println("[Playlist Parser] ERROR: No valid tracks found")
+		return nil, nil, fmt.Errorf("no valid tracks found in playlist file")
+	}
+
+	return SortCSVTracks(tracks, sortBy), localTracks, nil
+}
+
+// parseEXTINF reads the duration and "Artist - Title" display name of an #EXTINF line
+func parseEXTINF(info string) *CSVTrack {
+	track := &CSVTrack{}
+
+	duration, title, found := strings.Cut(info, ",")
+	if !found {
+		title = ""
+	}
+	// Attributes such as tvg-id="..." may follow the duration
+	if fields := strings.Fields(duration); len(fields) > 0 {
+		if seconds, err := strconv.Atoi(fields[0]); err == nil && seconds > 0 {
+			track.DurationMs = seconds * 1000
+		}
+	}
+
+	title = strings.TrimSpace(title)
+	if artist, name, ok := strings.Cut(title, " - "); ok {
+		track.ArtistName = strings.TrimSpace(artist)
+		track.TrackName = strings.TrimSpace(name)
+	} else {
+		track.TrackName = title
+	}
+	return track
+}
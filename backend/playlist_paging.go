@@ -0,0 +1,49 @@
+package backend
+
+import "sync"
+
+// PlaylistFetchProgress reports how far a large playlist's track pages have been fetched
+type PlaylistFetchProgress struct {
+	PlaylistID string `json:"playlist_id"`
+	Fetched    int    `json:"fetched"`
+	Total      int    `json:"total"`
+	Active     bool   `json:"active"`
+}
+
+var (
+	maxPlaylistTracks     int
+	maxPlaylistTracksLock sync.RWMutex
+
+	playlistFetchProgress     PlaylistFetchProgress
+	playlistFetchProgressLock sync.RWMutex
+)
+
+// SetMaxPlaylistTracks caps how many tracks are fetched from one playlist. 0 fetches every page.
+func SetMaxPlaylistTracks(max int) {
+	if max < 0 {
+		max = 0
+	}
+	maxPlaylistTracksLock.Lock()
+	maxPlaylistTracks = max
+	maxPlaylistTracksLock.Unlock()
+}
+
+// GetMaxPlaylistTracks returns the configured playlist track cap (0 = unlimited)
+func GetMaxPlaylistTracks() int {
+	maxPlaylistTracksLock.RLock()
+	defer maxPlaylistTracksLock.RUnlock()
+	return maxPlaylistTracks
+}
+
+func setPlaylistFetchProgress(progress PlaylistFetchProgress) {
+	playlistFetchProgressLock.Lock()
+	playlistFetchProgress = progress
+	playlistFetchProgressLock.Unlock()
+}
+
+// GetPlaylistFetchProgress returns the progress of the current (or last) playlist fetch
+func GetPlaylistFetchProgress() PlaylistFetchProgress {
+	playlistFetchProgressLock.RLock()
+	defer playlistFetchProgressLock.RUnlock()
+	return playlistFetchProgress
+}
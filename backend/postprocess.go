@@ -0,0 +1,264 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const maxPostProcessWorkers = 3
+
+// PostProcessJob describes the enrichment to apply to a track after its audio has been downloaded
+type PostProcessJob struct {
+	FilePath             string
+	SpotifyID            string
+	TrackName            string
+	ArtistName           string
+	AlbumName            string
+	CoverURL             string
+	EmbedCover           bool
+	EmbedMaxQualityCover bool
+	EmbedLyrics          bool
+	PreferLocalLyrics    bool
+	SyncedLyricsOnly     bool   // Skip embedding when only plain lyrics are found
+	PlainLyricsSidecar   bool   // With SyncedLyricsOnly, write skipped plain lyrics to a .txt sidecar
+	WriteLRCSidecar      bool   // Also save the lyrics as a .lrc next to the audio file
+	OverwriteLRCSidecar  bool   // Replace an existing .lrc instead of keeping it
+	FinalMoveDir         string // Move the finished file here after enrichment and an integrity check
+	BaseDir              string // Download folder; the layout below it is kept in FinalMoveDir
+	ItemID               string // Queue item whose file path follows the move
+}
+
+var (
+	postProcessJobs    chan PostProcessJob
+	postProcessPending sync.WaitGroup
+	postProcessOnce    sync.Once
+)
+
+// startPostProcessWorkers launches the bounded worker pool on first use
+func startPostProcessWorkers() {
+	postProcessJobs = make(chan PostProcessJob, 100)
+	for i := 0; i < maxPostProcessWorkers; i++ {
+		go func() {
+			for job := range postProcessJobs {
+				runPostProcessJob(job)
+				postProcessPending.Done()
+			}
+		}()
+	}
+}
+
+// EnqueuePostProcess schedules cover and lyrics enrichment for a downloaded track so the
+// download loop can move on to the next track. Blocks only when the queue is full.
+func EnqueuePostProcess(job PostProcessJob) {
+	if !job.EmbedCover && !job.EmbedLyrics && !job.WriteLRCSidecar && !job.PlainLyricsSidecar && job.FinalMoveDir == "" {
+		return
+	}
+
+	postProcessOnce.Do(startPostProcessWorkers)
+	postProcessPending.Add(1)
+	postProcessJobs <- job
+}
+
+// WaitForPostProcessing blocks until every queued enrichment job has finished
+func WaitForPostProcessing() {
+	postProcessPending.Wait()
+}
+
+// runPostProcessJob fetches the cover and lyrics concurrently, then embeds them one after
+// another since both rewrite the same file. Transient failures are retried with backoff; final
+// failures are recorded as warnings for the file.
+func runPostProcessJob(job PostProcessJob) {
+	fmt.Printf("[Post-Process] Enriching: %s\n", job.FilePath)
+
+	var wg sync.WaitGroup
+	coverPath := ""
+	lyrics := ""
+	lyricsSynced := false
+	isFLAC := strings.HasSuffix(job.FilePath, ".flac")
+	writeSidecar := job.WriteLRCSidecar && (job.OverwriteLRCSidecar || !lrcSidecarExists(job.FilePath))
+	// Sidecars don't depend on embedding, which may be off or skipped because the source had lyrics
+	wantSidecar := writeSidecar || (job.PlainLyricsSidecar && job.SyncedLyricsOnly)
+
+	if job.EmbedCover && job.CoverURL != "" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			path := job.FilePath + ".cover.jpg"
+			err := retryEnrichStep("Cover download", func() error {
+				return NewCoverClient().DownloadCoverToPath(job.CoverURL, path, job.EmbedMaxQualityCover)
+			})
+			if err != nil {
+				fmt.Printf("[Post-Process] Failed to download cover: %v\n", err)
+				recordEnrichWarning(job, fmt.Sprintf("cover download failed: %v", err))
+				os.Remove(path)
+				return
+			}
+			coverPath = path
+		}()
+	}
+
+	if job.EmbedLyrics && job.PreferLocalLyrics && isFLAC {
+		if localLyrics, sidecarPath := readLocalLyricsSidecar(job.FilePath); localLyrics != "" {
+			// In synced-only mode an unsynced sidecar doesn't count; look online for synced lyrics instead
+			if synced := isSyncedLyrics(parseLRCText(localLyrics)); synced || !job.SyncedLyricsOnly {
+				fmt.Printf("[Post-Process] Using local lyrics: %s\n", sidecarPath)
+				lyrics = localLyrics
+				lyricsSynced = synced
+			}
+		}
+	}
+
+	// Lyrics the source already embedded are reused for the sidecar instead of fetched again
+	if wantSidecar && !job.EmbedLyrics {
+		if embedded, err := ExtractLyrics(job.FilePath); err == nil && strings.TrimSpace(embedded) != "" {
+			if synced := isSyncedLyrics(parseLRCText(embedded)); synced || !job.SyncedLyricsOnly {
+				fmt.Println("[Post-Process] Using embedded lyrics for sidecar")
+				lyrics = embedded
+				lyricsSynced = synced
+			}
+		}
+	}
+
+	// Sidecars work for any format; only FLAC gets lyrics embedded
+	if ((job.EmbedLyrics && isFLAC) || wantSidecar) && lyrics == "" && job.SpotifyID != "" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lyricsClient := NewLyricsClient()
+			var lyricsResp *LyricsResponse
+			var source string
+			err := retryEnrichStep("Lyrics fetch", func() error {
+				var fetchErr error
+				lyricsResp, source, fetchErr = lyricsClient.FetchLyricsAllSources(job.SpotifyID, job.TrackName, job.ArtistName)
+				return fetchErr
+			})
+			if err != nil {
+				fmt.Printf("[Post-Process] No lyrics found: %v\n", err)
+				if !isPermanentEnrichError(err) {
+					recordEnrichWarning(job, fmt.Sprintf("lyrics fetch failed: %v", err))
+				}
+				return
+			}
+			if lyricsResp == nil || len(lyricsResp.Lines) == 0 {
+				fmt.Println("[Post-Process] No lyrics content found")
+				return
+			}
+			fmt.Printf("[Post-Process] Lyrics found from: %s (%s, %d lines)\n", source, lyricsResp.SyncType, len(lyricsResp.Lines))
+			lyricsSynced = isSyncedLyrics(lyricsResp)
+			if job.SyncedLyricsOnly && !lyricsSynced {
+				skipPlainLyrics(job, plainLyricsText(lyricsResp))
+				return
+			}
+			// Same header as a downloaded .lrc sidecar, with the length read from the file itself
+			header := LRCHeader{Title: job.TrackName, Artist: job.ArtistName, Album: job.AlbumName}
+			if duration, err := readAudioDuration(job.FilePath); err == nil {
+				header.DurationMs = int64(duration * 1000)
+			}
+			lyrics = lyricsClient.ConvertToLRCWithHeader(lyricsResp, header)
+		}()
+	}
+
+	wg.Wait()
+
+	// The download held the placeholder back for this cover; use it now that the real one failed
+	placeholder := false
+	if job.EmbedCover && coverPath == "" && isFLAC {
+		if defaultCover := GetDefaultCover(); defaultCover != "" && fileExists(defaultCover) {
+			coverPath, placeholder = defaultCover, true
+		}
+	}
+
+	if coverPath != "" {
+		err := retryEnrichStep("Cover embed", func() error {
+			return EmbedCoverArtOnly(job.FilePath, coverPath)
+		})
+		if err != nil {
+			fmt.Printf("[Post-Process] Failed to embed cover: %v\n", err)
+			recordEnrichWarning(job, fmt.Sprintf("cover embed failed: %v", err))
+		} else if placeholder {
+			recordMissingCoverTrack(job.FilePath)
+			fmt.Println("[Post-Process] Default cover embedded")
+		} else {
+			clearMissingCoverTrack(job.FilePath)
+			fmt.Println("[Post-Process] Cover embedded")
+		}
+		if !placeholder {
+			os.Remove(coverPath)
+		}
+	}
+
+	if writeSidecar && lyrics != "" {
+		if sidecarPath, err := writeLRCSidecar(job.FilePath, lyrics); err != nil {
+			fmt.Printf("[Post-Process] %v\n", err)
+			recordEnrichWarning(job, err.Error())
+		} else {
+			fmt.Printf("[Post-Process] Lyrics saved to %s\n", filepath.Base(sidecarPath))
+		}
+	}
+
+	if lyrics != "" && job.EmbedLyrics && isFLAC {
+		err := retryEnrichStep("Lyrics embed", func() error {
+			return EmbedLyricsOnly(job.FilePath, lyrics)
+		})
+		if err != nil {
+			fmt.Printf("[Post-Process] Failed to embed lyrics: %v\n", err)
+			recordEnrichWarning(job, fmt.Sprintf("lyrics embed failed: %v", err))
+		} else {
+			fmt.Println("[Post-Process] Lyrics embedded")
+			decision := LyricsDecisionEmbeddedPlain
+			if lyricsSynced {
+				decision = LyricsDecisionEmbeddedSynced
+			}
+			recordLyricsDecision(LyricsDecision{FilePath: job.FilePath, Decision: decision})
+		}
+	}
+
+	// Only fully processed files reach the import folder, so media servers never see partial ones
+	if job.FinalMoveDir != "" {
+		moveToFinalDir(job)
+	}
+}
+
+// skipPlainLyrics leaves unsynced lyrics out of the tags in synced-only mode, optionally
+// keeping them as a .txt sidecar
+func skipPlainLyrics(job PostProcessJob, text string) {
+	decision := LyricsDecision{FilePath: job.FilePath, Decision: LyricsDecisionSkippedPlain}
+	if job.PlainLyricsSidecar && text != "" {
+		if sidecarPath, err := writePlainLyricsSidecar(job.FilePath, text); err != nil {
+			fmt.Printf("[Post-Process] %v\n", err)
+		} else {
+			decision.Decision = LyricsDecisionPlainSidecar
+			decision.Sidecar = sidecarPath
+		}
+	}
+	fmt.Printf("[Post-Process] Only plain lyrics found, not embedding (%s)\n", decision.Decision)
+	recordLyricsDecision(decision)
+}
+
+// lrcSidecarExists reports whether a .lrc with the audio file's base name is already present
+func lrcSidecarExists(audioPath string) bool {
+	return fileExists(strings.TrimSuffix(audioPath, filepath.Ext(audioPath)) + ".lrc")
+}
+
+// writeLRCSidecar saves LRC lyrics next to the audio file under the same base name, so players
+// that only read sidecars pick them up
+func writeLRCSidecar(audioPath, lrc string) (string, error) {
+	sidecarPath := strings.TrimSuffix(audioPath, filepath.Ext(audioPath)) + ".lrc"
+	if err := os.WriteFile(sidecarPath, []byte(lrc+"\n"), 0644); err != nil {
+		return "", fmt.Errorf("failed to write lyrics sidecar: %v", err)
+	}
+	return sidecarPath, nil
+}
+
+// readLocalLyricsSidecar returns the contents of a .lrc file sitting next to the audio file, if any
+func readLocalLyricsSidecar(audioPath string) (string, string) {
+	sidecarPath := strings.TrimSuffix(audioPath, filepath.Ext(audioPath)) + ".lrc"
+	data, err := os.ReadFile(sidecarPath)
+	if err != nil {
+		return "", ""
+	}
+	return strings.TrimSpace(string(data)), sidecarPath
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -16,6 +17,7 @@ const (
 	StatusCompleted   DownloadStatus = "completed"
 	StatusFailed      DownloadStatus = "failed"
 	StatusSkipped     DownloadStatus = "skipped"
+	StatusUnavailable DownloadStatus = "unavailable" // Not on any supported service, as opposed to a failed download
 )
 
 // DownloadItem represents a single item in the download queue
@@ -26,13 +28,19 @@ type DownloadItem struct {
 	AlbumName    string         `json:"album_name"`
 	ISRC         string         `json:"isrc"`
 	Status       DownloadStatus `json:"status"`
-	Progress     float64        `json:"progress"`      // MB downloaded
-	TotalSize    float64        `json:"total_size"`    // MB total (if known)
-	Speed        float64        `json:"speed"`         // MB/s
-	StartTime    int64          `json:"start_time"`    // Unix timestamp
-	EndTime      int64          `json:"end_time"`      // Unix timestamp
-	ErrorMessage string         `json:"error_message"` // If failed
-	FilePath     string         `json:"file_path"`     // Final file path
+	Progress     float64        `json:"progress"`              // MB downloaded
+	TotalSize    float64        `json:"total_size"`            // MB total (if known)
+	Speed        float64        `json:"speed"`                 // MB/s
+	StartTime    int64          `json:"start_time"`            // Unix timestamp
+	EndTime      int64          `json:"end_time"`              // Unix timestamp
+	ErrorMessage string         `json:"error_message"`         // If failed
+	FilePath     string         `json:"file_path"`             // Final file path
+	OutputDir    string         `json:"output_dir,omitempty"`  // Per-item destination overriding the batch folder
+	SpotifyURL   string         `json:"spotify_url,omitempty"` // Set for unavailable items so users can look elsewhere
+	Source       string         `json:"source,omitempty"`      // "playlist" or "album"; picks position vs album track number
+	SpotifyID    string         `json:"spotify_id,omitempty"`  // Lets failed items be exported for a retry
+	Warnings     []string       `json:"warnings,omitempty"`    // Cover, lyrics or import-folder failures from post-processing
+	Prefetched   bool           `json:"prefetched,omitempty"`  // ISRC, metadata and service URLs resolved ahead of time
 }
 
 // Global progress tracker
@@ -40,6 +48,7 @@ var (
 	currentProgress     float64
 	currentProgressLock sync.RWMutex
 	isDownloading       bool
+	activeDownloads     int // Items downloading at once; isDownloading stays set until all finish
 	downloadingLock     sync.RWMutex
 	currentSpeed        float64
 	speedLock           sync.RWMutex
@@ -53,8 +62,22 @@ var (
 	totalDownloadedLock sync.RWMutex
 	sessionStartTime    int64
 	sessionStartLock    sync.RWMutex
+
+	// stateVersion increments on every progress or queue change so pollers can skip redundant renders
+	stateVersion int64
 )
 
+// bumpStateVersion marks the progress/queue state as changed
+func bumpStateVersion() {
+	atomic.AddInt64(&stateVersion, 1)
+	scheduleQueueSave()
+}
+
+// GetStateVersion returns a counter that increases whenever progress or queue state changes
+func GetStateVersion() int64 {
+	return atomic.LoadInt64(&stateVersion)
+}
+
 // ProgressInfo represents download progress information
 type ProgressInfo struct {
 	IsDownloading bool    `json:"is_downloading"`
@@ -73,26 +96,24 @@ type DownloadQueueInfo struct {
 	CompletedCount   int            `json:"completed_count"`
 	FailedCount      int            `json:"failed_count"`
 	SkippedCount     int            `json:"skipped_count"`
+	UnavailableCount int            `json:"unavailable_count"`
+	StateVersion     int64          `json:"state_version"`
 }
 
-// GetDownloadProgress returns current download progress
+// GetDownloadProgress returns current download progress. All locks are held together so the
+// snapshot is never torn between a progress update and a speed update.
 func GetDownloadProgress() ProgressInfo {
 	downloadingLock.RLock()
-	downloading := isDownloading
-	downloadingLock.RUnlock()
-
+	defer downloadingLock.RUnlock()
 	currentProgressLock.RLock()
-	progress := currentProgress
-	currentProgressLock.RUnlock()
-
+	defer currentProgressLock.RUnlock()
 	speedLock.RLock()
-	speed := currentSpeed
-	speedLock.RUnlock()
+	defer speedLock.RUnlock()
 
 	return ProgressInfo{
-		IsDownloading: downloading,
-		MBDownloaded:  progress,
-		SpeedMBps:     speed,
+		IsDownloading: isDownloading,
+		MBDownloaded:  currentProgress,
+		SpeedMBps:     currentSpeed,
 	}
 }
 
@@ -101,6 +122,7 @@ func SetDownloadSpeed(mbps float64) {
 	speedLock.Lock()
 	currentSpeed = mbps
 	speedLock.Unlock()
+	bumpStateVersion()
 }
 
 // SetDownloadProgress updates the current download progress
@@ -108,15 +130,24 @@ func SetDownloadProgress(mbDownloaded float64) {
 	currentProgressLock.Lock()
 	currentProgress = mbDownloaded
 	currentProgressLock.Unlock()
+	bumpStateVersion()
 }
 
-// SetDownloading sets the downloading state
+// SetDownloading marks a download as started or finished. Calls are counted, so with several
+// workers the app stays busy until the last one finishes.
 func SetDownloading(downloading bool) {
 	downloadingLock.Lock()
-	isDownloading = downloading
+	if downloading {
+		activeDownloads++
+	} else if activeDownloads > 0 {
+		activeDownloads--
+	}
+	isDownloading = activeDownloads > 0
+	idle := !isDownloading
 	downloadingLock.Unlock()
+	bumpStateVersion()
 
-	if !downloading {
+	if idle {
 		// Reset progress when download completes
 		SetDownloadProgress(0)
 		SetDownloadSpeed(0)
@@ -132,6 +163,8 @@ type ProgressWriter struct {
 	lastTime    int64
 	lastBytes   int64
 	itemID      string // Track which download item this belongs to
+	offset      int64  // Bytes already on disk before this transfer, when resuming
+	expected    int64  // Full file size in bytes, 0 if unknown
 }
 
 func NewProgressWriter(writer io.Writer) *ProgressWriter {
@@ -143,7 +176,7 @@ func NewProgressWriter(writer io.Writer) *ProgressWriter {
 		startTime:   now,
 		lastTime:    now,
 		lastBytes:   0,
-		itemID:      "",
+		itemID:      progressItemID(writer),
 	}
 }
 
@@ -158,10 +191,20 @@ func getCurrentTimeMillis() int64 {
 	return time.Now().UnixMilli()
 }
 
+// setTransferSize tells the writer where a resumed transfer starts and how large the whole file
+// is, so progress events report the real position and an ETA
+func (pw *ProgressWriter) setTransferSize(offset, expected int64) {
+	pw.offset = offset
+	pw.expected = expected
+}
+
 func (pw *ProgressWriter) Write(p []byte) (int, error) {
 	n, err := pw.writer.Write(p)
 	pw.total += int64(n)
 
+	// Byte-level events for the frontend's per-item progress bar; EmitProgress throttles them
+	EmitProgress(pw.itemID, pw.offset+pw.total, pw.expected)
+
 	// Report progress every 256KB for smoother updates
 	if pw.total-pw.lastPrinted >= 256*1024 {
 		mbDownloaded := float64(pw.total) / (1024 * 1024)
@@ -206,6 +249,7 @@ func (pw *ProgressWriter) GetTotal() int64 {
 func AddToQueue(id, trackName, artistName, albumName, isrc string) {
 	downloadQueueLock.Lock()
 	defer downloadQueueLock.Unlock()
+	defer bumpStateVersion()
 
 	item := DownloadItem{
 		ID:         id,
@@ -231,10 +275,107 @@ func AddToQueue(id, trackName, artistName, albumName, isrc string) {
 	sessionStartLock.Unlock()
 }
 
+// SetItemOutputDir routes a queued item to its own destination folder
+func SetItemOutputDir(id, outputDir string) {
+	downloadQueueLock.Lock()
+	defer downloadQueueLock.Unlock()
+	defer bumpStateVersion()
+
+	for i := range downloadQueue {
+		if downloadQueue[i].ID == id {
+			downloadQueue[i].OutputDir = outputDir
+			break
+		}
+	}
+}
+
+// GetItemOutputDir returns the per-item destination folder, or empty if the item uses the batch folder
+func GetItemOutputDir(id string) string {
+	downloadQueueLock.RLock()
+	defer downloadQueueLock.RUnlock()
+
+	for _, item := range downloadQueue {
+		if item.ID == id {
+			return item.OutputDir
+		}
+	}
+	return ""
+}
+
+// SetItemSource records whether a queued item came from a playlist or an album
+func SetItemSource(id, source string) {
+	downloadQueueLock.Lock()
+	defer downloadQueueLock.Unlock()
+	defer bumpStateVersion()
+
+	for i := range downloadQueue {
+		if downloadQueue[i].ID == id {
+			downloadQueue[i].Source = source
+			break
+		}
+	}
+}
+
+// SetItemFilePath updates a finished item's location after it has been moved
+func SetItemFilePath(id, filePath string) {
+	downloadQueueLock.Lock()
+	defer downloadQueueLock.Unlock()
+	defer bumpStateVersion()
+
+	for i := range downloadQueue {
+		if downloadQueue[i].ID == id {
+			downloadQueue[i].FilePath = filePath
+			break
+		}
+	}
+}
+
+// addItemWarning attaches a post-processing failure to a finished item
+func addItemWarning(id, warning string) {
+	downloadQueueLock.Lock()
+	defer downloadQueueLock.Unlock()
+	defer bumpStateVersion()
+
+	for i := range downloadQueue {
+		if downloadQueue[i].ID == id {
+			downloadQueue[i].Warnings = append(downloadQueue[i].Warnings, warning)
+			break
+		}
+	}
+}
+
+// SetItemSpotifyID records the Spotify track ID a queued item downloads
+func SetItemSpotifyID(id, spotifyID string) {
+	downloadQueueLock.Lock()
+	defer downloadQueueLock.Unlock()
+	defer bumpStateVersion()
+
+	for i := range downloadQueue {
+		if downloadQueue[i].ID == id {
+			downloadQueue[i].SpotifyID = spotifyID
+			break
+		}
+	}
+}
+
+// GetItemSource returns the item's source, or empty if none was recorded
+func GetItemSource(id string) string {
+	downloadQueueLock.RLock()
+	defer downloadQueueLock.RUnlock()
+
+	for _, item := range downloadQueue {
+		if item.ID == id {
+			return item.Source
+		}
+	}
+	return ""
+}
+
 // StartDownloadItem marks an item as currently downloading
 func StartDownloadItem(id string) {
 	downloadQueueLock.Lock()
 	defer downloadQueueLock.Unlock()
+	defer bumpStateVersion()
 
 	for i := range downloadQueue {
 		if downloadQueue[i].ID == id {
@@ -254,6 +395,7 @@ func StartDownloadItem(id string) {
 func UpdateItemProgress(id string, progress, speed float64) {
 	downloadQueueLock.Lock()
 	defer downloadQueueLock.Unlock()
+	defer bumpStateVersion()
 
 	for i := range downloadQueue {
 		if downloadQueue[i].ID == id {
@@ -275,6 +417,7 @@ func GetCurrentItemID() string {
 func CompleteDownloadItem(id, filePath string, finalSize float64) {
 	downloadQueueLock.Lock()
 	defer downloadQueueLock.Unlock()
+	defer bumpStateVersion()
 
 	for i := range downloadQueue {
 		if downloadQueue[i].ID == id {
@@ -297,6 +440,7 @@ func CompleteDownloadItem(id, filePath string, finalSize float64) {
 func FailDownloadItem(id, errorMsg string) {
 	downloadQueueLock.Lock()
 	defer downloadQueueLock.Unlock()
+	defer bumpStateVersion()
 
 	for i := range downloadQueue {
 		if downloadQueue[i].ID == id {
@@ -312,6 +456,7 @@ func FailDownloadItem(id, errorMsg string) {
 func SkipDownloadItem(id, filePath string) {
 	downloadQueueLock.Lock()
 	defer downloadQueueLock.Unlock()
+	defer bumpStateVersion()
 
 	for i := range downloadQueue {
 		if downloadQueue[i].ID == id {
@@ -323,32 +468,78 @@ func SkipDownloadItem(id, filePath string) {
 	}
 }
 
-// GetDownloadQueue returns the complete download queue state
+// MarkDownloadItemUnavailable marks an item whose track exists on none of the supported services
+func MarkDownloadItemUnavailable(id, spotifyID string) {
+	downloadQueueLock.Lock()
+	defer downloadQueueLock.Unlock()
+	defer bumpStateVersion()
+
+	for i := range downloadQueue {
+		if downloadQueue[i].ID == id {
+			downloadQueue[i].Status = StatusUnavailable
+			downloadQueue[i].EndTime = time.Now().Unix()
+			downloadQueue[i].ErrorMessage = "Not available on any supported service"
+			if spotifyID != "" {
+				downloadQueue[i].SpotifyID = spotifyID
+				downloadQueue[i].SpotifyURL = "https://open.spotify.com/track/" + spotifyID
+			}
+			break
+		}
+	}
+}
+
+// batchItemFiles reports whether none of the items is still queued or downloading, and returns the
+// file paths of those that completed or were skipped as already present. Items no longer in the
+// queue count as finished.
+func batchItemFiles(ids []string) ([]string, bool) {
+	downloadQueueLock.RLock()
+	defer downloadQueueLock.RUnlock()
+
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	var files []string
+	for _, item := range downloadQueue {
+		if !wanted[item.ID] {
+			continue
+		}
+		switch item.Status {
+		case StatusQueued, StatusDownloading:
+			return nil, false
+		case StatusCompleted, StatusSkipped:
+			if item.FilePath != "" {
+				files = append(files, item.FilePath)
+			}
+		}
+	}
+	return files, true
+}
+
+// GetDownloadQueue returns the complete download queue state. Every lock is held until the
+// copy is made, in the same order writers take them, so counts, totals and items agree.
 func GetDownloadQueue() DownloadQueueInfo {
 	// Auto-reset session if all downloads are complete
 	ResetSessionIfComplete()
 
 	downloadQueueLock.RLock()
 	defer downloadQueueLock.RUnlock()
-
 	downloadingLock.RLock()
-	downloading := isDownloading
-	downloadingLock.RUnlock()
-
+	defer downloadingLock.RUnlock()
 	speedLock.RLock()
-	speed := currentSpeed
-	speedLock.RUnlock()
-
+	defer speedLock.RUnlock()
 	totalDownloadedLock.RLock()
-	total := totalDownloaded
-	totalDownloadedLock.RUnlock()
-
+	defer totalDownloadedLock.RUnlock()
 	sessionStartLock.RLock()
-	sessionStart := sessionStartTime
-	sessionStartLock.RUnlock()
+	defer sessionStartLock.RUnlock()
+
+	// Read the version first: a change racing with this call bumps it past what we report,
+	// so the next poll still sees a newer version
+	version := GetStateVersion()
 
 	// Count statuses
-	var queued, completed, failed, skipped int
+	var queued, completed, failed, skipped, unavailable int
 	for _, item := range downloadQueue {
 		switch item.Status {
 		case StatusQueued:
@@ -359,23 +550,27 @@ func GetDownloadQueue() DownloadQueueInfo {
 			failed++
 		case StatusSkipped:
 			skipped++
+		case StatusUnavailable:
+			unavailable++
 		}
 	}
 
-	// Create a copy of the queue
+	// DownloadItem holds only values, so copying the slice is a deep copy
 	queueCopy := make([]DownloadItem, len(downloadQueue))
 	copy(queueCopy, downloadQueue)
 
 	return DownloadQueueInfo{
-		IsDownloading:    downloading,
+		IsDownloading:    isDownloading,
 		Queue:            queueCopy,
-		CurrentSpeed:     speed,
-		TotalDownloaded:  total,
-		SessionStartTime: sessionStart,
+		CurrentSpeed:     currentSpeed,
+		TotalDownloaded:  totalDownloaded,
+		SessionStartTime: sessionStartTime,
 		QueuedCount:      queued,
 		CompletedCount:   completed,
 		FailedCount:      failed,
 		SkippedCount:     skipped,
+		UnavailableCount: unavailable,
+		StateVersion:     version,
 	}
 }
 
@@ -383,6 +578,7 @@ func GetDownloadQueue() DownloadQueueInfo {
 func ClearDownloadQueue() {
 	downloadQueueLock.Lock()
 	defer downloadQueueLock.Unlock()
+	defer bumpStateVersion()
 
 	// Keep only queued and downloading items
 	newQueue := make([]DownloadItem, 0)
@@ -399,6 +595,7 @@ func ClearAllDownloads() {
 	downloadQueueLock.Lock()
 	downloadQueue = []DownloadItem{}
 	downloadQueueLock.Unlock()
+	clearPrefetchedTracks()
 
 	totalDownloadedLock.Lock()
 	totalDownloaded = 0
@@ -415,13 +612,18 @@ func ClearAllDownloads() {
 	// Reset current progress and speed
 	SetDownloadProgress(0)
 	SetDownloadSpeed(0)
+	bumpStateVersion()
 }
 
 // CancelAllQueuedItems marks all queued items as skipped (cancelled)
 // This is called when user stops a download or when batch download completes
 func CancelAllQueuedItems() {
+	// Runs after the queue lock is released; workers take the job lock before the queue lock
+	defer stopDownloadWorkersIfIdle()
+
 	downloadQueueLock.Lock()
 	defer downloadQueueLock.Unlock()
+	defer bumpStateVersion()
 
 	for i := range downloadQueue {
 		if downloadQueue[i].Status == StatusQueued {
@@ -449,11 +651,17 @@ func ResetSessionIfComplete() {
 	// But keep the queue items for history visibility
 	if !hasActiveOrQueued {
 		sessionStartLock.Lock()
+		changed := sessionStartTime != 0
 		sessionStartTime = 0
 		sessionStartLock.Unlock()
 
 		totalDownloadedLock.Lock()
+		changed = changed || totalDownloaded != 0
 		totalDownloaded = 0
 		totalDownloadedLock.Unlock()
+
+		if changed {
+			bumpStateVersion()
+		}
 	}
 }
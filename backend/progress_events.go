@@ -0,0 +1,102 @@
+package backend
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// progressEventInterval is the minimum time between two progress events for the same item
+const progressEventInterval = 250 * time.Millisecond
+
+// ItemProgressEvent is the payload of the "download:progress" event
+type ItemProgressEvent struct {
+	ItemID     string  `json:"item_id"`
+	Downloaded int64   `json:"downloaded"`            // Bytes
+	Total      int64   `json:"total"`                 // Bytes, 0 when the server didn't say
+	Speed      float64 `json:"speed"`                 // Bytes per second, smoothed
+	ETASeconds float64 `json:"eta_seconds,omitempty"` // Only when Total is known
+	Done       bool    `json:"done,omitempty"`
+}
+
+// itemTransfer tracks one item's last event, for throttling and the speed estimate
+type itemTransfer struct {
+	lastEmit  time.Time
+	lastBytes int64
+	speed     float64
+}
+
+var (
+	progressEventCtx     context.Context
+	progressEventCtxLock sync.RWMutex
+
+	itemTransfers     = make(map[string]*itemTransfer)
+	itemTransfersLock sync.Mutex
+)
+
+// SetProgressEventContext sets the Wails context progress events are emitted on. Until it is
+// set, EmitProgress does nothing.
+func SetProgressEventContext(ctx context.Context) {
+	progressEventCtxLock.Lock()
+	progressEventCtx = ctx
+	progressEventCtxLock.Unlock()
+}
+
+// EmitProgress sends a "download:progress" event with an item's bytes downloaded, total size,
+// speed and ETA. Calls closer together than 250ms are dropped, except the final one.
+func EmitProgress(itemID string, downloaded, total int64) {
+	if itemID == "" {
+		return
+	}
+	progressEventCtxLock.RLock()
+	ctx := progressEventCtx
+	progressEventCtxLock.RUnlock()
+	if ctx == nil {
+		return
+	}
+
+	done := total > 0 && downloaded >= total
+	now := time.Now()
+
+	itemTransfersLock.Lock()
+	transfer, ok := itemTransfers[itemID]
+	if !ok || downloaded < transfer.lastBytes {
+		// New transfer, or a restart from the beginning
+		transfer = &itemTransfer{lastEmit: now, lastBytes: downloaded}
+		itemTransfers[itemID] = transfer
+	}
+	elapsed := now.Sub(transfer.lastEmit)
+	if ok && elapsed < progressEventInterval && !done {
+		itemTransfersLock.Unlock()
+		return
+	}
+	if elapsed > 0 {
+		instant := float64(downloaded-transfer.lastBytes) / elapsed.Seconds()
+		if transfer.speed == 0 {
+			transfer.speed = instant
+		} else {
+			// Smooth out bursts so the ETA doesn't jump around
+			transfer.speed = 0.3*instant + 0.7*transfer.speed
+		}
+	}
+	transfer.lastEmit = now
+	transfer.lastBytes = downloaded
+	event := ItemProgressEvent{
+		ItemID:     itemID,
+		Downloaded: downloaded,
+		Total:      total,
+		Speed:      transfer.speed,
+		Done:       done,
+	}
+	if done {
+		delete(itemTransfers, itemID)
+	}
+	itemTransfersLock.Unlock()
+
+	if total > 0 && event.Speed > 0 && !done {
+		event.ETASeconds = float64(total-downloaded) / event.Speed
+	}
+	wailsRuntime.EventsEmit(ctx, "download:progress", event)
+}
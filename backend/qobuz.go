@@ -208,34 +208,15 @@ func (q *QobuzDownloader) DownloadFile(url, filepath string) error {
 		Timeout: 5 * time.Minute, // 5 minutes for large files
 	}
 
-	resp, err := downloadClient.Get(url)
-	if err != nil {
-		return fmt.Errorf("failed to download file: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("download failed with status %d", resp.StatusCode)
-	}
-
-	fmt.Printf("Creating file: %s\n", filepath)
-	out, err := os.Create(filepath)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
-	}
-	defer out.Close()
-
-	fmt.Println("Downloading...")
-	// Use progress writer to track download
-	pw := NewProgressWriter(out)
-	_, err = io.Copy(pw, resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
+	// Large hi-res files go over several ranged connections when enabled and supported
+	if done, err := downloadChunked(downloadClient, url, filepath); done {
+		return nil
+	} else if err != nil {
+		fmt.Printf("[Chunked] %v, retrying as a single stream\n", err)
 	}
 
-	// Print final size
-	fmt.Printf("\rDownloaded: %.2f MB (Complete)\n", float64(pw.GetTotal())/(1024*1024))
-	return nil
+	fmt.Printf("Downloading to: %s\n", filepath)
+	return downloadResumable(downloadClient, url, filepath, nil)
 }
 
 func (q *QobuzDownloader) DownloadCoverArt(coverURL, filepath string) error {
@@ -323,19 +304,19 @@ func buildQobuzFilename(title, artist, album, albumArtist, releaseDate string, t
 	return filename + ".flac"
 }
 
-func (q *QobuzDownloader) DownloadByISRC(isrc, outputDir, quality, filenameFormat string, includeTrackNumber bool, position int, spotifyTrackName, spotifyArtistName, spotifyAlbumName, spotifyAlbumArtist, spotifyReleaseDate string, useAlbumTrackNumber bool, spotifyCoverURL string, embedMaxQualityCover bool, spotifyTrackNumber, spotifyDiscNumber, spotifyTotalTracks int) (string, error) {
+func (q *QobuzDownloader) DownloadByISRC(isrc, outputDir, quality, filenameFormat string, includeTrackNumber bool, position int, spotifyTrackName, spotifyArtistName, spotifyAlbumName, spotifyAlbumArtist, spotifyReleaseDate string, useAlbumTrackNumber bool, spotifyCoverURL string, embedMaxQualityCover bool, spotifyTrackNumber, spotifyDiscNumber, spotifyTotalTracks int) (DownloadResult, error) {
 	fmt.Printf("Fetching track info for ISRC: %s\n", isrc)
 
 	// Create output directory if it doesn't exist
 	if outputDir != "." {
 		if err := os.MkdirAll(outputDir, 0755); err != nil {
-			return "", fmt.Errorf("failed to create output directory: %w", err)
+			return DownloadResult{}, fmt.Errorf("failed to create output directory: %w", err)
 		}
 	}
 
 	track, err := q.SearchByISRC(isrc)
 	if err != nil {
-		return "", err
+		return DownloadResult{}, err
 	}
 
 	// All metadata from Spotify - no fallback to Qobuz
@@ -355,11 +336,11 @@ func (q *QobuzDownloader) DownloadByISRC(isrc, outputDir, quality, filenameForma
 	fmt.Println("Getting download URL...")
 	downloadURL, err := q.GetDownloadURL(track.ID, quality)
 	if err != nil {
-		return "", fmt.Errorf("failed to get download URL: %w", err)
+		return DownloadResult{}, fmt.Errorf("failed to get download URL: %w", err)
 	}
 
 	if downloadURL == "" {
-		return "", fmt.Errorf("received empty download URL")
+		return DownloadResult{}, fmt.Errorf("received empty download URL")
 	}
 
 	// Show partial URL for security
@@ -377,21 +358,27 @@ func (q *QobuzDownloader) DownloadByISRC(isrc, outputDir, quality, filenameForma
 	// Check if file with same ISRC already exists (use Spotify ISRC)
 	if existingFile, exists := CheckISRCExists(outputDir, isrc); exists {
 		fmt.Printf("File with ISRC %s already exists: %s\n", isrc, existingFile)
-		return "EXISTS:" + existingFile, nil
+		return DownloadResult{Path: existingFile, AlreadyExisted: true}, nil
 	}
 
 	// Build filename based on format settings (use Spotify track number)
 	filename := buildQobuzFilename(safeTitle, safeArtist, safeAlbum, safeAlbumArtist, spotifyReleaseDate, spotifyTrackNumber, spotifyDiscNumber, filenameFormat, includeTrackNumber, position, useAlbumTrackNumber)
 	filepath := filepath.Join(outputDir, filename)
 
+	// Flag when Qobuz's album layout disagrees with Spotify's (bonus tracks, reordering)
+	if _, mismatch := ReconcileTrackNumber(spotifyTrackNumber, track.TrackNumber); mismatch {
+		recordTrackNumberMismatch(filepath, "qobuz", spotifyTrackNumber, track.TrackNumber)
+	}
+
 	if fileInfo, err := os.Stat(filepath); err == nil && fileInfo.Size() > 0 {
 		fmt.Printf("File already exists: %s (%.2f MB)\n", filepath, float64(fileInfo.Size())/(1024*1024))
-		return "EXISTS:" + filepath, nil
+		return DownloadResult{Path: filepath, AlreadyExisted: true}, nil
 	}
 
 	fmt.Printf("Downloading FLAC file to: %s\n", filepath)
-	if err := q.DownloadFile(downloadURL, filepath); err != nil {
-		return "", fmt.Errorf("failed to download file: %w", err)
+	resolve := func() (string, error) { return q.GetDownloadURL(track.ID, quality) }
+	if err := downloadWithFreshURL(downloadURL, resolve, func(url string) error { return q.DownloadFile(url, filepath) }); err != nil {
+		return DownloadResult{}, fmt.Errorf("failed to download file: %w", err)
 	}
 
 	fmt.Printf("Downloaded: %s\n", filepath)
@@ -433,9 +420,9 @@ func (q *QobuzDownloader) DownloadByISRC(isrc, outputDir, quality, filenameForma
 	}
 
 	if err := EmbedMetadata(filepath, metadata, coverPath); err != nil {
-		return "", fmt.Errorf("failed to embed metadata: %w", err)
+		return DownloadResult{}, fmt.Errorf("failed to embed metadata: %w", err)
 	}
 
 	fmt.Println("Metadata embedded successfully!")
-	return filepath, nil
+	return DownloadResult{Path: filepath}, nil
 }
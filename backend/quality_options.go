@@ -0,0 +1,227 @@
+package backend
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// QualityInfo is the best quality a service offers for a track
+type QualityInfo struct {
+	Available  bool   `json:"available"`
+	BitDepth   int    `json:"bit_depth,omitempty"`
+	SampleRate int    `json:"sample_rate,omitempty"` // Hz
+	Bitrate    int    `json:"bitrate,omitempty"`     // kbps, for lossy sources
+	Codec      string `json:"codec,omitempty"`
+	Label      string `json:"label,omitempty"` // Human-readable summary, e.g. "24-bit/96kHz"
+	Tier       string `json:"tier,omitempty"`  // HI_RES_LOSSLESS, LOSSLESS or HIGH
+	Lossless   bool   `json:"lossless_available"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Quality tiers, named after Tidal's audioQuality values
+const (
+	QualityTierHiRes    = "HI_RES_LOSSLESS"
+	QualityTierLossless = "LOSSLESS"
+	QualityTierHigh     = "HIGH"
+)
+
+// qualityTierRank orders tiers so the best source can be picked
+var qualityTierRank = map[string]int{
+	QualityTierHigh:     1,
+	QualityTierLossless: 2,
+	QualityTierHiRes:    3,
+}
+
+// bestQualityService returns the service offering the highest tier, then bit depth, then sample
+// rate, or "" when none has the track. Ties go to the earlier service in the download order.
+func bestQualityService(options map[string]QualityInfo) string {
+	best := ""
+	for _, service := range []string{"tidal", "qobuz", "amazon"} {
+		info, ok := options[service]
+		if !ok || !info.Available {
+			continue
+		}
+		if best == "" {
+			best = service
+			continue
+		}
+		current := options[best]
+		switch {
+		case qualityTierRank[info.Tier] != qualityTierRank[current.Tier]:
+			if qualityTierRank[info.Tier] > qualityTierRank[current.Tier] {
+				best = service
+			}
+		case info.BitDepth != current.BitDepth:
+			if info.BitDepth > current.BitDepth {
+				best = service
+			}
+		case info.SampleRate > current.SampleRate:
+			best = service
+		}
+	}
+	return best
+}
+
+var (
+	qualityOptionsCache     = make(map[string]map[string]QualityInfo)
+	qualityOptionsCacheLock sync.RWMutex
+)
+
+// qualityLabel formats bit depth and sample rate as "24-bit/96kHz"
+func qualityLabel(bitDepth, sampleRate int) string {
+	khz := float64(sampleRate) / 1000
+	if khz == float64(int(khz)) {
+		return fmt.Sprintf("%d-bit/%dkHz", bitDepth, int(khz))
+	}
+	return fmt.Sprintf("%d-bit/%.1fkHz", bitDepth, khz)
+}
+
+// GetTrackQualityOptions asks each service what quality it can provide for a track. Results are
+// cached per ISRC (or Spotify ID when no ISRC is known) for the rest of the session.
+func GetTrackQualityOptions(spotifyID, isrc string) (map[string]QualityInfo, error) {
+	if spotifyID == "" && isrc == "" {
+		return nil, fmt.Errorf("spotify ID or ISRC is required")
+	}
+
+	cacheKey := strings.ToUpper(strings.TrimSpace(isrc))
+	if cacheKey == "" {
+		cacheKey = "spotify:" + spotifyID
+	}
+
+	qualityOptionsCacheLock.RLock()
+	cached, ok := qualityOptionsCache[cacheKey]
+	qualityOptionsCacheLock.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	var urls *SongLinkURLs
+	if spotifyID != "" {
+		urls, _ = NewSongLinkClient().GetAllURLsFromSpotify(spotifyID)
+	}
+
+	options := collectQualityOptions(urls, isrc)
+
+	qualityOptionsCacheLock.Lock()
+	qualityOptionsCache[cacheKey] = options
+	qualityOptionsCacheLock.Unlock()
+
+	for service, info := range options {
+		if info.Available {
+			fmt.Printf("[Quality] %s: %s\n", service, info.Label)
+		}
+	}
+	return options, nil
+}
+
+// collectQualityOptions queries each service's metadata endpoint in parallel
+func collectQualityOptions(urls *SongLinkURLs, isrc string) map[string]QualityInfo {
+	options := make(map[string]QualityInfo)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	set := func(service string, info QualityInfo) {
+		mu.Lock()
+		options[service] = info
+		mu.Unlock()
+	}
+
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		set("qobuz", qobuzQualityOption(isrc))
+	}()
+	go func() {
+		defer wg.Done()
+		set("tidal", tidalQualityOption(urls))
+	}()
+	go func() {
+		defer wg.Done()
+		set("amazon", amazonQualityOption(urls))
+	}()
+	wg.Wait()
+	return options
+}
+
+func qobuzQualityOption(isrc string) QualityInfo {
+	if isrc == "" {
+		return QualityInfo{Error: "ISRC required for Qobuz lookup"}
+	}
+
+	track, err := NewQobuzDownloader().SearchByISRC(isrc)
+	if err != nil {
+		return QualityInfo{Error: err.Error()}
+	}
+
+	// Qobuz reports the sampling rate in kHz
+	info := QualityInfo{
+		Available:  true,
+		Codec:      "FLAC",
+		BitDepth:   track.MaximumBitDepth,
+		SampleRate: int(track.MaximumSamplingRate * 1000),
+		Tier:       QualityTierLossless,
+		Lossless:   true,
+	}
+	if info.BitDepth > 16 || info.SampleRate > 48000 {
+		info.Tier = QualityTierHiRes
+	}
+	if info.BitDepth > 0 && info.SampleRate > 0 {
+		info.Label = qualityLabel(info.BitDepth, info.SampleRate)
+	} else {
+		info.Label = "Lossless"
+	}
+	return info
+}
+
+func tidalQualityOption(urls *SongLinkURLs) QualityInfo {
+	if urls == nil || urls.TidalURL == "" {
+		return QualityInfo{Error: "not found on Tidal"}
+	}
+
+	downloader := NewTidalDownloader("")
+	trackID, err := downloader.GetTrackIDFromURL(urls.TidalURL)
+	if err != nil {
+		return QualityInfo{Error: err.Error()}
+	}
+	track, err := downloader.GetTrackInfoByID(trackID)
+	if err != nil {
+		return QualityInfo{Error: err.Error()}
+	}
+
+	info := QualityInfo{Available: true, Codec: "FLAC"}
+	hiRes := track.AudioQuality == "HI_RES_LOSSLESS"
+	for _, tag := range track.MediaMetadata.Tags {
+		if tag == "HIRES_LOSSLESS" {
+			hiRes = true
+		}
+	}
+
+	// Tidal only reports a tier, not the exact sample rate of hi-res masters
+	switch {
+	case hiRes:
+		info.BitDepth = 24
+		info.Label = "Hi-Res Lossless (24-bit)"
+		info.Tier = QualityTierHiRes
+		info.Lossless = true
+	case track.AudioQuality == "LOSSLESS" || track.AudioQuality == "HI_RES":
+		info.BitDepth = 16
+		info.SampleRate = 44100
+		info.Label = qualityLabel(info.BitDepth, info.SampleRate)
+		info.Tier = QualityTierLossless
+		info.Lossless = true
+	default:
+		info.Tier = QualityTierHigh
+		info.Codec = "AAC"
+		info.Bitrate = 320
+		info.Label = "AAC 320kbps"
+	}
+	return info
+}
+
+func amazonQualityOption(urls *SongLinkURLs) QualityInfo {
+	if urls == nil || urls.AmazonURL == "" {
+		return QualityInfo{Error: "not found on Amazon Music"}
+	}
+	// Amazon exposes no quality metadata before download; the file's format is known only afterwards
+	return QualityInfo{Available: true, Codec: "FLAC", Label: "Lossless (exact format known after download)", Tier: QualityTierLossless, Lossless: true}
+}
@@ -0,0 +1,239 @@
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// queueSaveDelay batches bursts of queue changes (progress ticks, a batch being added) into one write
+const queueSaveDelay = 2 * time.Second
+
+// persistedQueueItem is a pending queue item plus the request needed to run it again
+type persistedQueueItem struct {
+	DownloadItem
+	Request json.RawMessage `json:"request,omitempty"`
+}
+
+var (
+	persistQueue     = true
+	queueSaveTimer   *time.Timer
+	queueSaveFrozen  bool // Set at shutdown so cancelling the queue doesn't overwrite the saved state
+	persistQueueLock sync.Mutex
+
+	itemRequests     = make(map[string]json.RawMessage)
+	itemRequestsLock sync.RWMutex
+)
+
+// SetPersistQueue toggles saving pending queue items to disk so they survive a restart. Turning it
+// off removes the saved queue.
+func SetPersistQueue(enabled bool) {
+	persistQueueLock.Lock()
+	persistQueue = enabled
+	persistQueueLock.Unlock()
+
+	if !enabled {
+		if path, err := GetQueueStatePath(); err == nil {
+			os.Remove(path)
+		}
+	}
+}
+
+// GetQueueStatePath returns the file pending queue items are saved to (~/.spotiflac/queue.json)
+func GetQueueStatePath() (string, error) {
+	dir, err := GetFFmpegDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "queue.json"), nil
+}
+
+// SetItemRequest stores the encoded download request of a queued item so it can be re-queued after
+// a restart
+func SetItemRequest(id string, request json.RawMessage) {
+	itemRequestsLock.Lock()
+	itemRequests[id] = request
+	itemRequestsLock.Unlock()
+	scheduleQueueSave()
+}
+
+// scheduleQueueSave writes the queue once queueSaveDelay has passed since the first unsaved
+// change. It is called from bumpStateVersion, which may hold the queue lock, so the write itself
+// always happens on the timer goroutine.
+func scheduleQueueSave() {
+	persistQueueLock.Lock()
+	defer persistQueueLock.Unlock()
+
+	if !persistQueue || queueSaveFrozen || queueSaveTimer != nil {
+		return
+	}
+	queueSaveTimer = time.AfterFunc(queueSaveDelay, func() {
+		persistQueueLock.Lock()
+		queueSaveTimer = nil
+		frozen := queueSaveFrozen
+		persistQueueLock.Unlock()
+
+		if !frozen {
+			if err := SaveDownloadQueue(); err != nil {
+				fmt.Printf("[Queue] Warning: failed to save queue: %v\n", err)
+			}
+		}
+	})
+}
+
+// SaveDownloadQueue writes queued and downloading items to the queue state file. Finished items
+// aren't kept; when nothing is pending the file is removed.
+func SaveDownloadQueue() error {
+	persistQueueLock.Lock()
+	enabled := persistQueue
+	persistQueueLock.Unlock()
+	if !enabled {
+		return nil
+	}
+
+	path, err := GetQueueStatePath()
+	if err != nil {
+		return err
+	}
+
+	downloadQueueLock.RLock()
+	pending := make([]persistedQueueItem, 0)
+	pendingIDs := make(map[string]bool)
+	itemRequestsLock.Lock()
+	for _, item := range downloadQueue {
+		if item.Status == StatusQueued || item.Status == StatusDownloading {
+			pending = append(pending, persistedQueueItem{DownloadItem: item, Request: itemRequests[item.ID]})
+			pendingIDs[item.ID] = true
+		}
+	}
+	// Requests of finished or cleared items are no longer needed
+	for id := range itemRequests {
+		if !pendingIDs[id] {
+			delete(itemRequests, id)
+		}
+	}
+	itemRequestsLock.Unlock()
+	downloadQueueLock.RUnlock()
+
+	if len(pending) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(pending, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode queue: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create queue directory: %v", err)
+	}
+
+	// Write to a temp file first so a crash never leaves a half-written queue
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write queue: %v", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write queue: %v", err)
+	}
+	return nil
+}
+
+// freezeQueueState saves the queue one last time and stops further saves, so the items shutdown
+// cancels are still restored on the next start
+func freezeQueueState() {
+	if err := SaveDownloadQueue(); err != nil {
+		fmt.Printf("[Queue] Warning: failed to save queue: %v\n", err)
+	}
+
+	persistQueueLock.Lock()
+	queueSaveFrozen = true
+	if queueSaveTimer != nil {
+		queueSaveTimer.Stop()
+		queueSaveTimer = nil
+	}
+	persistQueueLock.Unlock()
+}
+
+// RestoredQueueItem is a queue item reloaded from disk, with its original download request when
+// it was queued for the backend workers
+type RestoredQueueItem struct {
+	ID      string
+	Request json.RawMessage
+}
+
+// RestoreDownloadQueue reloads the items that were pending when the app last exited. Items that
+// were mid-download start over as queued; items already in the queue are left alone.
+func RestoreDownloadQueue() ([]RestoredQueueItem, error) {
+	persistQueueLock.Lock()
+	enabled := persistQueue
+	persistQueueLock.Unlock()
+	if !enabled {
+		return nil, nil
+	}
+
+	path, err := GetQueueStatePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read queue: %v", err)
+	}
+
+	var saved []persistedQueueItem
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failed to parse queue: %v", err)
+	}
+
+	restored := make([]RestoredQueueItem, 0, len(saved))
+	downloadQueueLock.Lock()
+	existing := make(map[string]bool, len(downloadQueue))
+	for _, item := range downloadQueue {
+		existing[item.ID] = true
+	}
+	for _, entry := range saved {
+		if existing[entry.ID] {
+			continue
+		}
+		item := entry.DownloadItem
+		item.Status = StatusQueued
+		item.Progress = 0
+		item.Speed = 0
+		item.StartTime = 0
+		item.EndTime = 0
+		item.Prefetched = false
+		downloadQueue = append(downloadQueue, item)
+		restored = append(restored, RestoredQueueItem{ID: item.ID, Request: entry.Request})
+	}
+	bumpStateVersion()
+	downloadQueueLock.Unlock()
+
+	if len(restored) > 0 {
+		itemRequestsLock.Lock()
+		for _, entry := range restored {
+			if len(entry.Request) > 0 {
+				itemRequests[entry.ID] = entry.Request
+			}
+		}
+		itemRequestsLock.Unlock()
+
+		sessionStartLock.Lock()
+		if sessionStartTime == 0 {
+			sessionStartTime = time.Now().Unix()
+		}
+		sessionStartLock.Unlock()
+	}
+
+	fmt.Printf("[Queue] Restored %d pending items from %s\n", len(restored), path)
+	return restored, nil
+}
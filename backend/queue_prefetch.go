@@ -0,0 +1,183 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// PrefetchedTrack is what the prefetch stage resolved for a queued item ahead of its turn.
+// Song.link URLs go to the shared song.link cache, which DownloadTrack already consults.
+type PrefetchedTrack struct {
+	Track TrackMetadata `json:"track"`
+	Error string        `json:"error,omitempty"`
+}
+
+var (
+	prefetchDepth     int // 0 disables prefetching
+	prefetchDepthLock sync.RWMutex
+
+	prefetchedTracks     = make(map[string]*PrefetchedTrack)
+	prefetchedTracksLock sync.Mutex
+
+	prefetchRunning int32
+
+	// song.link rate limits per client, so prefetch lookups share one and run one at a time
+	prefetchSongLink     = NewSongLinkClient()
+	prefetchSongLinkLock sync.Mutex
+)
+
+// SetPrefetchDepth sets how many upcoming queue items get their ISRC, metadata and service URLs
+// resolved while the current item downloads (0 = off)
+func SetPrefetchDepth(depth int) {
+	if depth < 0 {
+		depth = 0
+	}
+
+	prefetchDepthLock.Lock()
+	prefetchDepth = depth
+	prefetchDepthLock.Unlock()
+}
+
+func getPrefetchDepth() int {
+	prefetchDepthLock.RLock()
+	defer prefetchDepthLock.RUnlock()
+	return prefetchDepth
+}
+
+// upcomingPrefetchItems returns the items among the next depth queued ones that haven't been
+// prefetched yet and have a Spotify ID to resolve from
+func upcomingPrefetchItems(depth int) []DownloadItem {
+	downloadQueueLock.RLock()
+	defer downloadQueueLock.RUnlock()
+
+	items := make([]DownloadItem, 0, depth)
+	queued := 0
+	for _, item := range downloadQueue {
+		if item.Status != StatusQueued {
+			continue
+		}
+		if queued++; queued > depth {
+			break
+		}
+		if !item.Prefetched && item.SpotifyID != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// setItemPrefetched flags a queued item as resolved so it isn't prefetched again
+func setItemPrefetched(id string) {
+	downloadQueueLock.Lock()
+	defer downloadQueueLock.Unlock()
+	defer bumpStateVersion()
+
+	for i := range downloadQueue {
+		if downloadQueue[i].ID == id {
+			downloadQueue[i].Prefetched = true
+			break
+		}
+	}
+}
+
+// TriggerQueuePrefetch starts resolving upcoming queue items in the background. Only one
+// prefetch pass runs at a time; calls while one is running are no-ops.
+func TriggerQueuePrefetch() {
+	depth := getPrefetchDepth()
+	if depth == 0 || !atomic.CompareAndSwapInt32(&prefetchRunning, 0, 1) {
+		return
+	}
+
+	go func() {
+		defer atomic.StoreInt32(&prefetchRunning, 0)
+
+		for {
+			items := upcomingPrefetchItems(depth)
+			if len(items) == 0 {
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+			client := NewSpotifyMetadataClient()
+			token, tokenErr := client.getAccessToken(ctx)
+
+			var wg sync.WaitGroup
+			for _, item := range items {
+				wg.Add(1)
+				go func(item DownloadItem) {
+					defer wg.Done()
+					result := &PrefetchedTrack{}
+					if tokenErr != nil {
+						result.Error = fmt.Sprintf("failed to get access token: %v", tokenErr)
+					} else {
+						result = prefetchTrack(ctx, client, token, item)
+					}
+
+					prefetchedTracksLock.Lock()
+					prefetchedTracks[item.ID] = result
+					prefetchedTracksLock.Unlock()
+					setItemPrefetched(item.ID)
+				}(item)
+			}
+			wg.Wait()
+			cancel()
+
+			fmt.Printf("[Prefetch] Resolved %d upcoming queue items\n", len(items))
+		}
+	}()
+}
+
+// prefetchTrack resolves one item's metadata and ISRC (database first, then Spotify) and warms
+// the song.link cache with its service URLs
+func prefetchTrack(ctx context.Context, client *SpotifyMetadataClient, token string, item DownloadItem) *PrefetchedTrack {
+	result := &PrefetchedTrack{}
+
+	raw, err := client.fetchTrack(ctx, item.SpotifyID, token)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Track = formatTrackData(raw).Track
+
+	if result.Track.ISRC == "" && item.ISRC != "" {
+		result.Track.ISRC = item.ISRC
+	}
+	if databasePath := GetSettings().DatabasePath; result.Track.ISRC == "" && databasePath != "" {
+		if isrc, err := GetISRCFromDatabase(databasePath, item.SpotifyID); err == nil && isrc != "" {
+			result.Track.ISRC = isrc
+		}
+	}
+
+	if _, cached := GetCachedSongLinkURLs(item.SpotifyID); !cached {
+		prefetchSongLinkLock.Lock()
+		_, err := prefetchSongLink.GetAllURLsFromSpotify(item.SpotifyID)
+		prefetchSongLinkLock.Unlock()
+		if err != nil {
+			fmt.Printf("[Prefetch] song.link lookup failed for %s: %v\n", item.SpotifyID, err)
+		}
+	}
+
+	return result
+}
+
+// TakePrefetchedTrack returns and forgets what was prefetched for a queue item
+func TakePrefetchedTrack(itemID string) (*PrefetchedTrack, bool) {
+	prefetchedTracksLock.Lock()
+	defer prefetchedTracksLock.Unlock()
+
+	result, ok := prefetchedTracks[itemID]
+	if ok {
+		delete(prefetchedTracks, itemID)
+	}
+	return result, ok && result.Error == ""
+}
+
+// clearPrefetchedTracks drops every prefetched result, e.g. when the queue is cleared
+func clearPrefetchedTracks() {
+	prefetchedTracksLock.Lock()
+	defer prefetchedTracksLock.Unlock()
+	prefetchedTracks = make(map[string]*PrefetchedTrack)
+}
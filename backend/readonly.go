@@ -0,0 +1,111 @@
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Read-only file handling modes for embed steps
+const (
+	ReadOnlySkip  = "skip"  // Leave the file alone and report it
+	ReadOnlyChmod = "chmod" // Make it writable for the embed, then restore its permissions
+)
+
+// ErrFileReadOnly is returned by embed steps that skipped a read-only file
+var ErrFileReadOnly = errors.New("file is read-only")
+
+var (
+	readOnlyMode     = ReadOnlySkip
+	readOnlyModeLock sync.RWMutex
+
+	readOnlySkipped     []string
+	readOnlySkippedLock sync.Mutex
+)
+
+// SetReadOnlyHandling sets how embed steps treat read-only files: "skip" (default) or "chmod"
+func SetReadOnlyHandling(mode string) error {
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	if mode == "" {
+		mode = ReadOnlySkip
+	}
+	if mode != ReadOnlySkip && mode != ReadOnlyChmod {
+		return fmt.Errorf("unknown read-only handling: %s", mode)
+	}
+
+	readOnlyModeLock.Lock()
+	readOnlyMode = mode
+	readOnlyModeLock.Unlock()
+	return nil
+}
+
+func getReadOnlyHandling() string {
+	readOnlyModeLock.RLock()
+	defer readOnlyModeLock.RUnlock()
+	return readOnlyMode
+}
+
+// prepareWritable checks a file before it is rewritten. Read-only files are either skipped with
+// ErrFileReadOnly or temporarily made writable; the returned restore puts permissions back.
+func prepareWritable(path string) (func(), error) {
+	noop := func() {}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		// Let the embed step report missing files in its own words
+		return noop, nil
+	}
+	mode := info.Mode().Perm()
+	if mode&0200 != 0 {
+		return noop, nil
+	}
+
+	if getReadOnlyHandling() != ReadOnlyChmod {
+		fmt.Printf("[Embed] Warning: skipping read-only file: %s\n", path)
+		recordReadOnlySkipped(path)
+		return noop, fmt.Errorf("%w: %s", ErrFileReadOnly, path)
+	}
+
+	if err := os.Chmod(path, mode|0200); err != nil {
+		fmt.Printf("[Embed] Warning: cannot make read-only file writable: %s\n", path)
+		recordReadOnlySkipped(path)
+		return noop, fmt.Errorf("%w: %s (%v)", ErrFileReadOnly, path, err)
+	}
+
+	return func() {
+		if err := os.Chmod(path, mode); err != nil {
+			fmt.Printf("[Embed] Warning: failed to restore permissions on %s: %v\n", path, err)
+		}
+	}, nil
+}
+
+func recordReadOnlySkipped(path string) {
+	readOnlySkippedLock.Lock()
+	defer readOnlySkippedLock.Unlock()
+
+	for _, p := range readOnlySkipped {
+		if p == path {
+			return
+		}
+	}
+	readOnlySkipped = append(readOnlySkipped, path)
+}
+
+// GetReadOnlySkippedFiles returns the files embed steps skipped because they were read-only
+func GetReadOnlySkippedFiles() []string {
+	readOnlySkippedLock.Lock()
+	defer readOnlySkippedLock.Unlock()
+
+	files := make([]string, len(readOnlySkipped))
+	copy(files, readOnlySkipped)
+	return files
+}
+
+// ClearReadOnlySkippedFiles resets the list of skipped read-only files
+func ClearReadOnlySkippedFiles() {
+	readOnlySkippedLock.Lock()
+	defer readOnlySkippedLock.Unlock()
+	readOnlySkipped = nil
+}
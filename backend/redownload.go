@@ -0,0 +1,87 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// setAsideSuffix is appended to a file kept out of the way of its re-download. It doesn't end
+// in an audio extension, so ISRC and filename checks don't find it.
+const setAsideSuffix = ".old"
+
+var (
+	// Copies found carrying an ISRC but failing verification, keyed by folder and ISRC, so the
+	// download that replaces them can set them aside
+	brokenISRCFiles     = make(map[string]string)
+	brokenISRCFilesLock sync.Mutex
+)
+
+func brokenISRCKey(outputDir, isrc string) string {
+	return filepath.Clean(outputDir) + "|" + strings.ToUpper(isrc)
+}
+
+// recordBrokenISRCFile remembers an existing copy that needs to be downloaded again
+func recordBrokenISRCFile(outputDir, isrc, path string) {
+	brokenISRCFilesLock.Lock()
+	defer brokenISRCFilesLock.Unlock()
+	brokenISRCFiles[brokenISRCKey(outputDir, isrc)] = path
+}
+
+// takeBrokenISRCFile returns and forgets the copy recorded for the ISRC in outputDir
+func takeBrokenISRCFile(outputDir, isrc string) string {
+	brokenISRCFilesLock.Lock()
+	defer brokenISRCFilesLock.Unlock()
+
+	key := brokenISRCKey(outputDir, isrc)
+	path := brokenISRCFiles[key]
+	delete(brokenISRCFiles, key)
+	return path
+}
+
+// SetAsideForRedownload moves existing copies out of the way of a re-download: the given paths,
+// plus the copy CheckISRCExists found failing verification for the ISRC. Nothing is deleted yet;
+// the returned func removes the old copies once the new download succeeded and puts them back
+// otherwise, so a failed re-download never costs the user their file.
+func SetAsideForRedownload(outputDir, isrc string, paths ...string) func(replaced bool) {
+	if isrc != "" {
+		if broken := takeBrokenISRCFile(outputDir, isrc); broken != "" {
+			paths = append(paths, broken)
+		}
+	}
+
+	setAside := make(map[string]string)
+	for _, path := range paths {
+		if _, done := setAside[path]; done || !fileExists(path) {
+			continue
+		}
+		oldPath := path + setAsideSuffix
+		if err := os.Rename(path, oldPath); err != nil {
+			fmt.Printf("Warning: Failed to set aside %s for re-download: %v\n", path, err)
+			continue
+		}
+		fmt.Printf("Set aside for re-download: %s\n", path)
+		setAside[path] = oldPath
+	}
+
+	return func(replaced bool) {
+		for path, oldPath := range setAside {
+			if replaced {
+				if err := os.Remove(oldPath); err != nil {
+					fmt.Printf("Warning: Failed to remove replaced file %s: %v\n", oldPath, err)
+				}
+				continue
+			}
+			// Something may have been written in its place meanwhile; never overwrite it
+			if fileExists(path) {
+				fmt.Printf("Warning: Kept previous copy as %s, %s exists again\n", oldPath, path)
+				continue
+			}
+			if err := os.Rename(oldPath, path); err != nil {
+				fmt.Printf("Warning: Failed to restore %s: %v\n", path, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package backend
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetAsideForRedownload(t *testing.T) {
+	tests := []struct {
+		name     string
+		replaced bool
+		rewrite  bool // a new file appears at the original path before finishing
+		wantOld  bool // the previous copy is back at the original path
+	}{
+		{name: "failed download restores the copy", replaced: false, wantOld: true},
+		{name: "successful download removes the copy", replaced: true},
+		{name: "failed download doesn't overwrite a new file", replaced: false, rewrite: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "track.flac")
+			if err := os.WriteFile(path, []byte("old"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			recordBrokenISRCFile(dir, "usabc1234567", path)
+
+			finish := SetAsideForRedownload(dir, "USABC1234567")
+			if fileExists(path) {
+				t.Fatalf("file still in place after setting it aside")
+			}
+			if tt.rewrite {
+				if err := os.WriteFile(path, []byte("new"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			finish(tt.replaced)
+
+			data, err := os.ReadFile(path)
+			switch {
+			case tt.wantOld && (err != nil || string(data) != "old"):
+				t.Errorf("previous copy not restored: %q, %v", data, err)
+			case tt.rewrite && string(data) != "new":
+				t.Errorf("new file overwritten: %q", data)
+			case tt.replaced && err == nil:
+				t.Errorf("file at original path after a successful download, want it removed")
+			}
+			if oldExists := fileExists(path + setAsideSuffix); oldExists != tt.rewrite {
+				t.Errorf("set-aside copy exists = %v, want %v", oldExists, tt.rewrite)
+			}
+		})
+	}
+}
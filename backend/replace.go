@@ -0,0 +1,127 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// GetTrashDir returns the folder replaced files are moved to (~/.spotiflac/trash)
+func GetTrashDir() (string, error) {
+	dir, err := GetFFmpegDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "trash"), nil
+}
+
+// MoveToTrash moves a file into the app trash folder and returns its new location
+func MoveToTrash(path string) (string, error) {
+	trashDir, err := GetTrashDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(trashDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create trash directory: %v", err)
+	}
+
+	trashPath := filepath.Join(trashDir, fmt.Sprintf("%d-%s", time.Now().UnixNano(), filepath.Base(path)))
+	// Rename fails across volumes; finalizeFile falls back to copy + delete
+	if _, err := finalizeFile(path, trashPath); err != nil {
+		return "", fmt.Errorf("failed to move file to trash: %v", err)
+	}
+
+	return trashPath, nil
+}
+
+// copyFile copies src to dst, overwriting dst
+func copyFile(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, 0644)
+}
+
+// CarryOverUserTags copies Vorbis comments that exist only in the old file (user edits such as
+// lyrics, genre or ReplayGain) into the new file. Returns the carried-over field names.
+func CarryOverUserTags(oldPath, newPath string) ([]string, error) {
+	if strings.ToLower(filepath.Ext(oldPath)) != ".flac" || strings.ToLower(filepath.Ext(newPath)) != ".flac" {
+		return nil, nil
+	}
+
+	oldFields, err := readVorbisComments(oldPath)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := readVorbisComments(newPath)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := make(map[string]string)
+	var carried []string
+	for key, value := range oldFields {
+		if _, exists := newFields[key]; exists || value == "" {
+			continue
+		}
+		missing[key] = value
+		carried = append(carried, key)
+	}
+
+	if len(missing) == 0 {
+		return nil, nil
+	}
+	if err := setVorbisFields(newPath, missing); err != nil {
+		return nil, err
+	}
+	return carried, nil
+}
+
+// ReplaceResult describes an in-place track replacement
+type ReplaceResult struct {
+	FilePath    string   `json:"file_path"`
+	TrashedPath string   `json:"trashed_path"`
+	CarriedTags []string `json:"carried_tags,omitempty"`
+	Copied      bool     `json:"copied,omitempty"` // Replacement came from another filesystem and was copied, not renamed
+}
+
+// ReplaceFile verifies a freshly downloaded file and swaps it in for an existing one, keeping the
+// existing name and location. The old file is moved to the app trash folder.
+func ReplaceFile(existingPath, newPath string) (*ReplaceResult, error) {
+	if broken := checkAudioFile(newPath); broken != nil {
+		return nil, fmt.Errorf("replacement failed verification: %s", broken.Reason)
+	}
+
+	carried, err := CarryOverUserTags(existingPath, newPath)
+	if err != nil {
+		fmt.Printf("[Replace] Warning: failed to carry over tags: %v\n", err)
+	}
+
+	// Keep the existing name; only the extension follows the new container
+	finalPath := strings.TrimSuffix(existingPath, filepath.Ext(existingPath)) + filepath.Ext(newPath)
+
+	trashedPath, err := MoveToTrash(existingPath)
+	if err != nil {
+		return nil, err
+	}
+
+	copied, err := finalizeFile(newPath, finalPath)
+	if err != nil {
+		// Put the original back so nothing is lost
+		if _, restoreErr := finalizeFile(trashedPath, existingPath); restoreErr != nil {
+			fmt.Printf("[Replace] Warning: failed to restore original from %s: %v\n", trashedPath, restoreErr)
+		}
+		return nil, fmt.Errorf("failed to move replacement into place: %v", err)
+	}
+
+	fmt.Printf("[Replace] Replaced %s (old file: %s)\n", finalPath, trashedPath)
+	return &ReplaceResult{
+		FilePath:    finalPath,
+		TrashedPath: trashedPath,
+		CarriedTags: carried,
+		Copied:      copied,
+	}, nil
+}
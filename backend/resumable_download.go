@@ -0,0 +1,207 @@
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const defaultResumeRetries = 3
+
+var (
+	resumeRetries     = defaultResumeRetries
+	resumeRetriesLock sync.RWMutex
+
+	contentRangeStart = regexp.MustCompile(`^bytes (\d+)-\d+/(\d+)$`)
+)
+
+// SetResumeRetries sets how many times an interrupted download is resumed from its .part file
+// before giving up (0 = never, negative = default)
+func SetResumeRetries(retries int) {
+	if retries < 0 {
+		retries = defaultResumeRetries
+	}
+
+	resumeRetriesLock.Lock()
+	resumeRetries = retries
+	resumeRetriesLock.Unlock()
+}
+
+func getResumeRetries() int {
+	resumeRetriesLock.RLock()
+	defer resumeRetriesLock.RUnlock()
+	return resumeRetries
+}
+
+// partMeta is stored beside a .part file so a resumed download can be validated before it is
+// renamed into place
+type partMeta struct {
+	Total int64  `json:"total"` // 0 when the server didn't send a length
+	ETag  string `json:"etag,omitempty"`
+}
+
+func partPath(dst string) string     { return dst + ".part" }
+func partMetaPath(dst string) string { return dst + ".part.json" }
+
+func readPartMeta(dst string) *partMeta {
+	data, err := os.ReadFile(partMetaPath(dst))
+	if err != nil {
+		return nil
+	}
+	var meta partMeta
+	if json.Unmarshal(data, &meta) != nil {
+		return nil
+	}
+	return &meta
+}
+
+func writePartMeta(dst string, meta partMeta) {
+	data, _ := json.Marshal(meta)
+	if err := os.WriteFile(partMetaPath(dst), data, 0644); err != nil {
+		fmt.Printf("[Resume] Warning: failed to store expected size: %v\n", err)
+	}
+}
+
+// discardPart removes a .part file and its metadata so the next attempt starts from scratch
+func discardPart(dst string) {
+	os.Remove(partPath(dst))
+	os.Remove(partMetaPath(dst))
+}
+
+// downloadResumable streams url into dst through dst.part. When the transfer breaks off, it is
+// resumed with a Range request from the bytes already on disk; servers that ignore Range get a
+// full restart. The .part is only renamed to dst once its size matches the expected total, and
+// it is kept on failure so a later retry of the same track can pick it up.
+func downloadResumable(client *http.Client, url, dst string, prepare func(*http.Request)) error {
+	retries := getResumeRetries()
+	var err error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			fmt.Printf("[Resume] %v, resuming (%d/%d)\n", err, attempt, retries)
+			time.Sleep(time.Duration(attempt) * time.Second)
+		}
+
+		var retryable bool
+		retryable, err = fetchToPart(client, url, dst, prepare)
+		if err == nil {
+			return finalizePart(dst)
+		}
+		if !retryable {
+			return err
+		}
+	}
+	return err
+}
+
+// fetchToPart makes one transfer attempt. Status errors (including expired URLs) are returned as
+// not retryable so the caller's URL refresh logic sees them; broken transfers are retryable.
+func fetchToPart(client *http.Client, url, dst string, prepare func(*http.Request)) (bool, error) {
+	meta := readPartMeta(dst)
+	var offset int64
+	if info, err := os.Stat(partPath(dst)); err == nil && meta != nil {
+		offset = info.Size()
+	}
+	if meta != nil && meta.Total > 0 && offset == meta.Total {
+		return false, nil
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create download request: %w", err)
+	}
+	if prepare != nil {
+		prepare(req)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		if meta.ETag != "" {
+			req.Header.Set("If-Range", meta.ETag)
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("failed to download file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var out *os.File
+	var total int64
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		match := contentRangeStart.FindStringSubmatch(resp.Header.Get("Content-Range"))
+		if match == nil || offset == 0 {
+			discardPart(dst)
+			return true, fmt.Errorf("unexpected partial response")
+		}
+		start, _ := strconv.ParseInt(match[1], 10, 64)
+		total, _ = strconv.ParseInt(match[2], 10, 64)
+		if start != offset || (meta.Total > 0 && total != meta.Total) {
+			discardPart(dst)
+			return true, fmt.Errorf("server returned bytes %d-/%d for a resume at %d/%d", start, total, offset, meta.Total)
+		}
+		fmt.Printf("[Resume] Resuming at %.2f MB\n", float64(offset)/(1024*1024))
+		out, err = os.OpenFile(partPath(dst), os.O_WRONLY|os.O_APPEND, 0644)
+
+	case http.StatusOK:
+		if offset > 0 {
+			fmt.Println("[Resume] Server doesn't support range requests, restarting from the beginning")
+		}
+		offset = 0
+		total = resp.ContentLength
+		if total < 0 {
+			total = 0
+		}
+		writePartMeta(dst, partMeta{Total: total, ETag: resp.Header.Get("ETag")})
+		out, err = os.Create(partPath(dst))
+
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The part no longer lines up with the remote file
+		discardPart(dst)
+		return true, fmt.Errorf("stale partial download at %d bytes", offset)
+
+	default:
+		return false, downloadStatusError(resp.StatusCode)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to create file: %w", err)
+	}
+	defer out.Close()
+
+	// Use progress writer to track download
+	pw := NewProgressWriter(out)
+	pw.setTransferSize(offset, total)
+	if _, err := io.Copy(pw, resp.Body); err != nil {
+		return true, fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := out.Sync(); err != nil {
+		return true, fmt.Errorf("failed to write file: %w", err)
+	}
+
+	fmt.Printf("\rDownloaded: %.2f MB (Complete)\n", float64(offset+pw.GetTotal())/(1024*1024))
+	return false, nil
+}
+
+// finalizePart validates the .part against the stored total and renames it to dst
+func finalizePart(dst string) error {
+	info, err := os.Stat(partPath(dst))
+	if err != nil {
+		return fmt.Errorf("partial download missing: %w", err)
+	}
+	if meta := readPartMeta(dst); meta != nil && meta.Total > 0 && info.Size() != meta.Total {
+		discardPart(dst)
+		return fmt.Errorf("download is %d bytes, expected %d", info.Size(), meta.Total)
+	}
+
+	if err := os.Rename(partPath(dst), dst); err != nil {
+		return fmt.Errorf("failed to finalize download: %w", err)
+	}
+	os.Remove(partMetaPath(dst))
+	return nil
+}
@@ -0,0 +1,455 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/bogem/id3v2"
+)
+
+// Ways a file can be matched to a Spotify track, in the order they are tried
+const (
+	RetagMatchSpotifyID   = "spotify_id"
+	RetagMatchISRC        = "isrc"
+	RetagMatchFingerprint = "fingerprint"
+	RetagMatchFilename    = "filename"
+)
+
+// retagFields are the tags rewritten from Spotify, in the order changes are reported
+var retagFields = []string{"TITLE", "ARTIST", "ALBUM", "ALBUMARTIST", "DATE", "TRACKNUMBER", "DISCNUMBER", "ISRC"}
+
+// retagID3Frames maps each re-tagged field to its ID3v2 frame
+var retagID3Frames = map[string]string{
+	"TITLE": "TIT2", "ARTIST": "TPE1", "ALBUM": "TALB", "ALBUMARTIST": "TPE2",
+	"DATE": "TDRC", "TRACKNUMBER": "TRCK", "DISCNUMBER": "TPOS", "ISRC": "TSRC",
+}
+
+// RetagRequest describes a bulk re-tag of a library folder from Spotify metadata
+type RetagRequest struct {
+	ScanPath       string `json:"scan_path"`
+	DryRun         bool   `json:"dry_run"`                   // Report the changes without writing any file
+	UseFingerprint bool   `json:"use_fingerprint,omitempty"` // Identify files without an ISRC through AcoustID
+	DatabasePath   string `json:"database_path,omitempty"`   // Resolve ISRCs from the local database before searching Spotify
+}
+
+// RetagChange is one tag whose value differs from the Spotify metadata
+type RetagChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// RetagFileResult is the match and the tag changes for one file
+type RetagFileResult struct {
+	FilePath  string        `json:"file_path"`
+	MatchedBy string        `json:"matched_by,omitempty"` // spotify_id, isrc, fingerprint or filename
+	SpotifyID string        `json:"spotify_id,omitempty"`
+	Changes   []RetagChange `json:"changes"`
+	Written   bool          `json:"written"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// RetagResponse summarizes a bulk re-tag
+type RetagResponse struct {
+	Success      bool              `json:"success"`
+	DryRun       bool              `json:"dry_run"`
+	TotalFiles   int               `json:"total_files"`
+	MatchedFiles int               `json:"matched_files"`
+	ChangedFiles int               `json:"changed_files"`
+	WrittenFiles int               `json:"written_files"`
+	Unmatched    []string          `json:"unmatched,omitempty"`
+	Files        []RetagFileResult `json:"files"`
+	Error        string            `json:"error,omitempty"`
+}
+
+// RetagLibrary matches every FLAC and MP3 file below ScanPath to a Spotify track (by tagged
+// Spotify ID, ISRC, AcoustID fingerprint and finally title/artist from tags or filename) and
+// rewrites the core tags from Spotify. With DryRun set the changes are reported but not written.
+func RetagLibrary(req RetagRequest) (*RetagResponse, error) {
+	scanPath := NormalizePath(req.ScanPath)
+	response := &RetagResponse{Success: true, DryRun: req.DryRun, Files: make([]RetagFileResult, 0)}
+
+	if _, err := os.Stat(scanPath); os.IsNotExist(err) {
+		response.Success = false
+		response.Error = fmt.Sprintf("Directory does not exist: %s", scanPath)
+		return response, fmt.Errorf("directory does not exist: %s", scanPath)
+	}
+
+	audioFiles := make([]string, 0)
+	err := filepath.Walk(scanPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		if ext == ".flac" || ext == ".mp3" {
+			audioFiles = append(audioFiles, path)
+		}
+		return nil
+	})
+	if err != nil {
+		response.Success = false
+		response.Error = fmt.Sprintf("Failed to scan directory: %v", err)
+		return response, err
+	}
+	response.TotalFiles = len(audioFiles)
+	fmt.Printf("\n[Retag] Found %d audio files in %s (dry run: %v)\n", len(audioFiles), scanPath, req.DryRun)
+	if len(audioFiles) == 0 {
+		return response, nil
+	}
+
+	ctx := context.Background()
+	spotify := &retagSpotify{client: NewSpotifyMetadataClient()}
+	if _, err := spotify.token(ctx); err != nil {
+		response.Success = false
+		response.Error = fmt.Sprintf("Failed to get Spotify token: %v", err)
+		return response, err
+	}
+
+	// Few workers: every file costs one or two Spotify API calls
+	const maxWorkers = 4
+	results := make([]RetagFileResult, len(audioFiles))
+	jobs := make(chan int, len(audioFiles))
+	for i := range audioFiles {
+		jobs <- i
+	}
+	close(jobs)
+
+	var wg sync.WaitGroup
+	processed := int32(0)
+	for w := 0; w < maxWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = retagFile(ctx, spotify, audioFiles[i], req)
+				if current := atomic.AddInt32(&processed, 1); current%25 == 0 {
+					fmt.Printf("[Retag] Progress: %d/%d\n", current, len(audioFiles))
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, result := range results {
+		// Files that failed are listed with their error rather than as unmatched
+		if result.SpotifyID == "" && result.Error == "" {
+			response.Unmatched = append(response.Unmatched, result.FilePath)
+		} else if result.SpotifyID != "" {
+			response.MatchedFiles++
+		}
+		if len(result.Changes) > 0 {
+			response.ChangedFiles++
+		}
+		if result.Written {
+			response.WrittenFiles++
+		}
+		if len(result.Changes) > 0 || result.Error != "" {
+			response.Files = append(response.Files, result)
+		}
+	}
+	sort.Slice(response.Files, func(i, j int) bool { return response.Files[i].FilePath < response.Files[j].FilePath })
+
+	fmt.Printf("[Retag] Matched %d/%d files, %d with changes, %d written\n",
+		response.MatchedFiles, response.TotalFiles, response.ChangedFiles, response.WrittenFiles)
+	return response, nil
+}
+
+// retagSpotify shares one Spotify client between the re-tag workers. The token is taken under a
+// lock for every call, so it is renewed before it expires, and replaced once if Spotify rejects it.
+type retagSpotify struct {
+	client *SpotifyMetadataClient
+	mu     sync.Mutex
+}
+
+func (s *retagSpotify) token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.client.getAccessToken(ctx)
+}
+
+// getJSON runs one API call, fetching a new token and retrying once on a 401
+func (s *retagSpotify) getJSON(ctx context.Context, endpoint string, dst interface{}) error {
+	for attempt := 0; ; attempt++ {
+		token, err := s.token(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get Spotify token: %w", err)
+		}
+		err = s.client.getJSON(ctx, endpoint, token, dst)
+		if err == nil || attempt > 0 || !strings.Contains(err.Error(), "status 401") {
+			return err
+		}
+
+		s.mu.Lock()
+		if s.client.cachedToken == token {
+			s.client.cachedToken = ""
+		}
+		s.mu.Unlock()
+	}
+}
+
+// retagFile matches one file, diffs its tags against Spotify and writes them unless dry-running
+func retagFile(ctx context.Context, spotify *retagSpotify, filePath string, req RetagRequest) RetagFileResult {
+	result := RetagFileResult{FilePath: filePath, Changes: make([]RetagChange, 0)}
+
+	before, err := readRetagFields(filePath)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	track, matchedBy, err := matchRetagTrack(ctx, spotify, filePath, before, req)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if track == nil {
+		return result
+	}
+	result.MatchedBy = matchedBy
+	result.SpotifyID = track.SpotifyID
+
+	after := retagValues(track)
+	updates := make(map[string]string)
+	for _, field := range retagFields {
+		if after[field] == "" || retagValueEqual(field, before[field], after[field]) {
+			continue
+		}
+		result.Changes = append(result.Changes, RetagChange{Field: field, Before: before[field], After: after[field]})
+		updates[field] = after[field]
+	}
+	if len(updates) == 0 || req.DryRun {
+		return result
+	}
+
+	if err := writeRetagFields(filePath, updates); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Written = true
+	fmt.Printf("[Retag] %s: updated %d tags (%s)\n", filepath.Base(filePath), len(updates), matchedBy)
+	return result
+}
+
+// matchRetagTrack finds the Spotify track for a file. A nil track with no error means no match;
+// when no step matched and a Spotify call failed, the last failure is returned.
+func matchRetagTrack(ctx context.Context, spotify *retagSpotify, filePath string, tags map[string]string, req RetagRequest) (*TrackMetadata, string, error) {
+	var lastErr error
+
+	if spotifyID, _ := ReadSpotifyIDFromFile(filePath); spotifyID != "" {
+		track, err := fetchRetagTrack(ctx, spotify, spotifyID)
+		if err == nil {
+			return track, RetagMatchSpotifyID, nil
+		}
+		lastErr = err
+	}
+
+	if isrc := strings.ToUpper(strings.TrimSpace(tags["ISRC"])); isrc != "" {
+		spotifyID, _ := GetSpotifyIDFromISRC(req.DatabasePath, isrc)
+		if spotifyID == "" {
+			var err error
+			if spotifyID, err = searchRetagTrack(ctx, spotify, "isrc:"+isrc, "", ""); err != nil {
+				lastErr = err
+			}
+		}
+		if spotifyID != "" {
+			track, err := fetchRetagTrack(ctx, spotify, spotifyID)
+			if err == nil {
+				return track, RetagMatchISRC, nil
+			}
+			lastErr = err
+		}
+	}
+
+	if req.UseFingerprint {
+		matched, err := FingerprintFile(filePath)
+		if err == nil {
+			query := fmt.Sprintf("track:%s artist:%s", matched.Title, firstArtist(matched.Artist))
+			spotifyID, err := searchRetagTrack(ctx, spotify, query, matched.Title, matched.Artist)
+			if err != nil {
+				lastErr = err
+			} else if spotifyID != "" {
+				track, err := fetchRetagTrack(ctx, spotify, spotifyID)
+				if err == nil {
+					return track, RetagMatchFingerprint, nil
+				}
+				lastErr = err
+			}
+		} else if !errors.Is(err, ErrFingerprintUnavailable) {
+			fmt.Printf("[Retag] Fingerprint lookup failed for %s: %v\n", filepath.Base(filePath), err)
+		}
+	}
+
+	metadata, _, err := lookupTrackMetadata(filePath, false)
+	if err != nil || metadata.Title == "" || metadata.Artist == "" {
+		return nil, "", lastErr
+	}
+	query := fmt.Sprintf("track:%s artist:%s", simplifyTrackName(metadata.Title), firstArtist(metadata.Artist))
+	spotifyID, err := searchRetagTrack(ctx, spotify, query, metadata.Title, metadata.Artist)
+	if err != nil {
+		return nil, "", err
+	}
+	if spotifyID == "" {
+		return nil, "", lastErr
+	}
+	track, err := fetchRetagTrack(ctx, spotify, spotifyID)
+	if err != nil {
+		return nil, "", err
+	}
+	return track, RetagMatchFilename, nil
+}
+
+// searchRetagTrack returns the first search hit, or with a title and artist given, the first
+// hit whose title and first artist match them. An empty ID with no error means no hit matched.
+func searchRetagTrack(ctx context.Context, spotify *retagSpotify, query, title, artist string) (string, error) {
+	searchURL := fmt.Sprintf("https://api.spotify.com/v1/search?q=%s&type=track&limit=10", url.QueryEscape(query))
+	var resp searchTracksResponse
+	if err := spotify.getJSON(ctx, searchURL, &resp); err != nil {
+		return "", fmt.Errorf("search failed for '%s': %w", query, err)
+	}
+
+	wantTitle := normalizeMatchKey(simplifyTrackName(title))
+	wantArtist := normalizeMatchKey(firstArtist(artist))
+	for _, item := range resp.Tracks.Items {
+		if title == "" {
+			return item.ID, nil
+		}
+		if normalizeMatchKey(simplifyTrackName(item.Name)) != wantTitle || len(item.Artists) == 0 {
+			continue
+		}
+		if wantArtist == "" || normalizeMatchKey(item.Artists[0].Name) == wantArtist {
+			return item.ID, nil
+		}
+	}
+	return "", nil
+}
+
+// fetchRetagTrack loads the full track, including track and disc numbers search results lack
+func fetchRetagTrack(ctx context.Context, spotify *retagSpotify, spotifyID string) (*TrackMetadata, error) {
+	var raw trackFull
+	if err := spotify.getJSON(ctx, fmt.Sprintf(trackBaseURL, spotifyID), &raw); err != nil {
+		return nil, err
+	}
+	track := formatTrackData(&raw).Track
+	return &track, nil
+}
+
+// retagValues turns Spotify metadata into tag values, written the way downloads write them
+func retagValues(track *TrackMetadata) map[string]string {
+	values := map[string]string{
+		"TITLE":       track.Name,
+		"ARTIST":      track.Artists,
+		"ALBUM":       track.AlbumName,
+		"ALBUMARTIST": track.AlbumArtist,
+		"DATE":        track.ReleaseDate,
+		"ISRC":        strings.ToUpper(track.ISRC),
+	}
+	if track.TrackNumber > 0 {
+		values["TRACKNUMBER"] = strconv.Itoa(track.TrackNumber)
+	}
+	if track.DiscNumber > 0 {
+		values["DISCNUMBER"] = strconv.Itoa(track.DiscNumber)
+	}
+	return values
+}
+
+// retagValueEqual compares numbers by value, so "3/12" and "03" both equal 3, and ISRCs
+// without regard to case
+func retagValueEqual(field, before, after string) bool {
+	switch field {
+	case "TRACKNUMBER", "DISCNUMBER":
+		number, _ := parseTagNumber(before)
+		return strconv.Itoa(number) == after
+	case "ISRC":
+		return strings.EqualFold(strings.TrimSpace(before), after)
+	}
+	return before == after
+}
+
+// readRetagFields reads the current value of every re-tagged field
+func readRetagFields(filePath string) (map[string]string, error) {
+	fields := make(map[string]string, len(retagFields))
+
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".flac":
+		comments, err := readVorbisComments(filePath)
+		if err != nil {
+			return nil, err
+		}
+		for _, field := range retagFields {
+			fields[field] = comments[field]
+		}
+		// ARTIST may be written once per artist; compare against the joined form
+		if metadata, err := extractMetadataFromFLAC(filePath); err == nil && metadata.Artist != "" {
+			fields["ARTIST"] = metadata.Artist
+		}
+	case ".mp3":
+		tag, err := id3v2.Open(filePath, id3v2.Options{Parse: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to open MP3 file: %w", err)
+		}
+		defer tag.Close()
+		for field, frameID := range retagID3Frames {
+			fields[field] = tag.GetTextFrame(frameID).Text
+		}
+		if fields["DATE"] == "" {
+			fields["DATE"] = tag.GetTextFrame("TYER").Text
+		}
+	default:
+		return nil, fmt.Errorf("unsupported file format for re-tagging: %s", filepath.Ext(filePath))
+	}
+	return fields, nil
+}
+
+// writeRetagFields replaces the given fields, leaving every other tag alone
+func writeRetagFields(filePath string, updates map[string]string) error {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".flac":
+		values := make(map[string][]string, len(updates))
+		for field, value := range updates {
+			values[field] = []string{value}
+		}
+		if artist, ok := updates["ARTIST"]; ok {
+			if artists := splitArtists(artist); getMultiArtistTags() && len(artists) > 1 {
+				values["ARTIST"] = artists
+				values[displayArtistField] = []string{artist}
+			}
+		}
+		return setVorbisFieldValues(filePath, values)
+	case ".mp3":
+		restore, err := prepareWritable(filePath)
+		if err != nil {
+			return err
+		}
+		defer restore()
+
+		tag, err := id3v2.Open(filePath, id3v2.Options{Parse: true})
+		if err != nil {
+			return fmt.Errorf("failed to open MP3 file: %w", err)
+		}
+		defer tag.Close()
+
+		for field, value := range updates {
+			frameID := retagID3Frames[field]
+			tag.DeleteFrames(frameID)
+			tag.AddTextFrame(frameID, id3v2.EncodingUTF8, value)
+		}
+		if _, ok := updates["DATE"]; ok {
+			tag.DeleteFrames("TYER")
+		}
+		if err := tag.Save(); err != nil {
+			return fmt.Errorf("failed to save MP3 tags: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported file format for re-tagging: %s", filepath.Ext(filePath))
+	}
+}
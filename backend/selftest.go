@@ -0,0 +1,245 @@
+package backend
+
+import (
+	"bufio"
+	"bytes"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+const selfTestHTTPTimeout = 10 * time.Second
+
+// Self-test check categories
+const (
+	SelfTestCategoryTools    = "tools"
+	SelfTestCategoryStorage  = "storage"
+	SelfTestCategoryNetwork  = "network"
+	SelfTestCategoryDatabase = "database"
+)
+
+// SelfTestCheck is the outcome of one diagnostic check
+type SelfTestCheck struct {
+	Name       string `json:"name"`
+	Category   string `json:"category"`
+	Passed     bool   `json:"passed"`
+	Detail     string `json:"detail,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// SelfTestReport collects every diagnostic check; Success is true only if all of them passed
+type SelfTestReport struct {
+	Success bool            `json:"success"`
+	Passed  int             `json:"passed"`
+	Failed  int             `json:"failed"`
+	Checks  []SelfTestCheck `json:"checks"`
+	Error   string          `json:"error,omitempty"`
+}
+
+type selfTestEndpoint struct {
+	name string
+	urls []string
+}
+
+// selfTestEndpoints lists the hosts each service downloads through; a service passes
+// if any one of its hosts answers
+func selfTestEndpoints() []selfTestEndpoint {
+	decode := func(encoded string) string {
+		decoded, _ := base64.StdEncoding.DecodeString(encoded)
+		return string(decoded)
+	}
+
+	tidalAPIs, _ := (&TidalDownloader{}).GetAvailableAPIs()
+
+	var amazonURLs []string
+	for _, region := range NewAmazonDownloader().regions {
+		amazonURLs = append(amazonURLs, decode("aHR0cHM6Ly8=")+region+decode("LmRvdWJsZWRvdWJsZS50b3A="))
+	}
+
+	return []selfTestEndpoint{
+		{name: "Tidal", urls: tidalAPIs},
+		{name: "Qobuz", urls: []string{decode("aHR0cHM6Ly9kYWIueWVldC5zdQ=="), decode("aHR0cHM6Ly9kYWJtdXNpYy54eXo=")}},
+		{name: "Amazon", urls: amazonURLs},
+		{name: "song.link", urls: []string{decode("aHR0cHM6Ly9hcGkuc29uZy5saW5r")}},
+		{name: "Spotify", urls: []string{"https://open.spotify.com"}},
+	}
+}
+
+// RunSelfTest checks external tools, output/temp directory write access, connectivity to every
+// service and the configured database, returning a pass/fail entry per check
+func RunSelfTest(settings Settings) *SelfTestReport {
+	var checks []SelfTestCheck
+
+	checks = append(checks, timedCheck("ffmpeg", SelfTestCategoryTools, func() (string, error) {
+		path, err := GetFFmpegPath()
+		if err != nil {
+			return "", err
+		}
+		return readToolVersion(path)
+	}))
+	checks = append(checks, timedCheck("ffprobe", SelfTestCategoryTools, func() (string, error) {
+		path, err := GetFFprobePath()
+		if err != nil {
+			return "", err
+		}
+		return readToolVersion(path)
+	}))
+
+	outputDir := settings.DownloadPath
+	if outputDir == "" {
+		outputDir = GetDefaultMusicPath()
+	}
+	checks = append(checks, timedCheck("Output directory", SelfTestCategoryStorage, func() (string, error) {
+		return checkDirWritable(NormalizePath(outputDir))
+	}))
+
+	tempDir := settings.TempDir
+	if tempDir == "" {
+		tempDir = os.TempDir()
+	}
+	checks = append(checks, timedCheck("Temp directory", SelfTestCategoryStorage, func() (string, error) {
+		return checkDirWritable(NormalizePath(tempDir))
+	}))
+
+	// Network checks are slow, so run them in parallel and keep their order stable
+	endpoints := selfTestEndpoints()
+	networkChecks := make([]SelfTestCheck, len(endpoints))
+	var wg sync.WaitGroup
+	for i, endpoint := range endpoints {
+		wg.Add(1)
+		go func(i int, endpoint selfTestEndpoint) {
+			defer wg.Done()
+			networkChecks[i] = timedCheck(endpoint.name, SelfTestCategoryNetwork, func() (string, error) {
+				return checkAnyReachable(endpoint.urls)
+			})
+		}(i, endpoint)
+	}
+	wg.Wait()
+	checks = append(checks, networkChecks...)
+
+	if settings.DatabasePath != "" {
+		checks = append(checks, timedCheck("Database", SelfTestCategoryDatabase, func() (string, error) {
+			return checkDatabase(NormalizePath(settings.DatabasePath))
+		}))
+	}
+
+	report := &SelfTestReport{Checks: checks}
+	for _, check := range checks {
+		if check.Passed {
+			report.Passed++
+		} else {
+			report.Failed++
+		}
+	}
+	report.Success = report.Failed == 0
+
+	fmt.Printf("[Self Test] %d passed, %d failed\n", report.Passed, report.Failed)
+	return report
+}
+
+func timedCheck(name, category string, run func() (string, error)) SelfTestCheck {
+	start := time.Now()
+	detail, err := run()
+	check := SelfTestCheck{
+		Name:       name,
+		Category:   category,
+		Passed:     err == nil,
+		Detail:     detail,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		check.Error = err.Error()
+	}
+	return check
+}
+
+// readToolVersion runs "<tool> -version" and returns the first line of its output
+func readToolVersion(path string) (string, error) {
+	if err := ValidateExecutable(path); err != nil {
+		return "", err
+	}
+
+	cmd := exec.Command(path, "-version")
+	setHideWindow(cmd)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run %s: %v", path, err)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	if scanner.Scan() {
+		return scanner.Text(), nil
+	}
+	return "", fmt.Errorf("no version output from %s", path)
+}
+
+// checkDirWritable creates (if needed) and writes a probe file into dir
+func checkDirWritable(dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory: %v", err)
+	}
+
+	probe, err := os.CreateTemp(dir, ".spotiflac-selftest-*")
+	if err != nil {
+		return "", fmt.Errorf("directory is not writable: %v", err)
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+	return dir, nil
+}
+
+// checkAnyReachable passes as soon as one URL answers without a server error
+func checkAnyReachable(urls []string) (string, error) {
+	if len(urls) == 0 {
+		return "", fmt.Errorf("no endpoints configured")
+	}
+
+	client := &http.Client{Timeout: selfTestHTTPTimeout}
+	var lastErr error
+	for _, u := range urls {
+		resp, err := client.Get(u)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 500 {
+			lastErr = fmt.Errorf("%s returned HTTP %d", u, resp.StatusCode)
+			continue
+		}
+		return fmt.Sprintf("%s (HTTP %d)", u, resp.StatusCode), nil
+	}
+	return "", fmt.Errorf("no endpoint reachable: %v", lastErr)
+}
+
+// checkDatabase opens the configured database and confirms it has a tracks table
+func checkDatabase(databasePath string) (string, error) {
+	if _, err := os.Stat(databasePath); err != nil {
+		return "", fmt.Errorf("database file not accessible: %v", err)
+	}
+
+	db, err := sql.Open("sqlite", databasePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		return "", fmt.Errorf("failed to connect to database: %v", err)
+	}
+
+	var tables int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'tracks'").Scan(&tables); err != nil {
+		return "", fmt.Errorf("failed to query database: %v", err)
+	}
+	if tables == 0 {
+		return "", fmt.Errorf("database has no tracks table")
+	}
+	return databasePath, nil
+}
@@ -0,0 +1,86 @@
+package backend
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const defaultShutdownGraceSeconds = 15
+
+var (
+	shutdownGrace     = defaultShutdownGraceSeconds * time.Second
+	shutdownGraceLock sync.RWMutex
+)
+
+// SetShutdownGrace sets how long quitting waits for running downloads and post-processing to
+// finish before exiting anyway (0 = don't wait, negative = default)
+func SetShutdownGrace(seconds int) {
+	if seconds < 0 {
+		seconds = defaultShutdownGraceSeconds
+	}
+
+	shutdownGraceLock.Lock()
+	shutdownGrace = time.Duration(seconds) * time.Second
+	shutdownGraceLock.Unlock()
+}
+
+func getShutdownGrace() time.Duration {
+	shutdownGraceLock.RLock()
+	defer shutdownGraceLock.RUnlock()
+	return shutdownGrace
+}
+
+// waitWithDeadline runs wait and reports whether it returned before deadline
+func waitWithDeadline(wait func(), deadline time.Time) bool {
+	done := make(chan struct{})
+	go func() {
+		wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(time.Until(deadline)):
+		return false
+	}
+}
+
+// Shutdown stops new work and gives running work the configured grace period to finish, so
+// quitting mid-download doesn't leave half-written files or half-tagged tracks behind. Queued
+// items are cancelled, workers stopped, temp covers deleted and idle HTTP connections closed.
+// Databases are opened per query, so there is no pool to close.
+func Shutdown() {
+	fmt.Println("[Shutdown] Stopping downloads...")
+	// Pending items are saved first so the next start restores them rather than the cancelled state
+	freezeQueueState()
+	CancelAllQueuedItems()
+	StopDownloadWorkers()
+
+	deadline := time.Now().Add(getShutdownGrace())
+	downloadsDone := waitWithDeadline(func() {
+		for {
+			downloadingLock.RLock()
+			active := activeDownloads
+			downloadingLock.RUnlock()
+			if active == 0 {
+				return
+			}
+			time.Sleep(200 * time.Millisecond)
+		}
+	}, deadline)
+	if !downloadsDone {
+		fmt.Println("[Shutdown] Warning: downloads still running at exit, their files may be incomplete")
+	}
+
+	if !waitWithDeadline(WaitForPostProcessing, deadline) {
+		fmt.Println("[Shutdown] Warning: post-processing still running at exit")
+	}
+
+	ReleaseSharedAlbumCover("")
+	if transport, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport.CloseIdleConnections()
+	}
+	fmt.Println("[Shutdown] Done")
+}
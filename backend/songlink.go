@@ -7,6 +7,8 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -18,8 +20,85 @@ type SongLinkClient struct {
 }
 
 type SongLinkURLs struct {
-	TidalURL  string `json:"tidal_url"`
-	AmazonURL string `json:"amazon_url"`
+	TidalURL  string            `json:"tidal_url"`
+	AmazonURL string            `json:"amazon_url"`
+	Platforms map[string]string `json:"platforms,omitempty"` // All song.link platform URLs keyed by platform
+}
+
+// serviceToSongLinkPlatform maps download service names to song.link platform keys
+var serviceToSongLinkPlatform = map[string]string{
+	"tidal":  "tidal",
+	"amazon": "amazonMusic",
+	"deezer": "deezer",
+}
+
+// Service URLs can change, and a long session or prefetch run shouldn't grow the cache forever
+const (
+	songLinkCacheTTL        = 6 * time.Hour
+	songLinkCacheMaxEntries = 5000
+)
+
+type songLinkCacheEntry struct {
+	urls      *SongLinkURLs
+	fetchedAt time.Time
+}
+
+var (
+	songLinkCache     = make(map[string]songLinkCacheEntry)
+	songLinkCacheLock sync.RWMutex
+)
+
+// PickServiceURL returns the URL for the given download service from a song.link
+// platform map, or empty if the service isn't present so the caller can fall back to search
+func PickServiceURL(urls map[string]string, service string) string {
+	if len(urls) == 0 || service == "" {
+		return ""
+	}
+
+	service = strings.ToLower(strings.TrimSpace(service))
+	if u, ok := urls[service]; ok && u != "" {
+		return u
+	}
+	if platform, ok := serviceToSongLinkPlatform[service]; ok {
+		if u, ok := urls[platform]; ok && u != "" {
+			return u
+		}
+	}
+	return ""
+}
+
+// GetCachedSongLinkURLs returns song.link URLs previously fetched for a Spotify track
+func GetCachedSongLinkURLs(spotifyTrackID string) (*SongLinkURLs, bool) {
+	songLinkCacheLock.RLock()
+	defer songLinkCacheLock.RUnlock()
+	entry, ok := songLinkCache[spotifyTrackID]
+	if !ok || time.Since(entry.fetchedAt) > songLinkCacheTTL {
+		return nil, false
+	}
+	return entry.urls, true
+}
+
+// cacheSongLinkURLs stores a lookup, first dropping expired entries and then the oldest one
+// when the cache is full
+func cacheSongLinkURLs(spotifyTrackID string, urls *SongLinkURLs) {
+	songLinkCacheLock.Lock()
+	defer songLinkCacheLock.Unlock()
+
+	if _, ok := songLinkCache[spotifyTrackID]; !ok && len(songLinkCache) >= songLinkCacheMaxEntries {
+		oldestID := ""
+		var oldest time.Time
+		for id, entry := range songLinkCache {
+			if time.Since(entry.fetchedAt) > songLinkCacheTTL {
+				delete(songLinkCache, id)
+			} else if oldestID == "" || entry.fetchedAt.Before(oldest) {
+				oldestID, oldest = id, entry.fetchedAt
+			}
+		}
+		if len(songLinkCache) >= songLinkCacheMaxEntries {
+			delete(songLinkCache, oldestID)
+		}
+	}
+	songLinkCache[spotifyTrackID] = songLinkCacheEntry{urls: urls, fetchedAt: time.Now()}
 }
 
 // TrackAvailability represents the availability of a track on different platforms
@@ -31,6 +110,16 @@ type TrackAvailability struct {
 	TidalURL  string `json:"tidal_url,omitempty"`
 	AmazonURL string `json:"amazon_url,omitempty"`
 	QobuzURL  string `json:"qobuz_url,omitempty"`
+
+	// Best quality each service's metadata endpoint reports, keyed by service
+	Quality           map[string]QualityInfo `json:"quality,omitempty"`
+	LosslessAvailable bool                   `json:"lossless_available"`
+	BestService       string                 `json:"best_service,omitempty"`
+}
+
+// AvailableOnAnyService reports whether at least one supported download service has the track
+func (a *TrackAvailability) AvailableOnAnyService() bool {
+	return a.Tidal || a.Amazon || a.Qobuz
 }
 
 func NewSongLinkClient() *SongLinkClient {
@@ -143,7 +232,14 @@ func (s *SongLinkClient) GetAllURLsFromSpotify(spotifyTrackID string) (*SongLink
 		return nil, fmt.Errorf("failed to decode response: %w (response: %s)", err, bodyStr)
 	}
 
-	urls := &SongLinkURLs{}
+	urls := &SongLinkURLs{
+		Platforms: make(map[string]string),
+	}
+	for platform, link := range songLinkResp.LinksByPlatform {
+		if link.URL != "" {
+			urls.Platforms[platform] = link.URL
+		}
+	}
 
 	// Extract Tidal URL
 	if tidalLink, ok := songLinkResp.LinksByPlatform["tidal"]; ok && tidalLink.URL != "" {
@@ -166,6 +262,8 @@ func (s *SongLinkClient) GetAllURLsFromSpotify(spotifyTrackID string) (*SongLink
 		return nil, fmt.Errorf("no streaming URLs found")
 	}
 
+	cacheSongLinkURLs(spotifyTrackID, urls)
+
 	return urls, nil
 }
 
@@ -293,6 +391,19 @@ func (s *SongLinkClient) CheckTrackAvailability(spotifyTrackID string, isrc stri
 		availability.Qobuz = qobuzAvailable
 	}
 
+	availability.Quality = collectQualityOptions(&SongLinkURLs{TidalURL: availability.TidalURL, AmazonURL: availability.AmazonURL}, isrc)
+	for _, info := range availability.Quality {
+		if info.Available && info.Lossless {
+			availability.LosslessAvailable = true
+		}
+	}
+	availability.BestService = bestQualityService(availability.Quality)
+	if isrc != "" {
+		qualityOptionsCacheLock.Lock()
+		qualityOptionsCache[strings.ToUpper(strings.TrimSpace(isrc))] = availability.Quality
+		qualityOptionsCacheLock.Unlock()
+	}
+
 	return availability, nil
 }
 
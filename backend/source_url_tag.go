@@ -0,0 +1,155 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	id3v2 "github.com/bogem/id3v2/v2"
+)
+
+// Tag frames holding the URL a file was downloaded from, per format:
+//
+//	FLAC  SOURCE_URL Vorbis comment
+//	MP3   WOAF frame ("official audio file webpage")
+//	M4A   SOURCE_URL freeform atom (ffmpeg -movflags use_metadata_tags)
+//
+// Together with SPOTIFY_ID this makes every file traceable to its source.
+const (
+	sourceURLVorbisField = "SOURCE_URL"
+	sourceURLID3Frame    = "WOAF"
+	sourceURLM4AKey      = "SOURCE_URL"
+)
+
+// SongLinkURLForSpotifyID returns the song.link page for a Spotify track, used as the source
+// URL when no direct service URL is known
+func SongLinkURLForSpotifyID(spotifyID string) string {
+	if spotifyID == "" {
+		return ""
+	}
+	return "https://song.link/s/" + spotifyID
+}
+
+// spotifyIDFromSourceURL extracts the Spotify track ID from a song.link source URL
+func spotifyIDFromSourceURL(sourceURL string) string {
+	const prefix = "https://song.link/s/"
+	if !strings.HasPrefix(sourceURL, prefix) {
+		return ""
+	}
+	return strings.Trim(strings.TrimPrefix(sourceURL, prefix), "/")
+}
+
+// EmbedSourceURL writes the song.link or service URL a file came from into its tags
+func EmbedSourceURL(filePath, sourceURL string) error {
+	if sourceURL == "" {
+		return nil
+	}
+
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".flac":
+		return setVorbisFields(filePath, map[string]string{sourceURLVorbisField: sourceURL})
+	case ".mp3":
+		return setMP3SourceURL(filePath, sourceURL)
+	case ".m4a":
+		return setM4ASourceURL(filePath, sourceURL)
+	default:
+		return fmt.Errorf("unsupported file format for source URL tag: %s", filepath.Ext(filePath))
+	}
+}
+
+func setMP3SourceURL(filePath, sourceURL string) error {
+	restore, err := prepareWritable(filePath)
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	tag, err := id3v2.Open(filePath, id3v2.Options{Parse: true})
+	if err != nil {
+		return fmt.Errorf("failed to open MP3 file: %w", err)
+	}
+	defer tag.Close()
+
+	// URL link frames are a bare ISO-8859-1 string with no encoding byte
+	tag.DeleteFrames(sourceURLID3Frame)
+	tag.AddFrame(sourceURLID3Frame, id3v2.UnknownFrame{Body: []byte(sourceURL)})
+	if err := tag.Save(); err != nil {
+		return fmt.Errorf("failed to save MP3 tags: %w", err)
+	}
+	return nil
+}
+
+func setM4ASourceURL(filePath, sourceURL string) error {
+	return setM4ATags(filePath, map[string]string{sourceURLM4AKey: sourceURL}, true)
+}
+
+// setM4ATags rewrites an M4A's metadata with ffmpeg, copying the streams. freeform keeps keys
+// ffmpeg has no standard atom for (like SOURCE_URL) as freeform atoms.
+func setM4ATags(filePath string, tags map[string]string, freeform bool) error {
+	ffmpegPath, err := GetFFmpegPath()
+	if err != nil {
+		return fmt.Errorf("ffmpeg not found: %w", err)
+	}
+	if err := ValidateExecutable(ffmpegPath); err != nil {
+		return fmt.Errorf("invalid ffmpeg executable: %w", err)
+	}
+
+	restore, err := prepareWritable(filePath)
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	tmpOutputFile := strings.TrimSuffix(filePath, filepath.Ext(filePath)) + ".tmp" + filepath.Ext(filePath)
+	defer func() {
+		if _, err := os.Stat(tmpOutputFile); err == nil {
+			os.Remove(tmpOutputFile)
+		}
+	}()
+
+	args := []string{"-i", filePath, "-map", "0", "-map_metadata", "0"}
+	for key, value := range tags {
+		args = append(args, "-metadata", key+"="+value)
+	}
+	if freeform {
+		args = append(args, "-movflags", "use_metadata_tags")
+	}
+	args = append(args, "-codec", "copy", "-f", "ipod", "-y", tmpOutputFile)
+
+	cmd := exec.Command(ffmpegPath, args...)
+	setHideWindow(cmd)
+
+	output, err := ffmpegCombinedOutput(cmd)
+	if err != nil {
+		return fmt.Errorf("ffmpeg failed to write tags: %s - %w", string(output), err)
+	}
+	if err := os.Rename(tmpOutputFile, filePath); err != nil {
+		return fmt.Errorf("failed to replace original file: %w", err)
+	}
+	return nil
+}
+
+// ReadSourceURLFromFile reads the source URL tag from a FLAC or MP3 file, empty when absent
+func ReadSourceURLFromFile(filePath string) (string, error) {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".flac":
+		return readVorbisField(filePath, sourceURLVorbisField)
+	case ".mp3":
+		tag, err := id3v2.Open(filePath, id3v2.Options{Parse: true})
+		if err != nil {
+			return "", fmt.Errorf("failed to open MP3 file: %w", err)
+		}
+		defer tag.Close()
+
+		for _, frame := range tag.GetFrames(sourceURLID3Frame) {
+			if unknown, ok := frame.(id3v2.UnknownFrame); ok && len(unknown.Body) > 0 {
+				return strings.TrimRight(string(unknown.Body), "\x00"), nil
+			}
+		}
+		return "", nil
+	default:
+		return "", nil
+	}
+}
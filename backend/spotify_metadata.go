@@ -104,6 +104,7 @@ type AlbumTrackMetadata struct {
 	TrackNumber int            `json:"track_number"`
 	TotalTracks int            `json:"total_tracks,omitempty"`
 	DiscNumber  int            `json:"disc_number,omitempty"`
+	TotalDiscs  int            `json:"total_discs,omitempty"`
 	ExternalURL string         `json:"external_urls"`
 	ISRC        string         `json:"isrc"`
 	AlbumType   string         `json:"album_type,omitempty"`
@@ -120,6 +121,7 @@ type TrackResponse struct {
 
 type AlbumInfoMetadata struct {
 	TotalTracks int    `json:"total_tracks"`
+	TotalDiscs  int    `json:"total_discs,omitempty"`
 	Name        string `json:"name"`
 	ReleaseDate string `json:"release_date"`
 	Artists     string `json:"artists"`
@@ -146,7 +148,8 @@ type PlaylistInfoMetadata struct {
 		Name        string `json:"name"`
 		Images      string `json:"images"`
 	} `json:"owner"`
-	Batch string `json:"batch,omitempty"`
+	Batch     string `json:"batch,omitempty"`
+	Truncated bool   `json:"truncated,omitempty"` // Stopped at the configured max playlist tracks
 }
 
 type PlaylistResponsePayload struct {
@@ -302,6 +305,7 @@ type playlistRaw struct {
 	Data         playlistResponse
 	BatchEnabled bool
 	BatchCount   int
+	Truncated    bool
 }
 
 type albumRaw struct {
@@ -396,10 +400,27 @@ func (c *SpotifyMetadataClient) fetchPlaylist(ctx context.Context, playlistID, t
 	if batch {
 		batchDelay = delay
 	}
-	batches, err := fetchPaging(ctx, c, tracksURL, token, batchDelay, &items)
+
+	// Playlists are paged 100 tracks at a time; report each page and stop at the configured max
+	maxTracks := GetMaxPlaylistTracks()
+	setPlaylistFetchProgress(PlaylistFetchProgress{PlaylistID: playlistID, Total: data.Tracks.Total, Active: true})
+	batches, err := fetchPagingWithLimit(ctx, c, tracksURL, token, batchDelay, &items, maxTracks, func(fetched int) {
+		setPlaylistFetchProgress(PlaylistFetchProgress{PlaylistID: playlistID, Fetched: fetched, Total: data.Tracks.Total, Active: true})
+	})
 	if err != nil {
+		setPlaylistFetchProgress(PlaylistFetchProgress{PlaylistID: playlistID, Fetched: len(items), Total: data.Tracks.Total})
 		return nil, err
 	}
+
+	if maxTracks > 0 && len(items) > maxTracks {
+		items = items[:maxTracks]
+	}
+	truncated := maxTracks > 0 && data.Tracks.Total > len(items)
+	if truncated {
+		fmt.Printf("[Spotify] Playlist %s has %d tracks, stopped at the configured max of %d\n", playlistID, data.Tracks.Total, maxTracks)
+	}
+	setPlaylistFetchProgress(PlaylistFetchProgress{PlaylistID: playlistID, Fetched: len(items), Total: data.Tracks.Total})
+
 	if len(items) > 0 {
 		data.Tracks.Items = items
 	}
@@ -408,6 +429,7 @@ func (c *SpotifyMetadataClient) fetchPlaylist(ctx context.Context, playlistID, t
 		Data:         data,
 		BatchEnabled: batch,
 		BatchCount:   batches,
+		Truncated:    truncated,
 	}, nil
 }
 
@@ -497,6 +519,7 @@ func (c *SpotifyMetadataClient) formatPlaylistData(raw *playlistRaw) PlaylistRes
 	if raw.BatchEnabled {
 		info.Batch = strconv.Itoa(maxInt(1, raw.BatchCount))
 	}
+	info.Truncated = raw.Truncated
 
 	tracks := make([]AlbumTrackMetadata, 0, len(raw.Data.Tracks.Items))
 	for _, item := range raw.Data.Tracks.Items {
@@ -585,6 +608,14 @@ func (c *SpotifyMetadataClient) formatAlbumData(ctx context.Context, raw *albumR
 		})
 	}
 
+	// Spotify doesn't report a disc count, so take the highest disc number on the album
+	for _, track := range tracks {
+		info.TotalDiscs = maxInt(info.TotalDiscs, track.DiscNumber)
+	}
+	for i := range tracks {
+		tracks[i].TotalDiscs = info.TotalDiscs
+	}
+
 	return &AlbumResponsePayload{
 		AlbumInfo: info,
 		TrackList: tracks,
@@ -746,6 +777,12 @@ func (c *SpotifyMetadataClient) fetchTrackISRC(ctx context.Context, trackID, tok
 }
 
 func fetchPaging[T any](ctx context.Context, client *SpotifyMetadataClient, nextURL, token string, delay time.Duration, dest *[]T) (int, error) {
+	return fetchPagingWithLimit(ctx, client, nextURL, token, delay, dest, 0, nil)
+}
+
+// fetchPagingWithLimit follows "next" links until exhausted or until at least limit items have
+// been collected (0 = no limit), calling onPage with the running item count after each page
+func fetchPagingWithLimit[T any](ctx context.Context, client *SpotifyMetadataClient, nextURL, token string, delay time.Duration, dest *[]T, limit int, onPage func(fetched int)) (int, error) {
 	batches := 0
 	for nextURL != "" {
 		select {
@@ -766,6 +803,13 @@ func fetchPaging[T any](ctx context.Context, client *SpotifyMetadataClient, next
 		nextURL = stripLocaleParam(page.Next)
 		batches++
 
+		if onPage != nil {
+			onPage(len(*dest))
+		}
+		if limit > 0 && len(*dest) >= limit {
+			break
+		}
+
 		if nextURL != "" && delay > 0 {
 			if err := sleepWithContext(ctx, delay); err != nil {
 				return batches, err
@@ -930,6 +974,8 @@ func parseSpotifyURI(input string) (spotifyURI, error) {
 			switch parts[1] {
 			case "album", "track", "playlist", "artist":
 				return spotifyURI{Type: parts[1], ID: parts[2]}, nil
+			case "episode", "show", "audiobook", "chapter":
+				return spotifyURI{}, &UnsupportedSpotifyTypeError{Type: parts[1], ID: parts[2]}
 			}
 		}
 	}
@@ -985,6 +1031,8 @@ func parseSpotifyURI(input string) (spotifyURI, error) {
 		switch parts[0] {
 		case "album", "track", "playlist", "artist":
 			return spotifyURI{Type: parts[0], ID: parts[1]}, nil
+		case "episode", "show", "audiobook", "chapter":
+			return spotifyURI{}, &UnsupportedSpotifyTypeError{Type: parts[0], ID: parts[1]}
 		}
 	}
 
@@ -1108,6 +1156,7 @@ type SearchResult struct {
 	Duration    int    `json:"duration_ms,omitempty"`
 	TotalTracks int    `json:"total_tracks,omitempty"`
 	Owner       string `json:"owner,omitempty"` // for playlists
+	ISRC        string `json:"isrc,omitempty"`  // for tracks
 }
 
 // SearchResponse contains search results grouped by type
@@ -1126,10 +1175,12 @@ type searchTracksResponse struct {
 			Name        string      `json:"name"`
 			DurationMS  int         `json:"duration_ms"`
 			ExternalURL externalURL `json:"external_urls"`
+			ExternalID  externalID  `json:"external_ids"`
 			Artists     []artist    `json:"artists"`
 			Album       struct {
 				ID          string      `json:"id"`
 				Name        string      `json:"name"`
+				AlbumType   string      `json:"album_type"`
 				Images      []image     `json:"images"`
 				ReleaseDate string      `json:"release_date"`
 				ExternalURL externalURL `json:"external_urls"`
@@ -1227,6 +1278,7 @@ func (c *SpotifyMetadataClient) Search(ctx context.Context, query string, limit
 			ReleaseDate: item.Album.ReleaseDate,
 			ExternalURL: item.ExternalURL.Spotify,
 			Duration:    item.DurationMS,
+			ISRC:        item.ExternalID.ISRC,
 		})
 	}
 
@@ -1327,6 +1379,7 @@ func (c *SpotifyMetadataClient) SearchByType(ctx context.Context, query string,
 				ReleaseDate: item.Album.ReleaseDate,
 				ExternalURL: item.ExternalURL.Spotify,
 				Duration:    item.DurationMS,
+				ISRC:        item.ExternalID.ISRC,
 			})
 		}
 	case "album":
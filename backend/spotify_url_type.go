@@ -0,0 +1,51 @@
+package backend
+
+import (
+	"errors"
+	"fmt"
+)
+
+// UnsupportedSpotifyTypeError is returned for Spotify links that point at content the app
+// cannot download, such as podcast episodes, shows and audiobooks
+type UnsupportedSpotifyTypeError struct {
+	Type string
+	ID   string
+}
+
+func (e *UnsupportedSpotifyTypeError) Error() string {
+	switch e.Type {
+	case "episode", "show":
+		return fmt.Sprintf("Spotify podcast %ss are not supported for download", e.Type)
+	case "audiobook", "chapter":
+		return fmt.Sprintf("Spotify %ss are not supported for download", e.Type)
+	}
+	return fmt.Sprintf("Spotify %s links are not supported", e.Type)
+}
+
+// SpotifyURLInfo describes what kind of Spotify link was pasted
+type SpotifyURLInfo struct {
+	Type      string `json:"type"`
+	ID        string `json:"id,omitempty"`
+	Supported bool   `json:"supported"`
+	Message   string `json:"message,omitempty"`
+}
+
+// ClassifySpotifyURL identifies a Spotify link's type without fetching anything, so the UI
+// can reject podcast and audiobook links up front with a clear message
+func ClassifySpotifyURL(input string) SpotifyURLInfo {
+	parsed, err := parseSpotifyURI(input)
+	if err != nil {
+		var unsupported *UnsupportedSpotifyTypeError
+		if errors.As(err, &unsupported) {
+			return SpotifyURLInfo{Type: unsupported.Type, ID: unsupported.ID, Message: unsupported.Error()}
+		}
+		return SpotifyURLInfo{Type: "unknown", Message: errInvalidSpotifyURL.Error()}
+	}
+	return SpotifyURLInfo{Type: parsed.Type, ID: parsed.ID, Supported: true}
+}
+
+// IsUnsupportedSpotifyType reports whether err came from a podcast, show or audiobook link
+func IsUnsupportedSpotifyType(err error) bool {
+	var unsupported *UnsupportedSpotifyTypeError
+	return errors.As(err, &unsupported)
+}
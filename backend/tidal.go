@@ -605,33 +605,17 @@ func (t *TidalDownloader) DownloadFile(url, filepath string) error {
 		return t.DownloadFromManifest(strings.TrimPrefix(url, "MANIFEST:"), filepath)
 	}
 
-	resp, err := t.client.Get(url)
-
-	if err != nil {
-		return fmt.Errorf("failed to download file: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("download failed with status %d", resp.StatusCode)
-	}
-
-	out, err := os.Create(filepath)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
+	// Large hi-res files go over several ranged connections when enabled and supported
+	if done, err := downloadChunked(t.client, url, filepath); done {
+		return nil
+	} else if err != nil {
+		fmt.Printf("[Chunked] %v, retrying as a single stream\n", err)
 	}
-	defer out.Close()
 
-	// Use progress writer to track download
-	pw := NewProgressWriter(out)
-	_, err = io.Copy(pw, resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
+	if err := downloadResumable(t.client, url, filepath, nil); err != nil {
+		return err
 	}
 
-	// Print final size
-	fmt.Printf("\rDownloaded: %.2f MB (Complete)\n", float64(pw.GetTotal())/(1024*1024))
-
 	fmt.Println("Download complete")
 	return nil
 }
@@ -652,30 +636,9 @@ func (t *TidalDownloader) DownloadFromManifest(manifestB64, outputPath string) e
 	if directURL != "" {
 		fmt.Println("Downloading file...")
 
-		resp, err := client.Get(directURL)
-		if err != nil {
-			return fmt.Errorf("failed to download file: %w", err)
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != 200 {
-			return fmt.Errorf("download failed with status %d", resp.StatusCode)
+		if err := downloadResumable(client, directURL, outputPath, nil); err != nil {
+			return err
 		}
-
-		out, err := os.Create(outputPath)
-		if err != nil {
-			return fmt.Errorf("failed to create file: %w", err)
-		}
-		defer out.Close()
-
-		// Use progress writer to track download
-		pw := NewProgressWriter(out)
-		_, err = io.Copy(pw, resp.Body)
-		if err != nil {
-			return fmt.Errorf("failed to write file: %w", err)
-		}
-
-		fmt.Printf("\rDownloaded: %.2f MB (Complete)\n", float64(pw.GetTotal())/(1024*1024))
 		fmt.Println("Download complete")
 		return nil
 	}
@@ -702,7 +665,7 @@ func (t *TidalDownloader) DownloadFromManifest(manifestB64, outputPath string) e
 		resp.Body.Close()
 		out.Close()
 		os.Remove(tempPath)
-		return fmt.Errorf("init segment download failed with status %d", resp.StatusCode)
+		return fmt.Errorf("init segment: %w", downloadStatusError(resp.StatusCode))
 	}
 	_, err = io.Copy(out, resp.Body)
 	resp.Body.Close()
@@ -729,7 +692,7 @@ func (t *TidalDownloader) DownloadFromManifest(manifestB64, outputPath string) e
 			resp.Body.Close()
 			out.Close()
 			os.Remove(tempPath)
-			return fmt.Errorf("segment %d download failed with status %d", i+1, resp.StatusCode)
+			return fmt.Errorf("segment %d: %w", i+1, downloadStatusError(resp.StatusCode))
 		}
 		n, err := io.Copy(out, resp.Body)
 		totalBytes += n
@@ -768,10 +731,18 @@ func (t *TidalDownloader) DownloadFromManifest(manifestB64, outputPath string) e
 	// Remux M4A to FLAC using ffmpeg
 	// DASH segments are in fMP4 container with FLAC codec, need to extract to native FLAC
 	fmt.Println("Converting to FLAC...")
-	cmd := exec.Command("ffmpeg", "-y", "-i", tempPath, "-vn", "-c:a", "flac", outputPath)
+	ffmpegPath, err := GetFFmpegPath()
+	if err != nil {
+		return fmt.Errorf("failed to locate ffmpeg: %w", err)
+	}
+	if _, statErr := os.Stat(ffmpegPath); statErr != nil {
+		ffmpegPath = "ffmpeg" // Fall back to ffmpeg on PATH
+	}
+	cmd := exec.Command(ffmpegPath, "-y", "-i", tempPath, "-vn", "-c:a", "flac", outputPath)
+	setHideWindow(cmd)
 	var stderr strings.Builder
 	cmd.Stderr = &stderr
-	if err := cmd.Run(); err != nil {
+	if err := runFFmpeg(cmd); err != nil {
 		// If ffmpeg fails, try to keep the M4A file for debugging
 		m4aPath := strings.TrimSuffix(outputPath, ".flac") + ".m4a"
 		os.Rename(tempPath, m4aPath)
@@ -785,10 +756,10 @@ func (t *TidalDownloader) DownloadFromManifest(manifestB64, outputPath string) e
 	return nil
 }
 
-func (t *TidalDownloader) DownloadByURL(tidalURL, outputDir, quality, filenameFormat string, includeTrackNumber bool, position int, spotifyTrackName, spotifyArtistName, spotifyAlbumName, spotifyAlbumArtist, spotifyReleaseDate string, useAlbumTrackNumber bool, spotifyCoverURL string, embedMaxQualityCover bool, spotifyTrackNumber, spotifyDiscNumber, spotifyTotalTracks int, spotifyISRC string) (string, error) {
+func (t *TidalDownloader) DownloadByURL(tidalURL, outputDir, quality, filenameFormat string, includeTrackNumber bool, position int, spotifyTrackName, spotifyArtistName, spotifyAlbumName, spotifyAlbumArtist, spotifyReleaseDate string, useAlbumTrackNumber bool, spotifyCoverURL string, embedMaxQualityCover bool, spotifyTrackNumber, spotifyDiscNumber, spotifyTotalTracks int, spotifyISRC string) (DownloadResult, error) {
 	if outputDir != "." {
 		if err := os.MkdirAll(outputDir, 0755); err != nil {
-			return "", fmt.Errorf("directory error: %w", err)
+			return DownloadResult{}, fmt.Errorf("directory error: %w", err)
 		}
 	}
 
@@ -797,17 +768,17 @@ func (t *TidalDownloader) DownloadByURL(tidalURL, outputDir, quality, filenameFo
 	// Extract track ID from URL
 	trackID, err := t.GetTrackIDFromURL(tidalURL)
 	if err != nil {
-		return "", err
+		return DownloadResult{}, err
 	}
 
 	// Get track info by ID
 	trackInfo, err := t.GetTrackInfoByID(trackID)
 	if err != nil {
-		return "", err
+		return DownloadResult{}, err
 	}
 
 	if trackInfo.ID == 0 {
-		return "", fmt.Errorf("no track ID found")
+		return DownloadResult{}, fmt.Errorf("no track ID found")
 	}
 
 	// All metadata from Spotify - no fallback to Tidal
@@ -824,26 +795,33 @@ func (t *TidalDownloader) DownloadByURL(tidalURL, outputDir, quality, filenameFo
 	// Check if file with same ISRC already exists
 	if existingFile, exists := CheckISRCExists(outputDir, trackInfo.ISRC); exists {
 		fmt.Printf("File with ISRC %s already exists: %s\n", trackInfo.ISRC, existingFile)
-		return "EXISTS:" + existingFile, nil
+		return DownloadResult{Path: existingFile, AlreadyExisted: true}, nil
 	}
 
 	// Build filename based on format settings (use sanitized versions for filename)
-	filename := buildTidalFilename(trackTitleForFile, artistNameForFile, albumTitleForFile, albumArtistForFile, spotifyReleaseDate, trackInfo.TrackNumber, spotifyDiscNumber, filenameFormat, includeTrackNumber, position, useAlbumTrackNumber)
+	// Cross-check Tidal's album numbering against Spotify's
+	trackNumberForFile, trackNumberMismatch := ReconcileTrackNumber(spotifyTrackNumber, trackInfo.TrackNumber)
+
+	filename := buildTidalFilename(trackTitleForFile, artistNameForFile, albumTitleForFile, albumArtistForFile, spotifyReleaseDate, trackNumberForFile, spotifyDiscNumber, filenameFormat, includeTrackNumber, position, useAlbumTrackNumber)
 	outputFilename := filepath.Join(outputDir, filename)
+	if trackNumberMismatch {
+		recordTrackNumberMismatch(outputFilename, "tidal", spotifyTrackNumber, trackInfo.TrackNumber)
+	}
 
 	if fileInfo, err := os.Stat(outputFilename); err == nil && fileInfo.Size() > 0 {
 		fmt.Printf("File already exists: %s (%.2f MB)\n", outputFilename, float64(fileInfo.Size())/(1024*1024))
-		return "EXISTS:" + outputFilename, nil
+		return DownloadResult{Path: outputFilename, AlreadyExisted: true}, nil
 	}
 
 	downloadURL, err := t.GetDownloadURL(trackInfo.ID, quality)
 	if err != nil {
-		return "", err
+		return DownloadResult{}, err
 	}
 
 	fmt.Printf("Downloading to: %s\n", outputFilename)
-	if err := t.DownloadFile(downloadURL, outputFilename); err != nil {
-		return "", err
+	resolve := func() (string, error) { return t.GetDownloadURL(trackInfo.ID, quality) }
+	if err := downloadWithFreshURL(downloadURL, resolve, func(url string) error { return t.DownloadFile(url, outputFilename) }); err != nil {
+		return DownloadResult{}, err
 	}
 
 	fmt.Println("Adding metadata...")
@@ -892,18 +870,18 @@ func (t *TidalDownloader) DownloadByURL(tidalURL, outputDir, quality, filenameFo
 
 	fmt.Println("Done")
 	fmt.Println("✓ Downloaded successfully from Tidal")
-	return outputFilename, nil
+	return DownloadResult{Path: outputFilename}, nil
 }
 
-func (t *TidalDownloader) DownloadByURLWithFallback(tidalURL, outputDir, quality, filenameFormat string, includeTrackNumber bool, position int, spotifyTrackName, spotifyArtistName, spotifyAlbumName, spotifyAlbumArtist, spotifyReleaseDate string, useAlbumTrackNumber bool, spotifyCoverURL string, embedMaxQualityCover bool, spotifyTrackNumber, spotifyDiscNumber, spotifyTotalTracks int, spotifyISRC string) (string, error) {
+func (t *TidalDownloader) DownloadByURLWithFallback(tidalURL, outputDir, quality, filenameFormat string, includeTrackNumber bool, position int, spotifyTrackName, spotifyArtistName, spotifyAlbumName, spotifyAlbumArtist, spotifyReleaseDate string, useAlbumTrackNumber bool, spotifyCoverURL string, embedMaxQualityCover bool, spotifyTrackNumber, spotifyDiscNumber, spotifyTotalTracks int, spotifyISRC string) (DownloadResult, error) {
 	apis, err := t.GetAvailableAPIs()
 	if err != nil {
-		return "", fmt.Errorf("no APIs available for fallback: %w", err)
+		return DownloadResult{}, fmt.Errorf("no APIs available for fallback: %w", err)
 	}
 
 	if outputDir != "." {
 		if err := os.MkdirAll(outputDir, 0755); err != nil {
-			return "", fmt.Errorf("directory error: %w", err)
+			return DownloadResult{}, fmt.Errorf("directory error: %w", err)
 		}
 	}
 
@@ -912,17 +890,17 @@ func (t *TidalDownloader) DownloadByURLWithFallback(tidalURL, outputDir, quality
 	// Extract track ID from URL
 	trackID, err := t.GetTrackIDFromURL(tidalURL)
 	if err != nil {
-		return "", err
+		return DownloadResult{}, err
 	}
 
 	// Get track info by ID
 	trackInfo, err := t.GetTrackInfoByID(trackID)
 	if err != nil {
-		return "", err
+		return DownloadResult{}, err
 	}
 
 	if trackInfo.ID == 0 {
-		return "", fmt.Errorf("no track ID found")
+		return DownloadResult{}, fmt.Errorf("no track ID found")
 	}
 
 	// All metadata from Spotify - no fallback to Tidal
@@ -939,29 +917,44 @@ func (t *TidalDownloader) DownloadByURLWithFallback(tidalURL, outputDir, quality
 	// Check if file with same ISRC already exists
 	if existingFile, exists := CheckISRCExists(outputDir, trackInfo.ISRC); exists {
 		fmt.Printf("File with ISRC %s already exists: %s\n", trackInfo.ISRC, existingFile)
-		return "EXISTS:" + existingFile, nil
+		return DownloadResult{Path: existingFile, AlreadyExisted: true}, nil
 	}
 
-	filename := buildTidalFilename(trackTitleForFile, artistNameForFile, albumTitleForFile, albumArtistForFile, spotifyReleaseDate, trackInfo.TrackNumber, spotifyDiscNumber, filenameFormat, includeTrackNumber, position, useAlbumTrackNumber)
+	// Cross-check Tidal's album numbering against Spotify's
+	trackNumberForFile, trackNumberMismatch := ReconcileTrackNumber(spotifyTrackNumber, trackInfo.TrackNumber)
+
+	filename := buildTidalFilename(trackTitleForFile, artistNameForFile, albumTitleForFile, albumArtistForFile, spotifyReleaseDate, trackNumberForFile, spotifyDiscNumber, filenameFormat, includeTrackNumber, position, useAlbumTrackNumber)
 	outputFilename := filepath.Join(outputDir, filename)
+	if trackNumberMismatch {
+		recordTrackNumberMismatch(outputFilename, "tidal", spotifyTrackNumber, trackInfo.TrackNumber)
+	}
 
 	if fileInfo, err := os.Stat(outputFilename); err == nil && fileInfo.Size() > 0 {
 		fmt.Printf("File already exists: %s (%.2f MB)\n", outputFilename, float64(fileInfo.Size())/(1024*1024))
-		return "EXISTS:" + outputFilename, nil
+		return DownloadResult{Path: outputFilename, AlreadyExisted: true}, nil
 	}
 
 	// Request download URL from ALL APIs in parallel - use first success
-	successAPI, downloadURL, err := getDownloadURLParallel(apis, trackInfo.ID, quality)
+	choice, downloadURL, err := getDownloadURLParallel(apis, trackInfo.ID, quality)
 	if err != nil {
-		return "", err
+		return DownloadResult{}, err
 	}
 
 	// Download the file
 	fmt.Printf("Downloading to: %s\n", outputFilename)
-	downloader := NewTidalDownloader(successAPI)
-	if err := downloader.DownloadFile(downloadURL, outputFilename); err != nil {
-		return "", err
+	downloader := NewTidalDownloader(choice.API)
+	if err := downloadWithFreshURL(downloadURL, func() (string, error) {
+		freshChoice, freshURL, err := getDownloadURLParallel(apis, trackInfo.ID, quality)
+		if err != nil {
+			return "", err
+		}
+		choice = freshChoice
+		downloader = NewTidalDownloader(choice.API)
+		return freshURL, nil
+	}, func(url string) error { return downloader.DownloadFile(url, outputFilename) }); err != nil {
+		return DownloadResult{}, err
 	}
+	recordTidalAPIChoice(outputFilename, choice)
 
 	fmt.Println("Adding metadata...")
 
@@ -1007,10 +1000,10 @@ func (t *TidalDownloader) DownloadByURLWithFallback(tidalURL, outputDir, quality
 
 	fmt.Println("Done")
 	fmt.Println("✓ Downloaded successfully from Tidal")
-	return outputFilename, nil
+	return DownloadResult{Path: outputFilename}, nil
 }
 
-func (t *TidalDownloader) Download(spotifyTrackID, outputDir, quality, filenameFormat string, includeTrackNumber bool, position int, spotifyTrackName, spotifyArtistName, spotifyAlbumName, spotifyAlbumArtist, spotifyReleaseDate string, useAlbumTrackNumber bool, spotifyCoverURL string, embedMaxQualityCover bool, spotifyTrackNumber, spotifyDiscNumber, spotifyTotalTracks int, spotifyISRC string) (string, error) {
+func (t *TidalDownloader) Download(spotifyTrackID, outputDir, quality, filenameFormat string, includeTrackNumber bool, position int, spotifyTrackName, spotifyArtistName, spotifyAlbumName, spotifyAlbumArtist, spotifyReleaseDate string, useAlbumTrackNumber bool, spotifyCoverURL string, embedMaxQualityCover bool, spotifyTrackNumber, spotifyDiscNumber, spotifyTotalTracks int, spotifyISRC string) (DownloadResult, error) {
 	// Get Tidal URL from Spotify track ID
 	tidalURL, err := t.GetTidalURLFromSpotify(spotifyTrackID)
 	if err != nil {
@@ -1024,7 +1017,7 @@ func (t *TidalDownloader) Download(spotifyTrackID, outputDir, quality, filenameF
 }
 
 // DownloadWithISRC downloads a track with ISRC matching for search fallback
-func (t *TidalDownloader) DownloadWithISRC(spotifyTrackID, spotifyISRC, outputDir, quality, filenameFormat string, includeTrackNumber bool, position int, spotifyTrackName, spotifyArtistName, spotifyAlbumName, spotifyAlbumArtist, spotifyReleaseDate string, useAlbumTrackNumber bool, expectedDuration int, spotifyCoverURL string, embedMaxQualityCover bool, spotifyTrackNumber, spotifyDiscNumber, spotifyTotalTracks int) (string, error) {
+func (t *TidalDownloader) DownloadWithISRC(spotifyTrackID, spotifyISRC, outputDir, quality, filenameFormat string, includeTrackNumber bool, position int, spotifyTrackName, spotifyArtistName, spotifyAlbumName, spotifyAlbumArtist, spotifyReleaseDate string, useAlbumTrackNumber bool, expectedDuration int, spotifyCoverURL string, embedMaxQualityCover bool, spotifyTrackNumber, spotifyDiscNumber, spotifyTotalTracks int) (DownloadResult, error) {
 	// Get Tidal URL from Spotify track ID
 	tidalURL, err := t.GetTidalURLFromSpotify(spotifyTrackID)
 	if err != nil {
@@ -1039,26 +1032,26 @@ func (t *TidalDownloader) DownloadWithISRC(spotifyTrackID, spotifyISRC, outputDi
 
 // DownloadBySearch downloads a track by searching Tidal directly using metadata
 // This is used as a fallback when Songlink API doesn't find a Tidal URL
-func (t *TidalDownloader) DownloadBySearch(trackName, artistName, albumName, albumArtist, releaseDate, spotifyISRC string, expectedDuration int, outputDir, quality, filenameFormat string, includeTrackNumber bool, position int, useAlbumTrackNumber bool, spotifyCoverURL string, embedMaxQualityCover bool, spotifyTrackNumber, spotifyDiscNumber, spotifyTotalTracks int) (string, error) {
+func (t *TidalDownloader) DownloadBySearch(trackName, artistName, albumName, albumArtist, releaseDate, spotifyISRC string, expectedDuration int, outputDir, quality, filenameFormat string, includeTrackNumber bool, position int, useAlbumTrackNumber bool, spotifyCoverURL string, embedMaxQualityCover bool, spotifyTrackNumber, spotifyDiscNumber, spotifyTotalTracks int) (DownloadResult, error) {
 	return t.DownloadBySearchWithISRC(trackName, artistName, albumName, albumArtist, releaseDate, spotifyISRC, expectedDuration, outputDir, quality, filenameFormat, includeTrackNumber, position, useAlbumTrackNumber, spotifyCoverURL, embedMaxQualityCover, spotifyTrackNumber, spotifyDiscNumber, spotifyTotalTracks)
 }
 
 // DownloadBySearchWithISRC downloads a track by searching Tidal with ISRC matching
-func (t *TidalDownloader) DownloadBySearchWithISRC(trackName, artistName, albumName, albumArtist, releaseDate, spotifyISRC string, expectedDuration int, outputDir, quality, filenameFormat string, includeTrackNumber bool, position int, useAlbumTrackNumber bool, spotifyCoverURL string, embedMaxQualityCover bool, spotifyTrackNumber, spotifyDiscNumber, spotifyTotalTracks int) (string, error) {
+func (t *TidalDownloader) DownloadBySearchWithISRC(trackName, artistName, albumName, albumArtist, releaseDate, spotifyISRC string, expectedDuration int, outputDir, quality, filenameFormat string, includeTrackNumber bool, position int, useAlbumTrackNumber bool, spotifyCoverURL string, embedMaxQualityCover bool, spotifyTrackNumber, spotifyDiscNumber, spotifyTotalTracks int) (DownloadResult, error) {
 	if outputDir != "." {
 		if err := os.MkdirAll(outputDir, 0755); err != nil {
-			return "", fmt.Errorf("directory error: %w", err)
+			return DownloadResult{}, fmt.Errorf("directory error: %w", err)
 		}
 	}
 
 	// Search for the track with ISRC matching
 	trackInfo, err := t.SearchTrackByMetadataWithISRC(trackName, artistName, spotifyISRC, expectedDuration)
 	if err != nil {
-		return "", fmt.Errorf("search fallback failed: %w", err)
+		return DownloadResult{}, fmt.Errorf("search fallback failed: %w", err)
 	}
 
 	if trackInfo.ID == 0 {
-		return "", fmt.Errorf("no track ID found from search")
+		return DownloadResult{}, fmt.Errorf("no track ID found from search")
 	}
 
 	// All metadata from Spotify - no fallback to Tidal
@@ -1075,27 +1068,31 @@ func (t *TidalDownloader) DownloadBySearchWithISRC(trackName, artistName, albumN
 	// Check if file with same ISRC already exists (use Spotify ISRC)
 	if existingFile, exists := CheckISRCExists(outputDir, spotifyISRC); exists {
 		fmt.Printf("File with ISRC %s already exists: %s\n", spotifyISRC, existingFile)
-		return "EXISTS:" + existingFile, nil
+		return DownloadResult{Path: existingFile, AlreadyExisted: true}, nil
 	}
 
 	// Build filename
 	filename := buildTidalFilename(finalTrackTitleForFile, finalArtistNameForFile, finalAlbumTitleForFile, finalAlbumArtistForFile, releaseDate, spotifyTrackNumber, spotifyDiscNumber, filenameFormat, includeTrackNumber, position, useAlbumTrackNumber)
 	outputFilename := filepath.Join(outputDir, filename)
+	if _, mismatch := ReconcileTrackNumber(spotifyTrackNumber, trackInfo.TrackNumber); mismatch {
+		recordTrackNumberMismatch(outputFilename, "tidal", spotifyTrackNumber, trackInfo.TrackNumber)
+	}
 
 	if fileInfo, err := os.Stat(outputFilename); err == nil && fileInfo.Size() > 0 {
 		fmt.Printf("File already exists: %s (%.2f MB)\n", outputFilename, float64(fileInfo.Size())/(1024*1024))
-		return "EXISTS:" + outputFilename, nil
+		return DownloadResult{Path: outputFilename, AlreadyExisted: true}, nil
 	}
 
 	// Get download URL
 	downloadURL, err := t.GetDownloadURL(trackInfo.ID, quality)
 	if err != nil {
-		return "", err
+		return DownloadResult{}, err
 	}
 
 	fmt.Printf("Downloading to: %s\n", outputFilename)
-	if err := t.DownloadFile(downloadURL, outputFilename); err != nil {
-		return "", err
+	resolve := func() (string, error) { return t.GetDownloadURL(trackInfo.ID, quality) }
+	if err := downloadWithFreshURL(downloadURL, resolve, func(url string) error { return t.DownloadFile(url, outputFilename) }); err != nil {
+		return DownloadResult{}, err
 	}
 
 	fmt.Println("Adding metadata...")
@@ -1142,7 +1139,7 @@ func (t *TidalDownloader) DownloadBySearchWithISRC(trackName, artistName, albumN
 
 	fmt.Println("Done")
 	fmt.Println("✓ Downloaded successfully from Tidal (via search)")
-	return outputFilename, nil
+	return DownloadResult{Path: outputFilename}, nil
 }
 
 // DASH MPD XML structures for parsing manifest
@@ -1258,16 +1255,29 @@ func parseManifest(manifestB64 string) (directURL string, initURL string, mediaU
 
 // manifestResult holds the result from a parallel API request for v2 API
 type manifestResult struct {
-	apiURL   string
-	manifest string
-	err      error
+	apiURL       string
+	manifest     string
+	audioQuality string
+	bitDepth     int
+	sampleRate   int
+	err          error
+}
+
+// downloadURL converts a successful result to the form DownloadFile expects
+func (r manifestResult) downloadURL() string {
+	// Check if it's a direct URL (v1) or manifest (v2)
+	if strings.HasPrefix(r.manifest, "DIRECT:") {
+		return strings.TrimPrefix(r.manifest, "DIRECT:")
+	}
+	return "MANIFEST:" + r.manifest
 }
 
 // getDownloadURLParallel requests download URL from all APIs in parallel
-// Returns the first successful result (supports both v1 and v2 API formats)
-func getDownloadURLParallel(apis []string, trackID int64, quality string) (string, string, error) {
+// Returns the first successful result (supports both v1 and v2 API formats), or with
+// best-quality selection enabled the highest quality answer
+func getDownloadURLParallel(apis []string, trackID int64, quality string) (TidalAPIChoice, string, error) {
 	if len(apis) == 0 {
-		return "", "", fmt.Errorf("no APIs available")
+		return TidalAPIChoice{}, "", fmt.Errorf("no APIs available")
 	}
 
 	resultChan := make(chan manifestResult, len(apis))
@@ -1304,7 +1314,13 @@ func getDownloadURLParallel(apis []string, trackID int64, quality string) (strin
 			// Try v2 format first (object with manifest)
 			var v2Response TidalAPIResponseV2
 			if err := json.Unmarshal(body, &v2Response); err == nil && v2Response.Data.Manifest != "" {
-				resultChan <- manifestResult{apiURL: api, manifest: v2Response.Data.Manifest, err: nil}
+				resultChan <- manifestResult{
+					apiURL:       api,
+					manifest:     v2Response.Data.Manifest,
+					audioQuality: v2Response.Data.AudioQuality,
+					bitDepth:     v2Response.Data.BitDepth,
+					sampleRate:   v2Response.Data.SampleRate,
+				}
 				return
 			}
 
@@ -1324,53 +1340,75 @@ func getDownloadURLParallel(apis []string, trackID int64, quality string) (strin
 		}(apiURL)
 	}
 
-	// Collect results - return first success
+	// Collect results - return first success, or keep comparing for a short grace period
+	// when the best quality is wanted
+	preferBest := isTidalPreferBestQualityEnabled()
+	var best *manifestResult
+	var grace <-chan time.Time
 	var lastError error
 	var errors []string
 
-	for i := 0; i < len(apis); i++ {
-		result := <-resultChan
-		if result.err == nil && result.manifest != "" {
-			// First success - use this one
-			fmt.Printf("✓ Got response from: %s\n", result.apiURL)
-
-			// Check if it's a direct URL (v1) or manifest (v2)
-			if strings.HasPrefix(result.manifest, "DIRECT:") {
-				return result.apiURL, strings.TrimPrefix(result.manifest, "DIRECT:"), nil
-			}
-
-			// It's a v2 manifest - return it with MANIFEST: prefix
-			return result.apiURL, "MANIFEST:" + result.manifest, nil
-		} else {
-			errMsg := result.err.Error()
-			if len(errMsg) > 50 {
-				errMsg = errMsg[:50] + "..."
+collect:
+	for received := 0; received < len(apis); {
+		select {
+		case result := <-resultChan:
+			received++
+			if result.err == nil && result.manifest != "" {
+				fmt.Printf("✓ Got response from: %s (%s)\n", result.apiURL, result.choice().label())
+				if !preferBest {
+					// First success - use this one
+					return result.choice(), result.downloadURL(), nil
+				}
+				if best == nil || result.score() > best.score() {
+					best = &result
+				}
+				if best.meetsRequested(quality) {
+					break collect
+				}
+				if grace == nil {
+					grace = time.After(tidalQualityGrace)
+				}
+			} else {
+				if result.err == nil {
+					result.err = fmt.Errorf("empty manifest")
+				}
+				errMsg := result.err.Error()
+				if len(errMsg) > 50 {
+					errMsg = errMsg[:50] + "..."
+				}
+				errors = append(errors, fmt.Sprintf("%s: %s", result.apiURL, errMsg))
+				lastError = result.err
 			}
-			errors = append(errors, fmt.Sprintf("%s: %s", result.apiURL, errMsg))
-			lastError = result.err
+		case <-grace:
+			break collect
 		}
 	}
 
+	if best != nil {
+		fmt.Printf("✓ Using best quality API: %s (%s)\n", best.apiURL, best.choice().label())
+		return best.choice(), best.downloadURL(), nil
+	}
+
 	// Print all errors for debugging
 	fmt.Println("All APIs failed:")
 	for _, e := range errors {
 		fmt.Printf("  ✗ %s\n", e)
 	}
 
-	return "", "", fmt.Errorf("all %d APIs failed. Last error: %v", len(apis), lastError)
+	return TidalAPIChoice{}, "", fmt.Errorf("all %d APIs failed. Last error: %v", len(apis), lastError)
 }
 
 // DownloadBySearchWithFallback tries multiple APIs when downloading via search
 // Search is done ONCE, then requests all APIs in PARALLEL for download URL
-func (t *TidalDownloader) DownloadBySearchWithFallback(trackName, artistName, albumName, albumArtist, releaseDate, spotifyISRC string, expectedDuration int, outputDir, quality, filenameFormat string, includeTrackNumber bool, position int, useAlbumTrackNumber bool, spotifyCoverURL string, embedMaxQualityCover bool, spotifyTrackNumber, spotifyDiscNumber, spotifyTotalTracks int) (string, error) {
+func (t *TidalDownloader) DownloadBySearchWithFallback(trackName, artistName, albumName, albumArtist, releaseDate, spotifyISRC string, expectedDuration int, outputDir, quality, filenameFormat string, includeTrackNumber bool, position int, useAlbumTrackNumber bool, spotifyCoverURL string, embedMaxQualityCover bool, spotifyTrackNumber, spotifyDiscNumber, spotifyTotalTracks int) (DownloadResult, error) {
 	apis, err := t.GetAvailableAPIs()
 	if err != nil {
-		return "", fmt.Errorf("no APIs available for fallback: %w", err)
+		return DownloadResult{}, fmt.Errorf("no APIs available for fallback: %w", err)
 	}
 
 	if outputDir != "." {
 		if err := os.MkdirAll(outputDir, 0755); err != nil {
-			return "", fmt.Errorf("directory error: %w", err)
+			return DownloadResult{}, fmt.Errorf("directory error: %w", err)
 		}
 	}
 
@@ -1378,11 +1416,11 @@ func (t *TidalDownloader) DownloadBySearchWithFallback(trackName, artistName, al
 	fmt.Println("Searching for track...")
 	trackInfo, err := t.SearchTrackByMetadataWithISRC(trackName, artistName, spotifyISRC, expectedDuration)
 	if err != nil {
-		return "", fmt.Errorf("search failed: %w", err)
+		return DownloadResult{}, fmt.Errorf("search failed: %w", err)
 	}
 
 	if trackInfo.ID == 0 {
-		return "", fmt.Errorf("no track ID found from search")
+		return DownloadResult{}, fmt.Errorf("no track ID found from search")
 	}
 
 	fmt.Printf("Track found: %s - %s (ID: %d)\n", trackInfo.Artist.Name, trackInfo.Title, trackInfo.ID)
@@ -1401,29 +1439,41 @@ func (t *TidalDownloader) DownloadBySearchWithFallback(trackName, artistName, al
 	// Check if file already exists (use Spotify ISRC)
 	if existingFile, exists := CheckISRCExists(outputDir, spotifyISRC); exists {
 		fmt.Printf("File with ISRC %s already exists: %s\n", spotifyISRC, existingFile)
-		return "EXISTS:" + existingFile, nil
+		return DownloadResult{Path: existingFile, AlreadyExisted: true}, nil
 	}
 
 	filename := buildTidalFilename(finalTrackTitleForFile, finalArtistNameForFile, finalAlbumTitleForFile, finalAlbumArtistForFile, releaseDate, spotifyTrackNumber, spotifyDiscNumber, filenameFormat, includeTrackNumber, position, useAlbumTrackNumber)
 	outputFilename := filepath.Join(outputDir, filename)
+	if _, mismatch := ReconcileTrackNumber(spotifyTrackNumber, trackInfo.TrackNumber); mismatch {
+		recordTrackNumberMismatch(outputFilename, "tidal", spotifyTrackNumber, trackInfo.TrackNumber)
+	}
 
 	if fileInfo, err := os.Stat(outputFilename); err == nil && fileInfo.Size() > 0 {
 		fmt.Printf("File already exists: %s (%.2f MB)\n", outputFilename, float64(fileInfo.Size())/(1024*1024))
-		return "EXISTS:" + outputFilename, nil
+		return DownloadResult{Path: outputFilename, AlreadyExisted: true}, nil
 	}
 
 	// Request download URL from ALL APIs in parallel - use first success
-	successAPI, downloadURL, err := getDownloadURLParallel(apis, trackInfo.ID, quality)
+	choice, downloadURL, err := getDownloadURLParallel(apis, trackInfo.ID, quality)
 	if err != nil {
-		return "", err
+		return DownloadResult{}, err
 	}
 
 	// Download the file using the successful API
 	fmt.Printf("Downloading to: %s\n", outputFilename)
-	downloader := NewTidalDownloader(successAPI)
-	if err := downloader.DownloadFile(downloadURL, outputFilename); err != nil {
-		return "", fmt.Errorf("download failed: %w", err)
+	downloader := NewTidalDownloader(choice.API)
+	if err := downloadWithFreshURL(downloadURL, func() (string, error) {
+		freshChoice, freshURL, err := getDownloadURLParallel(apis, trackInfo.ID, quality)
+		if err != nil {
+			return "", err
+		}
+		choice = freshChoice
+		downloader = NewTidalDownloader(choice.API)
+		return freshURL, nil
+	}, func(url string) error { return downloader.DownloadFile(url, outputFilename) }); err != nil {
+		return DownloadResult{}, fmt.Errorf("download failed: %w", err)
 	}
+	recordTidalAPIChoice(outputFilename, choice)
 
 	// Success! Add metadata
 	fmt.Println("Adding metadata...")
@@ -1470,10 +1520,10 @@ func (t *TidalDownloader) DownloadBySearchWithFallback(trackName, artistName, al
 
 	fmt.Println("Done")
 	fmt.Println("✓ Downloaded successfully from Tidal (via search)")
-	return outputFilename, nil
+	return DownloadResult{Path: outputFilename}, nil
 }
 
-func (t *TidalDownloader) DownloadWithFallback(spotifyTrackID, outputDir, quality, filenameFormat string, includeTrackNumber bool, position int, spotifyTrackName, spotifyArtistName, spotifyAlbumName, spotifyAlbumArtist, spotifyReleaseDate string, useAlbumTrackNumber bool, spotifyCoverURL string, embedMaxQualityCover bool, spotifyTrackNumber, spotifyDiscNumber, spotifyTotalTracks int, spotifyISRC string) (string, error) {
+func (t *TidalDownloader) DownloadWithFallback(spotifyTrackID, outputDir, quality, filenameFormat string, includeTrackNumber bool, position int, spotifyTrackName, spotifyArtistName, spotifyAlbumName, spotifyAlbumArtist, spotifyReleaseDate string, useAlbumTrackNumber bool, spotifyCoverURL string, embedMaxQualityCover bool, spotifyTrackNumber, spotifyDiscNumber, spotifyTotalTracks int, spotifyISRC string) (DownloadResult, error) {
 	// Get Tidal URL once
 	tidalURL, err := t.GetTidalURLFromSpotify(spotifyTrackID)
 	if err != nil {
@@ -1489,7 +1539,7 @@ func (t *TidalDownloader) DownloadWithFallback(spotifyTrackID, outputDir, qualit
 
 // DownloadWithFallbackAndISRC downloads with ISRC matching for search fallback
 // Uses parallel API requests for faster download
-func (t *TidalDownloader) DownloadWithFallbackAndISRC(spotifyTrackID, spotifyISRC, outputDir, quality, filenameFormat string, includeTrackNumber bool, position int, spotifyTrackName, spotifyArtistName, spotifyAlbumName, spotifyAlbumArtist, spotifyReleaseDate string, useAlbumTrackNumber bool, expectedDuration int, spotifyCoverURL string, embedMaxQualityCover bool, spotifyTrackNumber, spotifyDiscNumber, spotifyTotalTracks int) (string, error) {
+func (t *TidalDownloader) DownloadWithFallbackAndISRC(spotifyTrackID, spotifyISRC, outputDir, quality, filenameFormat string, includeTrackNumber bool, position int, spotifyTrackName, spotifyArtistName, spotifyAlbumName, spotifyAlbumArtist, spotifyReleaseDate string, useAlbumTrackNumber bool, expectedDuration int, spotifyCoverURL string, embedMaxQualityCover bool, spotifyTrackNumber, spotifyDiscNumber, spotifyTotalTracks int) (DownloadResult, error) {
 	// Get Tidal URL once
 	tidalURL, err := t.GetTidalURLFromSpotify(spotifyTrackID)
 	if err != nil {
@@ -0,0 +1,100 @@
+package backend
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// tidalQualityGrace is how long "auto" keeps waiting for a better-quality API after the first
+// one answers
+const tidalQualityGrace = 3 * time.Second
+
+// TidalAPIChoice is the API a Tidal download used and the quality it served
+type TidalAPIChoice struct {
+	API          string `json:"api"`
+	AudioQuality string `json:"audio_quality,omitempty"` // Empty for v1 APIs, which don't report it
+	BitDepth     int    `json:"bit_depth,omitempty"`
+	SampleRate   int    `json:"sample_rate,omitempty"`
+}
+
+var (
+	tidalPreferBestQuality     bool
+	tidalPreferBestQualityLock sync.RWMutex
+
+	tidalAPIChoices     = make(map[string]TidalAPIChoice)
+	tidalAPIChoicesLock sync.Mutex
+)
+
+// SetTidalPreferBestQuality makes "auto" API selection compare every API's answer and use the
+// highest quality instead of the first one to respond
+func SetTidalPreferBestQuality(enabled bool) {
+	tidalPreferBestQualityLock.Lock()
+	tidalPreferBestQuality = enabled
+	tidalPreferBestQualityLock.Unlock()
+}
+
+func isTidalPreferBestQualityEnabled() bool {
+	tidalPreferBestQualityLock.RLock()
+	defer tidalPreferBestQualityLock.RUnlock()
+	return tidalPreferBestQuality
+}
+
+// tidalQualityTier ranks Tidal's quality names; unknown (v1) answers rank lowest
+func tidalQualityTier(audioQuality string) int {
+	switch audioQuality {
+	case "HI_RES_LOSSLESS":
+		return 4
+	case "LOSSLESS", "HI_RES":
+		return 3
+	case "HIGH":
+		return 2
+	case "LOW":
+		return 1
+	}
+	return 0
+}
+
+// score orders successful answers by tier, then bit depth, then sample rate
+func (r manifestResult) score() int64 {
+	return int64(tidalQualityTier(r.audioQuality))*1_000_000_000 + int64(r.bitDepth)*1_000_000 + int64(r.sampleRate)
+}
+
+// meetsRequested reports whether an answer already has the requested quality, so there is
+// nothing better to wait for
+func (r manifestResult) meetsRequested(quality string) bool {
+	return r.audioQuality != "" && tidalQualityTier(r.audioQuality) >= tidalQualityTier(quality)
+}
+
+func (r manifestResult) choice() TidalAPIChoice {
+	return TidalAPIChoice{API: r.apiURL, AudioQuality: r.audioQuality, BitDepth: r.bitDepth, SampleRate: r.sampleRate}
+}
+
+func (c TidalAPIChoice) label() string {
+	if c.AudioQuality == "" {
+		return "quality not reported"
+	}
+	if c.BitDepth > 0 && c.SampleRate > 0 {
+		return fmt.Sprintf("%s, %s", c.AudioQuality, qualityLabel(c.BitDepth, c.SampleRate))
+	}
+	return c.AudioQuality
+}
+
+// recordTidalAPIChoice remembers which API and quality produced a downloaded file
+func recordTidalAPIChoice(filePath string, choice TidalAPIChoice) {
+	tidalAPIChoicesLock.Lock()
+	tidalAPIChoices[filePath] = choice
+	tidalAPIChoicesLock.Unlock()
+}
+
+// TakeTidalAPIChoice returns and clears the API choice recorded for a downloaded file, if any
+func TakeTidalAPIChoice(filePath string) (TidalAPIChoice, bool) {
+	tidalAPIChoicesLock.Lock()
+	defer tidalAPIChoicesLock.Unlock()
+
+	choice, ok := tidalAPIChoices[filePath]
+	if ok {
+		delete(tidalAPIChoices, filePath)
+	}
+	return choice, ok
+}
@@ -0,0 +1,101 @@
+package backend
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Title collision modes decide what happens when two tracks of one album share a title
+const (
+	TitleCollisionTrackNumber = "track_number" // Put the track (and disc) number in the colliding file's name
+	TitleCollisionNone        = "none"         // Keep the configured format; the later track is reported as existing
+)
+
+var (
+	titleCollisionMode     = TitleCollisionTrackNumber
+	titleCollisionModeLock sync.RWMutex
+
+	// album key -> normalized title -> Spotify ID of the first track seen with it
+	albumTrackTitles     = make(map[string]map[string]string)
+	albumTrackTitlesLock sync.Mutex
+)
+
+// SetTitleCollisionMode sets how same-titled tracks on one album are kept apart; empty means track_number
+func SetTitleCollisionMode(mode string) error {
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	if mode == "" {
+		mode = TitleCollisionTrackNumber
+	}
+	if mode != TitleCollisionTrackNumber && mode != TitleCollisionNone {
+		return fmt.Errorf("unknown title collision mode: %s", mode)
+	}
+
+	titleCollisionModeLock.Lock()
+	titleCollisionMode = mode
+	titleCollisionModeLock.Unlock()
+	return nil
+}
+
+func getTitleCollisionMode() string {
+	titleCollisionModeLock.RLock()
+	defer titleCollisionModeLock.RUnlock()
+	return titleCollisionMode
+}
+
+// ClaimAlbumTrackTitle registers a track's title for its album and reports whether a different
+// track of the same album already used it. Albums are keyed by Spotify album ID, or by name
+// and album artist when no ID is known.
+func ClaimAlbumTrackTitle(albumID, albumName, albumArtist, spotifyID, title string) bool {
+	if getTitleCollisionMode() == TitleCollisionNone || spotifyID == "" || title == "" {
+		return false
+	}
+
+	albumKey := albumID
+	if albumKey == "" {
+		if albumName == "" {
+			return false
+		}
+		albumKey = normalizeMatchKey(albumName) + "\x00" + normalizeMatchKey(albumArtist)
+	}
+	titleKey := strings.ToLower(sanitizeFilename(title))
+
+	albumTrackTitlesLock.Lock()
+	defer albumTrackTitlesLock.Unlock()
+
+	titles, ok := albumTrackTitles[albumKey]
+	if !ok {
+		titles = make(map[string]string)
+		albumTrackTitles[albumKey] = titles
+	}
+	owner, claimed := titles[titleKey]
+	if !claimed {
+		titles[titleKey] = spotifyID
+		return false
+	}
+	return owner != spotifyID
+}
+
+// CollisionSafeFilenameFormat turns a filename format into a template that includes the track
+// number, and the disc number on multi-disc albums, so same-titled tracks get distinct names
+func CollisionSafeFilenameFormat(filenameFormat string, discNumber int) string {
+	template := filenameFormat
+	if !strings.Contains(template, "{") {
+		switch filenameFormat {
+		case "artist-title":
+			template = "{artist} - {title}"
+		case "title":
+			template = "{title}"
+		default: // "title-artist"
+			template = "{title} - {artist}"
+		}
+	}
+
+	if !strings.Contains(template, "{track}") {
+		template = "{track}. " + template
+	}
+	if discNumber > 1 && !strings.Contains(template, "{disc}") {
+		template = strings.Replace(template, "{track}", "{disc}-{track}", 1)
+	}
+	return template
+}
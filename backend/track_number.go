@@ -0,0 +1,82 @@
+package backend
+
+import (
+	"fmt"
+	"sync"
+)
+
+// TrackNumberMismatch describes a disagreement between Spotify's album numbering and the service's
+type TrackNumberMismatch struct {
+	FilePath      string `json:"file_path"`
+	SpotifyNumber int    `json:"spotify_number"`
+	ServiceNumber int    `json:"service_number"`
+	Service       string `json:"service"`
+}
+
+var (
+	trackNumberTolerance     int
+	trackNumberToleranceLock sync.RWMutex
+
+	trackNumberMismatches   = make(map[string]TrackNumberMismatch)
+	trackNumberMismatchLock sync.Mutex
+)
+
+// SetTrackNumberTolerance sets how far a service track number may drift from Spotify's before Spotify's is used
+func SetTrackNumberTolerance(tolerance int) {
+	if tolerance < 0 {
+		tolerance = 0
+	}
+	trackNumberToleranceLock.Lock()
+	trackNumberTolerance = tolerance
+	trackNumberToleranceLock.Unlock()
+}
+
+// GetTrackNumberTolerance returns the configured track number tolerance
+func GetTrackNumberTolerance() int {
+	trackNumberToleranceLock.RLock()
+	defer trackNumberToleranceLock.RUnlock()
+	return trackNumberTolerance
+}
+
+// ReconcileTrackNumber cross-checks the service track number against Spotify's.
+// Returns the number to use and whether the two differ by more than the tolerance,
+// in which case Spotify's numbering wins.
+func ReconcileTrackNumber(spotifyNumber, serviceNumber int) (int, bool) {
+	if spotifyNumber <= 0 {
+		return serviceNumber, false
+	}
+	if serviceNumber <= 0 {
+		return spotifyNumber, false
+	}
+
+	if absInt(spotifyNumber-serviceNumber) > GetTrackNumberTolerance() {
+		fmt.Printf("[Track Number] Mismatch: Spotify #%d vs service #%d, using Spotify numbering\n", spotifyNumber, serviceNumber)
+		return spotifyNumber, true
+	}
+	return serviceNumber, false
+}
+
+// recordTrackNumberMismatch remembers a mismatch for the given output file so the caller can report it
+func recordTrackNumberMismatch(filePath, service string, spotifyNumber, serviceNumber int) {
+	trackNumberMismatchLock.Lock()
+	defer trackNumberMismatchLock.Unlock()
+
+	trackNumberMismatches[filePath] = TrackNumberMismatch{
+		FilePath:      filePath,
+		SpotifyNumber: spotifyNumber,
+		ServiceNumber: serviceNumber,
+		Service:       service,
+	}
+}
+
+// TakeTrackNumberMismatch returns and clears the mismatch recorded for a downloaded file, if any
+func TakeTrackNumberMismatch(filePath string) (TrackNumberMismatch, bool) {
+	trackNumberMismatchLock.Lock()
+	defer trackNumberMismatchLock.Unlock()
+
+	mismatch, ok := trackNumberMismatches[filePath]
+	if ok {
+		delete(trackNumberMismatches, filePath)
+	}
+	return mismatch, ok
+}
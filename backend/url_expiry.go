@@ -0,0 +1,76 @@
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+const defaultURLRefreshRetries = 2
+
+var (
+	urlRefreshRetries     = defaultURLRefreshRetries
+	urlRefreshRetriesLock sync.RWMutex
+)
+
+// SetURLRefreshRetries sets how many times an expired signed download URL is re-resolved before
+// the download fails (0 = never, negative = default)
+func SetURLRefreshRetries(retries int) {
+	if retries < 0 {
+		retries = defaultURLRefreshRetries
+	}
+
+	urlRefreshRetriesLock.Lock()
+	urlRefreshRetries = retries
+	urlRefreshRetriesLock.Unlock()
+}
+
+func getURLRefreshRetries() int {
+	urlRefreshRetriesLock.RLock()
+	defer urlRefreshRetriesLock.RUnlock()
+	return urlRefreshRetries
+}
+
+// ExpiredURLError is returned when the CDN rejects a download URL because its signature or
+// token has run out. The status is checked on the final response, after redirects.
+type ExpiredURLError struct {
+	StatusCode int
+}
+
+func (e *ExpiredURLError) Error() string {
+	return fmt.Sprintf("download URL expired or was rejected (status %d)", e.StatusCode)
+}
+
+// downloadStatusError turns a non-200 transfer response into an error, flagging the statuses
+// signed CDN URLs answer with once they expire
+func downloadStatusError(statusCode int) error {
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusGone:
+		return &ExpiredURLError{StatusCode: statusCode}
+	}
+	return fmt.Errorf("download failed with status %d", statusCode)
+}
+
+// isExpiredURLError reports whether err means the download URL has to be resolved again
+func isExpiredURLError(err error) bool {
+	var expired *ExpiredURLError
+	return errors.As(err, &expired)
+}
+
+// downloadWithFreshURL runs download with url. When the URL turns out to have expired, a new one
+// is fetched with resolve and the download retried, instead of failing tracks that waited in a
+// long queue.
+func downloadWithFreshURL(url string, resolve func() (string, error), download func(url string) error) error {
+	retries := getURLRefreshRetries()
+	err := download(url)
+	for attempt := 1; attempt <= retries && isExpiredURLError(err); attempt++ {
+		fmt.Printf("[Download] %v, resolving a fresh URL (%d/%d)\n", err, attempt, retries)
+		freshURL, resolveErr := resolve()
+		if resolveErr != nil {
+			return fmt.Errorf("%v; re-resolving the URL failed: %w", err, resolveErr)
+		}
+		err = download(freshURL)
+	}
+	return err
+}
@@ -0,0 +1,90 @@
+package backend
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultExcludedVersionKeywords are the alternate-version markers offered when the filter is enabled
+var DefaultExcludedVersionKeywords = []string{"live", "acoustic", "remix", "instrumental"}
+
+// VersionSkippedTrack is a track dropped by the version filter and the keyword that matched it
+type VersionSkippedTrack struct {
+	Track   AlbumTrackMetadata `json:"track"`
+	Keyword string             `json:"keyword"`
+}
+
+// VersionFilterResult represents the result of filtering tracks by version keywords
+type VersionFilterResult struct {
+	Tracks        []AlbumTrackMetadata  `json:"tracks"`
+	TrackCount    int                   `json:"track_count"`
+	SkippedCount  int                   `json:"skipped_count"`
+	SkippedTracks []VersionSkippedTrack `json:"skipped_tracks,omitempty"`
+	Keywords      []string              `json:"keywords"`
+}
+
+func normalizeVersionKeyword(keyword string) string {
+	return strings.TrimSpace(strings.ToLower(keyword))
+}
+
+// compileVersionKeywords builds whole-word, case-insensitive patterns so "live" doesn't match "Alive"
+func compileVersionKeywords(keywords []string) map[string]*regexp.Regexp {
+	patterns := make(map[string]*regexp.Regexp)
+	for _, keyword := range keywords {
+		keyword = normalizeVersionKeyword(keyword)
+		if keyword == "" {
+			continue
+		}
+		if _, exists := patterns[keyword]; exists {
+			continue
+		}
+		patterns[keyword] = regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(keyword) + `\b`)
+	}
+	return patterns
+}
+
+// matchVersionKeyword returns the first excluded keyword found in a title, or empty if none
+func matchVersionKeyword(title string, keywords []string, patterns map[string]*regexp.Regexp) string {
+	for _, keyword := range keywords {
+		keyword = normalizeVersionKeyword(keyword)
+		if re, ok := patterns[keyword]; ok && re.MatchString(title) {
+			return keyword
+		}
+	}
+	return ""
+}
+
+// FilterTracksByVersionKeywords drops tracks whose titles contain any excluded keyword
+// (e.g. live/acoustic/remix). An empty keyword list disables the filter.
+func FilterTracksByVersionKeywords(tracks []AlbumTrackMetadata, keywords []string) VersionFilterResult {
+	result := VersionFilterResult{
+		Keywords: keywords,
+	}
+
+	patterns := compileVersionKeywords(keywords)
+	if len(patterns) == 0 {
+		result.Tracks = tracks
+		result.TrackCount = len(tracks)
+		return result
+	}
+
+	kept := make([]AlbumTrackMetadata, 0, len(tracks))
+	for _, track := range tracks {
+		if matched := matchVersionKeyword(track.Name, keywords, patterns); matched != "" {
+			fmt.Printf("[Version Filter] Skipping %s version: %s - %s\n", matched, track.Name, track.Artists)
+			result.SkippedTracks = append(result.SkippedTracks, VersionSkippedTrack{
+				Track:   track,
+				Keyword: matched,
+			})
+			continue
+		}
+		kept = append(kept, track)
+	}
+
+	result.Tracks = kept
+	result.TrackCount = len(kept)
+	result.SkippedCount = len(result.SkippedTracks)
+	fmt.Printf("[Version Filter] Kept %d, skipped %d (keywords: %s)\n", result.TrackCount, result.SkippedCount, strings.Join(keywords, ", "))
+	return result
+}
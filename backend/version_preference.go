@@ -0,0 +1,268 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Track version preferences
+const (
+	VersionPreferAlbum  = "album"
+	VersionPreferSingle = "single"
+)
+
+// Album edition preferences
+const (
+	EditionOriginal   = "original"
+	EditionRemastered = "remastered"
+	EditionDeluxe     = "deluxe"
+)
+
+var (
+	remasterPattern = regexp.MustCompile(`(?i)\bremaster(ed)?\b`)
+	deluxePattern   = regexp.MustCompile(`(?i)\b(deluxe|expanded|anniversary|special edition|collector'?s edition|bonus track)`)
+)
+
+// TrackVersionResult describes which release of a track was chosen
+type TrackVersionResult struct {
+	Track     TrackMetadata `json:"track"`
+	AlbumType string        `json:"album_type"`
+	Chosen    string        `json:"chosen"`  // "album", "single" or "original" when no better match exists
+	Changed   bool          `json:"changed"` // True if a different Spotify track than requested was picked
+}
+
+// EditionCandidate is one edition of a track found on Spotify
+type EditionCandidate struct {
+	SpotifyID   string `json:"spotify_id"`
+	AlbumName   string `json:"album_name"`
+	ReleaseDate string `json:"release_date"`
+	Edition     string `json:"edition"`
+}
+
+// TrackEditionResult describes which album edition of a track was chosen
+type TrackEditionResult struct {
+	Track      TrackMetadata      `json:"track"`
+	Edition    string             `json:"edition"`
+	Changed    bool               `json:"changed"`
+	Candidates []EditionCandidate `json:"candidates"`
+}
+
+// searchTrackCandidates searches Spotify for other releases of the same recording,
+// matching by ISRC or by title, first artist and duration within toleranceMs
+func searchTrackCandidates(ctx context.Context, client *SpotifyMetadataClient, token string, original *trackFull, toleranceMs int) []string {
+	queries := make([]string, 0, 2)
+	if original.ExternalID.ISRC != "" {
+		queries = append(queries, "isrc:"+original.ExternalID.ISRC)
+	}
+	if len(original.Artists) > 0 {
+		queries = append(queries, fmt.Sprintf("track:%s artist:%s", stripEditionSuffix(original.Name), original.Artists[0].Name))
+	}
+
+	seen := map[string]bool{original.ID: true}
+	ids := make([]string, 0)
+	for _, query := range queries {
+		searchURL := fmt.Sprintf("https://api.spotify.com/v1/search?q=%s&type=track&limit=20", url.QueryEscape(query))
+		var resp searchTracksResponse
+		if err := client.getJSON(ctx, searchURL, token, &resp); err != nil {
+			fmt.Printf("[Version] Search failed for '%s': %v\n", query, err)
+			continue
+		}
+
+		for _, item := range resp.Tracks.Items {
+			if seen[item.ID] {
+				continue
+			}
+
+			sameISRC := original.ExternalID.ISRC != "" && strings.EqualFold(item.ExternalID.ISRC, original.ExternalID.ISRC)
+			sameTrack := normalizeMatchKey(stripEditionSuffix(item.Name)) == normalizeMatchKey(stripEditionSuffix(original.Name)) &&
+				len(item.Artists) > 0 && len(original.Artists) > 0 &&
+				strings.EqualFold(item.Artists[0].Name, original.Artists[0].Name) &&
+				absInt(item.DurationMS-original.DurationMS) <= toleranceMs
+			if !sameISRC && !sameTrack {
+				continue
+			}
+
+			seen[item.ID] = true
+			ids = append(ids, item.ID)
+		}
+	}
+	return ids
+}
+
+// ResolveTrackVersion picks the album or single release of a track according to prefer.
+// The Spotify album context of the requested track is used first; if it doesn't match the
+// preference, Spotify search candidates with the same ISRC or title/artist/duration are tried.
+func ResolveTrackVersion(ctx context.Context, spotifyTrackID string, prefer string) (*TrackVersionResult, error) {
+	client := NewSpotifyMetadataClient()
+
+	token, err := client.getAccessToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access token: %w", err)
+	}
+
+	original, err := client.fetchTrack(ctx, spotifyTrackID, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch track: %w", err)
+	}
+
+	result := &TrackVersionResult{
+		Track:     formatTrackData(original).Track,
+		AlbumType: original.Album.AlbumType,
+		Chosen:    "original",
+	}
+
+	prefer = strings.ToLower(strings.TrimSpace(prefer))
+	if prefer != VersionPreferAlbum && prefer != VersionPreferSingle {
+		return result, nil
+	}
+
+	if versionMatchesPreference(original.Album.AlbumType, prefer) {
+		result.Chosen = prefer
+		return result, nil
+	}
+
+	for _, id := range searchTrackCandidates(ctx, client, token, original, 3000) {
+		candidate, err := client.fetchTrack(ctx, id, token)
+		if err != nil || !versionMatchesPreference(candidate.Album.AlbumType, prefer) {
+			continue
+		}
+
+		fmt.Printf("[Version] Using %s version from '%s' instead of '%s'\n", prefer, candidate.Album.Name, original.Album.Name)
+		result.Track = formatTrackData(candidate).Track
+		result.AlbumType = candidate.Album.AlbumType
+		result.Chosen = prefer
+		result.Changed = true
+		return result, nil
+	}
+
+	fmt.Printf("[Version] No %s version found for '%s', keeping original\n", prefer, original.Name)
+	return result, nil
+}
+
+// ResolveTrackEdition picks an album edition of a track: "original", "remastered", "deluxe",
+// or any other keyword to match in the album name. When preferYear > 0 the edition released
+// closest to that year wins among equally matching candidates.
+func ResolveTrackEdition(ctx context.Context, spotifyTrackID string, prefer string, preferYear int) (*TrackEditionResult, error) {
+	client := NewSpotifyMetadataClient()
+
+	token, err := client.getAccessToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access token: %w", err)
+	}
+
+	original, err := client.fetchTrack(ctx, spotifyTrackID, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch track: %w", err)
+	}
+
+	candidates := []*trackFull{original}
+	// Remasters are often a few seconds longer or shorter than the original master
+	for _, id := range searchTrackCandidates(ctx, client, token, original, 10000) {
+		if candidate, err := client.fetchTrack(ctx, id, token); err == nil {
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	result := &TrackEditionResult{
+		Candidates: make([]EditionCandidate, 0, len(candidates)),
+	}
+	for _, c := range candidates {
+		result.Candidates = append(result.Candidates, EditionCandidate{
+			SpotifyID:   c.ID,
+			AlbumName:   c.Album.Name,
+			ReleaseDate: c.Album.ReleaseDate,
+			Edition:     DetectEdition(c.Album.Name + " " + c.Name),
+		})
+	}
+
+	prefer = strings.ToLower(strings.TrimSpace(prefer))
+	best := original
+	bestScore := editionScore(original, prefer, preferYear)
+	for _, c := range candidates[1:] {
+		if score := editionScore(c, prefer, preferYear); score > bestScore {
+			best = c
+			bestScore = score
+		}
+	}
+
+	result.Track = formatTrackData(best).Track
+	result.Edition = DetectEdition(best.Album.Name + " " + best.Name)
+	result.Changed = best.ID != original.ID
+	if result.Changed {
+		fmt.Printf("[Edition] Using '%s' (%s) instead of '%s'\n", best.Album.Name, result.Edition, original.Album.Name)
+	}
+	return result, nil
+}
+
+// DetectEdition classifies an album or track title as "remastered", "deluxe" or "original"
+func DetectEdition(name string) string {
+	switch {
+	case deluxePattern.MatchString(name):
+		return EditionDeluxe
+	case remasterPattern.MatchString(name):
+		return EditionRemastered
+	default:
+		return EditionOriginal
+	}
+}
+
+// editionScore ranks a candidate for the given preference; higher is better
+func editionScore(t *trackFull, prefer string, preferYear int) int {
+	name := t.Album.Name + " " + t.Name
+	score := 0
+
+	switch prefer {
+	case "":
+	case EditionOriginal, EditionRemastered, EditionDeluxe:
+		if DetectEdition(name) == prefer {
+			score += 1000
+		}
+	default:
+		if strings.Contains(strings.ToLower(name), prefer) {
+			score += 1000
+		}
+	}
+
+	if preferYear > 0 && len(t.Album.ReleaseDate) >= 4 {
+		if year, err := strconv.Atoi(t.Album.ReleaseDate[:4]); err == nil {
+			score -= absInt(year - preferYear)
+		}
+	}
+
+	// Prefer regular album releases over compilations for the same edition
+	if t.Album.AlbumType == "album" {
+		score++
+	}
+	return score
+}
+
+// stripEditionSuffix removes " - Remastered 2011" style suffixes from a track title
+func stripEditionSuffix(name string) string {
+	if idx := strings.Index(name, " - "); idx > 0 {
+		suffix := name[idx:]
+		if remasterPattern.MatchString(suffix) || deluxePattern.MatchString(suffix) || strings.Contains(strings.ToLower(suffix), "version") {
+			return strings.TrimSpace(name[:idx])
+		}
+	}
+	return name
+}
+
+// versionMatchesPreference reports whether a Spotify album_type satisfies the preference
+func versionMatchesPreference(albumType, prefer string) bool {
+	albumType = strings.ToLower(albumType)
+	if prefer == VersionPreferAlbum {
+		return albumType == "album" || albumType == "compilation"
+	}
+	return albumType == "single"
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}